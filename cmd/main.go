@@ -1,19 +1,36 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
 
 	"github.com/sanmu2018/word-hero/internal/conf"
 	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/graph"
+	"github.com/sanmu2018/word-hero/internal/grpcserver"
+	"github.com/sanmu2018/word-hero/internal/mailer"
 	"github.com/sanmu2018/word-hero/internal/middleware"
 	"github.com/sanmu2018/word-hero/internal/router"
 	"github.com/sanmu2018/word-hero/internal/service"
 	"github.com/sanmu2018/word-hero/internal/utils"
 	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pubsub"
+	"github.com/sanmu2018/word-hero/pkg/ratelimit"
 )
 
+// devMode allows insecure defaults that must never reach production
+var devMode = flag.Bool("dev", false, "allow insecure development defaults such as the sample JWT secret")
+
 func main() {
+	flag.Parse()
+
 	// Load configuration
 	config, err := conf.LoadConfig()
 	if err != nil {
@@ -21,6 +38,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	if config.JWT.Secret == conf.DefaultJWTSecret && !isDevMode() {
+		log.Fatal().Msg("Refusing to start with the default JWT secret: change or remove jwt.secret (it may be empty when jwt.keys are configured), or run with --dev (WORD_HERO_DEV=1) for local development")
+	}
+
 	port := config.Server.Port
 
 	// Initialize logger
@@ -66,27 +87,94 @@ func main() {
 	// Initialize authentication services
 	log.Info().Msg("Initializing authentication services...")
 	userDAO := dao.NewUserDAO()
-	jwtUtils := utils.NewJWTUtils(&config.JWT)
-	authService := service.NewAuthService(userDAO, jwtUtils)
+	userTokenDAO := dao.NewUserTokenDAO()
+	recoveryCodeDAO := dao.NewRecoveryCodeDAO()
+	settingDAO := dao.NewSettingDAO()
+	jwtUtils, err := utils.NewJWTUtils(&config.JWT)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load JWT keys")
+	}
+	rateLimitStore, err := newRateLimitStore(&config.RateLimit)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize rate limiter")
+	}
+	loginGuard, err := service.NewLoginGuard(rateLimitStore, &config.RateLimit.Login)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize login guard")
+	}
+	mailSender, err := mailer.NewSender(&config.Mail)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize mail sender")
+	}
+	authService := service.NewAuthService(userDAO, userTokenDAO, recoveryCodeDAO, settingDAO, jwtUtils, loginGuard, mailSender, &config.Auth, config.Mail.BaseURL)
+	userIdentityDAO := dao.NewUserIdentityDAO()
+	if config.LDAP.Enabled {
+		ldapAuthenticator, err := service.NewLDAPAuthenticator(&config.LDAP, authService, userDAO, userIdentityDAO, settingDAO)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize LDAP authentication")
+		}
+		authService.SetAuthenticators(service.NewPasswordAuthenticator(userDAO), ldapAuthenticator)
+		log.Info().Str("url", config.LDAP.URL).Msg("LDAP authentication enabled")
+	}
 	userService := service.NewUserService(userDAO)
-	authMiddleware := middleware.NewAuthMiddleware(authService)
+	oidcService, err := service.NewOIDCService(config.Auth.OIDC, config.Mail.BaseURL, authService, userDAO, userIdentityDAO)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize OIDC login")
+	}
+	accessTokenDAO := dao.NewPersonalAccessTokenDAO()
+	accessTokenService := service.NewAccessTokenService(accessTokenDAO, userDAO)
+	guestDAO := dao.NewGuestDAO()
+	guestService, err := service.NewGuestService(guestDAO, wordTagDAO, jwtUtils, &config.Auth)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize guest sessions")
+	}
+	authMiddleware := middleware.NewAuthMiddleware(authService, accessTokenService, guestService)
+	accountService, err := service.NewAccountService(authService, userDAO, wordTagDAO, userIdentityDAO, accessTokenDAO, recoveryCodeDAO, guestDAO, &config.Auth)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize account service")
+	}
+	accountService.StartPurger()
 
 	// Initialize service layer
 	pagerService := service.NewPagerService()
 	vocabularyService := service.NewVocabularyService(wordDAO, wordTagDAO)
-	wordTagService := service.NewWordTagService(wordTagDAO, wordDAO, userDAO, vocabularyService)
+	eventBroker, err := newEventBroker(&config.Events)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize event broker")
+	}
+	eventService := service.NewEventService(eventBroker, config.Events.BufferSize)
+	eventService.Start()
+	wordTagService := service.NewWordTagService(wordTagDAO, wordDAO, userDAO, guestDAO, vocabularyService, eventService)
+	syncOperationDAO := dao.NewSyncOperationDAO()
+	syncService := service.NewSyncService(syncOperationDAO, wordDAO, wordTagService)
+	syncService.StartPurger()
 
 	// Set service dependencies
 	pagerService.SetVocabularyService(vocabularyService)
+	suggestService := service.NewSuggestService(wordDAO)
+	suggestService.Start()
+	vocabularyService.SetSuggestService(suggestService)
 
 	// Initialize router layer
-	webServer := router.NewWebServer(vocabularyService, pagerService, authService, userService, wordTagService, authMiddleware, "web/templates")
+	wordBookName := strings.TrimSuffix(filepath.Base(config.App.ExcelFile), filepath.Ext(config.App.ExcelFile))
+	graphQLServer := graph.NewServer(vocabularyService, wordTagService, wordBookName, &config.GraphQL)
+	webServer := router.NewWebServer(vocabularyService, suggestService, pagerService, authService, oidcService, accessTokenService, guestService, accountService, userService, wordTagService, eventService, syncService, graphQLServer, authMiddleware, "web/templates")
 
 	// Show database info
 	log.Info().Str("database", config.Database.DBName).Msg("Database Information:")
 	log.Info().Str("host", config.Database.Host).Str("port", strconv.Itoa(config.Database.Port)).Msg("Database connection")
 	log.Info().Int64("totalWords", totalWords).Msg("Available vocabulary words")
 
+	// Start the gRPC server for internal tools
+	if config.GRPC.Port > 0 {
+		grpcServer := grpcserver.NewServer(vocabularyService, wordTagService, authService, accessTokenService, config.GRPC.Reflection)
+		go func() {
+			if err := grpcServer.Start(config.GRPC.Port); err != nil {
+				log.Fatal().Err(err).Msg("Failed to start gRPC server")
+			}
+		}()
+	}
+
 	log.Info().Msg("Press Ctrl+C to stop the server")
 
 	// Start the web server
@@ -98,3 +186,61 @@ func main() {
 func Init() error {
 	return nil
 }
+
+// isDevMode reports whether the --dev flag or WORD_HERO_DEV is set
+func isDevMode() bool {
+	if *devMode {
+		return true
+	}
+	dev, _ := strconv.ParseBool(os.Getenv("WORD_HERO_DEV"))
+	return dev
+}
+
+// newRateLimitStore creates the rate limit backend selected in configuration
+func newRateLimitStore(config *conf.RateLimitConfig) (ratelimit.Store, error) {
+	switch config.Backend {
+	case "", "memory":
+		log.Info().Msg("Using in-memory rate limiter")
+		return ratelimit.NewMemoryStore(), nil
+	case "redis":
+		client, err := newRedisClient(&config.Redis)
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Str("addr", config.Redis.Addr).Msg("Using redis rate limiter")
+		return ratelimit.NewRedisStore(client, config.Redis.KeyPrefix), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend: %s", config.Backend)
+	}
+}
+
+// newEventBroker creates the event broker selected in configuration
+func newEventBroker(config *conf.EventsConfig) (pubsub.Broker, error) {
+	switch config.Backend {
+	case "", "memory":
+		log.Info().Msg("Using in-memory event broker")
+		return pubsub.NewMemoryBroker(), nil
+	case "redis":
+		client, err := newRedisClient(&config.Redis)
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Str("addr", config.Redis.Addr).Msg("Using redis event broker")
+		return pubsub.NewRedisBroker(client, config.Redis.KeyPrefix), nil
+	default:
+		return nil, fmt.Errorf("unknown event backend: %s", config.Backend)
+	}
+}
+
+// newRedisClient connects to the Redis server of config
+func newRedisClient(config *conf.RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Addr,
+		Password: config.Password,
+		DB:       config.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", config.Addr, err)
+	}
+	return client, nil
+}
@@ -11,10 +11,17 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	App      AppConfig      `yaml:"app"`
-	Database DatabaseConfig `yaml:"database"`
-	JWT      JWTConfig      `yaml:"jwt"`
+	Server    ServerConfig    `yaml:"server"`
+	GRPC      GRPCConfig      `yaml:"grpc"`
+	GraphQL   GraphQLConfig   `yaml:"graphql"`
+	App       AppConfig       `yaml:"app"`
+	Database  DatabaseConfig  `yaml:"database"`
+	JWT       JWTConfig       `yaml:"jwt"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Events    EventsConfig    `yaml:"events"`
+	Auth      AuthConfig      `yaml:"auth"`
+	LDAP      LDAPConfig      `yaml:"ldap"`
+	Mail      MailConfig      `yaml:"mail"`
 }
 
 // ServerConfig represents server configuration
@@ -23,6 +30,18 @@ type ServerConfig struct {
 	Host string `yaml:"host"`
 }
 
+// GRPCConfig represents the gRPC server configuration
+type GRPCConfig struct {
+	Port       int  `yaml:"port"`       // 0 disables the gRPC server
+	Reflection bool `yaml:"reflection"` // lets tools such as grpcurl list the services
+}
+
+// GraphQLConfig represents the limits of GraphQL queries
+type GraphQLConfig struct {
+	MaxDepth      int `yaml:"max_depth"`      // nesting of fields
+	MaxComplexity int `yaml:"max_complexity"` // fields to resolve, lists count once per item of a page
+}
+
 // AppConfig represents application configuration
 type AppConfig struct {
 	ExcelFile string `yaml:"excel_file"`
@@ -43,10 +62,116 @@ type DatabaseConfig struct {
 	ConnMaxLifetime int    `yaml:"conn_max_lifetime"`
 }
 
+// DefaultJWTSecret is the placeholder secret shipped in the sample
+// configuration. The server refuses to start with it outside dev mode.
+const DefaultJWTSecret = "your-secret-key-change-in-production"
+
 // JWTConfig represents JWT configuration
 type JWTConfig struct {
+	// Secret signs HS256 tokens without a kid. It may be left empty when keys are configured.
 	Secret    string `yaml:"secret"`
 	ExpiresIn string `yaml:"expires_in"`
+	// SigningKey is the kid of the key that signs new tokens; defaults to the
+	// first key with a private key, then to Secret
+	SigningKey string         `yaml:"signing_key"`
+	Keys       []JWTKeyConfig `yaml:"keys"`
+}
+
+// JWTKeyConfig represents an asymmetric JWT key. Keys with only a public key
+// file still verify tokens, which lets a retired key phase out after rotation.
+type JWTKeyConfig struct {
+	ID             string `yaml:"id"`
+	Algorithm      string `yaml:"algorithm"` // RS256 | EdDSA
+	PrivateKeyFile string `yaml:"private_key_file"`
+	PublicKeyFile  string `yaml:"public_key_file"`
+}
+
+// AuthConfig represents account and authentication policy configuration
+type AuthConfig struct {
+	RequireEmailVerification bool                 `yaml:"require_email_verification"`
+	VerificationTTL          string               `yaml:"verification_ttl"`
+	PasswordResetTTL         string               `yaml:"password_reset_ttl"`
+	GuestTTL                 string               `yaml:"guest_ttl"`
+	DeletionGracePeriod      string               `yaml:"deletion_grace_period"`
+	OIDC                     []OIDCProviderConfig `yaml:"oidc"`
+}
+
+// OIDCProviderConfig represents an external OpenID Connect identity provider
+type OIDCProviderConfig struct {
+	Name         string   `yaml:"name"` // used in URLs, e.g. /api/auth/oidc/<name>/login
+	DisplayName  string   `yaml:"display_name"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	Scopes       []string `yaml:"scopes"`
+	RedirectURL  string   `yaml:"redirect_url"` // defaults to <mail.base_url>/api/auth/oidc/<name>/callback
+}
+
+// LDAPConfig represents the LDAP authentication backend configuration
+type LDAPConfig struct {
+	Enabled            bool              `yaml:"enabled"`
+	URL                string            `yaml:"url"` // ldap://host:389 or ldaps://host:636
+	StartTLS           bool              `yaml:"start_tls"`
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify"`
+	BindDNTemplate     string            `yaml:"bind_dn_template"` // %s is replaced by the escaped username
+	GroupBaseDN        string            `yaml:"group_base_dn"`    // when empty, the memberOf attribute is used
+	GroupFilter        string            `yaml:"group_filter"`     // %s is replaced by the escaped user DN
+	EmailAttribute     string            `yaml:"email_attribute"`
+	NameAttribute      string            `yaml:"name_attribute"`
+	GroupRoles         map[string]string `yaml:"group_roles"` // group DN or cn -> role
+	DefaultRole        string            `yaml:"default_role"`
+	Timeout            string            `yaml:"timeout"`
+}
+
+// MailConfig represents outgoing mail configuration
+type MailConfig struct {
+	Driver  string     `yaml:"driver"` // smtp or dir
+	From    string     `yaml:"from"`
+	BaseURL string     `yaml:"base_url"` // public URL used in links sent by mail
+	Dir     string     `yaml:"dir"`      // output directory for the dir driver
+	SMTP    SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig represents SMTP server configuration
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// RateLimitConfig represents rate limiting configuration
+type RateLimitConfig struct {
+	Backend string           `yaml:"backend"` // memory or redis
+	Redis   RedisConfig      `yaml:"redis"`
+	Login   LoginLimitConfig `yaml:"login"`
+}
+
+// RedisConfig represents the Redis-compatible server used for shared state
+type RedisConfig struct {
+	Addr      string `yaml:"addr"`
+	Password  string `yaml:"password"`
+	DB        int    `yaml:"db"`
+	KeyPrefix string `yaml:"key_prefix"`
+}
+
+// EventsConfig represents the delivery of real-time events to clients
+type EventsConfig struct {
+	Backend    string      `yaml:"backend"` // memory or redis
+	Redis      RedisConfig `yaml:"redis"`
+	BufferSize int         `yaml:"buffer_size"` // events queued per connection before it is dropped
+}
+
+// LoginLimitConfig represents login throttling and lockout configuration
+type LoginLimitConfig struct {
+	IPPerMinute       int    `yaml:"ip_per_minute"`
+	IPBurst           int    `yaml:"ip_burst"`
+	UsernamePerMinute int    `yaml:"username_per_minute"`
+	UsernameBurst     int    `yaml:"username_burst"`
+	MaxFailures       int    `yaml:"max_failures"`
+	FailureWindow     string `yaml:"failure_window"`
+	LockoutBase       string `yaml:"lockout_base"`
+	LockoutMax        string `yaml:"lockout_max"`
 }
 
 // LoadConfig loads configuration from file
@@ -57,6 +182,14 @@ func LoadConfig() (*Config, error) {
 			Port: 8080,
 			Host: "0.0.0.0",
 		},
+		GRPC: GRPCConfig{
+			Port:       9090,
+			Reflection: true,
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      8,
+			MaxComplexity: 1000,
+		},
 		App: AppConfig{
 			ExcelFile: "configs/words/IELTS.xlsx",
 			PageSize:  12,
@@ -74,9 +207,59 @@ func LoadConfig() (*Config, error) {
 			ConnMaxLifetime: 3600,
 		},
 		JWT: JWTConfig{
-			Secret:    "your-secret-key-change-in-production",
+			Secret:    DefaultJWTSecret,
 			ExpiresIn: "24h",
 		},
+		RateLimit: RateLimitConfig{
+			Backend: "memory",
+			Redis: RedisConfig{
+				Addr:      "localhost:6379",
+				KeyPrefix: "word-hero:",
+			},
+			Login: LoginLimitConfig{
+				IPPerMinute:       20,
+				IPBurst:           10,
+				UsernamePerMinute: 10,
+				UsernameBurst:     5,
+				MaxFailures:       5,
+				FailureWindow:     "15m",
+				LockoutBase:       "1m",
+				LockoutMax:        "1h",
+			},
+		},
+		Events: EventsConfig{
+			Backend: "memory",
+			Redis: RedisConfig{
+				Addr:      "localhost:6379",
+				KeyPrefix: "word-hero:",
+			},
+			BufferSize: 64,
+		},
+		Auth: AuthConfig{
+			RequireEmailVerification: true,
+			VerificationTTL:          "48h",
+			PasswordResetTTL:         "1h",
+			GuestTTL:                 "720h",
+			DeletionGracePeriod:      "720h",
+		},
+		LDAP: LDAPConfig{
+			URL:            "ldap://localhost:389",
+			GroupFilter:    "(&(objectClass=groupOfNames)(member=%s))",
+			EmailAttribute: "mail",
+			NameAttribute:  "cn",
+			DefaultRole:    "user",
+			Timeout:        "5s",
+		},
+		Mail: MailConfig{
+			Driver:  "dir",
+			From:    "Word Hero <no-reply@wordhero.local>",
+			BaseURL: "http://localhost:8080",
+			Dir:     "mail",
+			SMTP: SMTPConfig{
+				Host: "localhost",
+				Port: 1025,
+			},
+		},
 	}
 
 	// Try to load from config file
@@ -142,4 +325,4 @@ func parseEnvInt(value string, fieldName string) (int, error) {
 		return 0, fmt.Errorf("invalid %s value: %s", fieldName, value)
 	}
 	return result, nil
-}
\ No newline at end of file
+}
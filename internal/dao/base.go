@@ -1,19 +1,24 @@
 package dao
 
 import (
-	"fmt"
+	"errors"
 
 	"gorm.io/gorm"
 )
 
+// ErrNotFound is wrapped by errors about records that do not exist
+var ErrNotFound = errors.New("not found")
+
 // Base 结构体提供基础数据库操作
 type Base struct{}
 
 // BaseList 分页查询参数结构体
 type BaseList struct {
-	PageNum  int    `form:"pageNum" json:"pageNum"`   // 页码，从1开始（可选）
-	PageSize int    `form:"pageSize" json:"pageSize"` // 每页大小（可选）
-	Sort     string `form:"sort" json:"sort"`         // 排序字段
+	PageNum   int    `form:"pageNum" json:"pageNum"`     // 页码，从1开始（可选）
+	PageSize  int    `form:"pageSize" json:"pageSize"`   // 每页大小（可选）
+	Sort      string `form:"sort" json:"sort"`           // 排序字段，如 difficulty,english|desc（可选）
+	Cursor    string `form:"cursor" json:"cursor"`       // 游标分页：首页传 start，之后传 nextCursor（可选）
+	SkipTotal bool   `form:"skipTotal" json:"skipTotal"` // 不统计总数，total 返回 -1（可选）
 }
 
 // PaginationResult 分页结果结构体
@@ -25,23 +30,9 @@ type PaginationResult struct {
 	TotalPages int         `json:"totalPages"` // 总页数
 }
 
-// ApplyPagination 应用分页和排序到数据库查询
+// ApplyPagination 应用分页到数据库查询；排序由各资源的 SortRegistry 处理
 func ApplyPagination(db *gorm.DB, baseList *BaseList) (*gorm.DB, error) {
 	if baseList != nil {
-		// 处理排序
-		if baseList.Sort != "" {
-			sort, err := NormalizeSorts(baseList.Sort)
-			if err != nil {
-				return nil, fmt.Errorf("invalid sort fields: %w", err)
-			}
-			if sort == "" {
-				sort = "created_at desc"
-			}
-			if len(sort) > 0 {
-				db = db.Order(sort)
-			}
-		}
-
 		// 处理分页 - 只有当pageNum和pageSize都设置且大于0时才应用分页
 		if baseList.PageNum > 0 && baseList.PageSize > 0 {
 			offset := (baseList.PageNum - 1) * baseList.PageSize
@@ -56,23 +47,6 @@ func PageList(db *gorm.DB, baseList *BaseList) (*gorm.DB, error) {
 	return ApplyPagination(db, baseList)
 }
 
-// NormalizeSorts 规范化排序字段
-func NormalizeSorts(str string) (string, error) {
-	var sorts string
-	for _, v := range str {
-		if v >= 'A' && v <= 'Z' {
-			sorts += "_" + string(v+32)
-		} else if v == '|' {
-			sorts += " "
-		} else if v >= 'a' && v <= 'z' || v == '_' || v == '-' || v == ',' {
-			sorts += string(v)
-		} else {
-			return "", fmt.Errorf("invalid sort fields")
-		}
-	}
-	return sorts, nil
-}
-
 // CalculatePagination 计算分页信息
 func CalculatePagination(total int64, pageNum, pageSize int) PaginationResult {
 	totalPages := 0
@@ -0,0 +1,118 @@
+package dao
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CursorStart starts keyset pagination from the first row
+const CursorStart = "start"
+
+// ErrInvalidCursor is returned for cursors that cannot be decoded or used
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// UsesCursor reports whether the list is paged by keyset cursor instead of
+// page number
+func (b *BaseList) UsesCursor() bool {
+	return b != nil && b.Cursor != ""
+}
+
+// Limit returns the page size of a keyset page
+func (b *BaseList) Limit() int {
+	if b == nil || b.PageSize <= 0 {
+		return defaultCursorPageSize
+	}
+	if b.PageSize > maxCursorPageSize {
+		return maxCursorPageSize
+	}
+	return b.PageSize
+}
+
+const (
+	defaultCursorPageSize = 20
+	maxCursorPageSize     = 200
+)
+
+// EncodeCursor encodes the sort key of the last row of a page as an opaque cursor
+func EncodeCursor(key interface{}) string {
+	data, err := json.Marshal(key)
+	if err != nil {
+		// Cursor keys are plain structs of strings and numbers
+		panic(fmt.Sprintf("failed to encode cursor: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a cursor into key. It returns false for CursorStart.
+// Only cursors exactly as EncodeCursor produces them for the type of key are
+// accepted, so edited cursors and those of other lists are rejected.
+func DecodeCursor(cursor string, key interface{}) (bool, error) {
+	if cursor == CursorStart {
+		return false, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(data, key); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if EncodeCursor(key) != cursor {
+		return false, fmt.Errorf("%w: cursor does not belong to this list", ErrInvalidCursor)
+	}
+	return true, nil
+}
+
+// countCacheTTL is how long a cached row count is reused
+const countCacheTTL = 30 * time.Second
+
+// countCache remembers recent row counts so paging through a large list does
+// not run a full COUNT(*) for every page
+type countCache struct {
+	mu      sync.Mutex
+	entries map[string]countEntry
+}
+
+type countEntry struct {
+	count     int64
+	expiresAt time.Time
+}
+
+var listCounts = &countCache{entries: make(map[string]countEntry)}
+
+// Get returns the cached count for key, calling count on a miss
+func (c *countCache) Get(key string, count func() (int64, error)) (int64, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.count, nil
+	}
+
+	n, err := count()
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = countEntry{count: n, expiresAt: now.Add(countCacheTTL)}
+	c.mu.Unlock()
+	return n, nil
+}
+
+// Reset drops all cached counts, after rows were added, changed or removed
+func (c *countCache) Reset() {
+	c.mu.Lock()
+	c.entries = make(map[string]countEntry)
+	c.mu.Unlock()
+}
@@ -0,0 +1,51 @@
+package dao
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := searchCursor{Rank: 3, Similarity: 0.4285714, English: "naïve 选择", ID: "w-1"}
+	cursor := EncodeCursor(want)
+
+	var got searchCursor
+	if ok, err := DecodeCursor(cursor, &got); err != nil || !ok || got != want {
+		t.Fatalf("DecodeCursor(EncodeCursor(%+v)) = %+v, %v, %v", want, got, ok, err)
+	}
+
+	var start wordCursor
+	if ok, err := DecodeCursor(CursorStart, &start); err != nil || ok {
+		t.Errorf("DecodeCursor(start) = %v, %v, want no position", ok, err)
+	}
+}
+
+func TestDecodeCursorRejectsTamperedAndMismatched(t *testing.T) {
+	word := EncodeCursor(wordCursor{English: "apple", ID: "w-1"})
+	raw := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+
+	tests := map[string]struct {
+		cursor string
+		key    interface{}
+	}{
+		"not base64":            {"not a cursor!", &wordCursor{}},
+		"padded base64":         {word + "==", &wordCursor{}},
+		"not json":              {raw("apple"), &wordCursor{}},
+		"wrong field type":      {raw(`{"e":1,"id":"w-1"}`), &wordCursor{}},
+		"edited json":           {raw(`{"id":"w-1","e":"apple"}`), &wordCursor{}},
+		"extra field":           {raw(`{"e":"apple","id":"w-1","x":1}`), &wordCursor{}},
+		"missing field":         {raw(`{"e":"apple"}`), &wordCursor{}},
+		"word cursor in search": {word, &searchCursor{}},
+		"search cursor in list": {EncodeCursor(searchCursor{Rank: 1, English: "apple", ID: "w-1"}), &wordCursor{}},
+		"word cursor in known":  {word, &knownWordCursor{}},
+		"word cursor in sync":   {word, &SyncKey{}},
+	}
+	for name, test := range tests {
+		if _, err := DecodeCursor(test.cursor, test.key); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: DecodeCursor(%q) error = %v, want ErrInvalidCursor", name, test.cursor, err)
+		}
+	}
+}
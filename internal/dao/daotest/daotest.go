@@ -0,0 +1,45 @@
+// Package daotest runs data access code against a mocked PostgreSQL
+// connection, so tests can check the queries without a database server.
+package daotest
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+)
+
+// Mock points dao.DB at a sqlmock connection until the test ends, when it
+// also checks that every expected query ran. DAOs must be created after
+// calling it. Queries are matched as regular expressions, and single writes
+// are not wrapped in a transaction.
+func Mock(t testing.TB) sqlmock.Sqlmock {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger:                 logger.Discard,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open gorm over sqlmock: %v", err)
+	}
+
+	previous := dao.DB
+	dao.DB = db
+	t.Cleanup(func() {
+		dao.DB = previous
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet database expectations: %v", err)
+		}
+		sqlDB.Close()
+	})
+	return mock
+}
@@ -0,0 +1,89 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// guestTouchInterval throttles last-seen updates to one write per guest per interval
+const guestTouchInterval = time.Hour
+
+// GuestDAO handles data access operations for guests
+type GuestDAO struct {
+	db *gorm.DB
+}
+
+// NewGuestDAO creates a new GuestDAO instance
+func NewGuestDAO() *GuestDAO {
+	return &GuestDAO{
+		db: DB,
+	}
+}
+
+// Create creates a new guest
+func (dao *GuestDAO) Create(guest *table.Guest) error {
+	guest.LastSeenAt = time.Now().UnixMilli()
+	if err := dao.db.Create(guest).Error; err != nil {
+		log.Error(err).Msg("Failed to create guest")
+		return fmt.Errorf("failed to create guest: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a guest by ID
+func (dao *GuestDAO) FindByID(id string) (*table.Guest, error) {
+	var guest table.Guest
+	if err := dao.db.First(&guest, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("guest %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to find guest: %w", err)
+	}
+	return &guest, nil
+}
+
+// Touch records that a guest was seen
+func (dao *GuestDAO) Touch(id string) error {
+	now := time.Now()
+	if err := dao.db.Model(&table.Guest{}).
+		Where("id = ? AND last_seen_at < ?", id, now.Add(-guestTouchInterval).UnixMilli()).
+		Update("last_seen_at", now.UnixMilli()).Error; err != nil {
+		return fmt.Errorf("failed to update guest last seen: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a guest
+func (dao *GuestDAO) Delete(id string) error {
+	if err := dao.db.Delete(&table.Guest{}, "id = ?", id).Error; err != nil {
+		log.Error(err).Str("guest_id", id).Msg("Failed to delete guest")
+		return fmt.Errorf("failed to delete guest: %w", err)
+	}
+	return nil
+}
+
+// PurgeInactive removes guests not seen since the cutoff (unix millis)
+// together with their word tags. It returns the number of guests removed.
+func (dao *GuestDAO) PurgeInactive(before int64) (int, error) {
+	var removed int64
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		stale := tx.Model(&table.Guest{}).Select("id").Where("last_seen_at < ?", before)
+		if err := tx.Where("user_id IN (?)", stale).Delete(&table.WordTag{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("last_seen_at < ?", before).Delete(&table.Guest{})
+		removed = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		log.Error(err).Msg("Failed to purge inactive guests")
+		return 0, fmt.Errorf("failed to purge inactive guests: %w", err)
+	}
+	return int(removed), nil
+}
@@ -4,11 +4,13 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sanmu2018/word-hero/internal/models"
 	"github.com/sanmu2018/word-hero/internal/table"
 	"github.com/sanmu2018/word-hero/log"
+	"gorm.io/gorm"
 )
 
 // AutoMigrate performs automatic database migration
@@ -30,6 +32,13 @@ func AutoMigrate() error {
 		&table.User{},
 		&table.Word{},
 		&table.WordTag{},
+		&table.UserToken{},
+		&table.RecoveryCode{},
+		&table.AppSetting{},
+		&table.UserIdentity{},
+		&table.PersonalAccessToken{},
+		&table.Guest{},
+		&table.SyncOperation{},
 	)
 	if err != nil {
 		log.Error(err).Msg("Database migration failed")
@@ -196,18 +205,157 @@ func MigrateWordTagsTable() error {
 
 // RunMigrations runs all database migrations and setup
 func RunMigrations() error {
+	// Accounts created before email verification existed are treated as verified
+	backfillVerified := DB.Migrator().HasTable(&table.User{}) && !DB.Migrator().HasColumn(&table.User{}, "EmailVerifiedAt")
+	backfillPinyin := DB.Migrator().HasTable(&table.Word{}) && !DB.Migrator().HasColumn(&table.Word{}, "Pinyin")
+	backfillProvisioned := DB.Migrator().HasTable(&table.UserIdentity{}) && !DB.Migrator().HasColumn(&table.UserIdentity{}, "Provisioned")
+
 	if err := AutoMigrate(); err != nil {
 		return err
 	}
+	if backfillVerified {
+		if err := BackfillEmailVerification(); err != nil {
+			return err
+		}
+	}
+	if backfillPinyin {
+		if err := BackfillWordPinyin(); err != nil {
+			return err
+		}
+	}
+	if backfillProvisioned {
+		if err := BackfillIdentityProvisioned(); err != nil {
+			return err
+		}
+	}
 	if err := MigrateWordTagsTable(); err != nil {
 		return err
 	}
+	if err := MigrateUserEmailIndex(); err != nil {
+		return err
+	}
+	if err := CreateSearchIndexes(); err != nil {
+		return err
+	}
+	if err := CreatePagingIndexes(); err != nil {
+		return err
+	}
 	if err := CreateDefaultUser(); err != nil {
 		return err
 	}
 	return nil
 }
 
+// CreateSearchIndexes enables pg_trgm and creates the trigram indexes used by
+// word search for substring and fuzzy matching
+func CreateSearchIndexes() error {
+	if err := DB.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		log.Error(err).Msg("Failed to create pg_trgm extension")
+		return fmt.Errorf("failed to create pg_trgm extension: %w", err)
+	}
+
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_words_english_trgm ON words USING gin (LOWER(english) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_words_chinese_trgm ON words USING gin (LOWER(chinese) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_words_pinyin_trgm ON words USING gin (pinyin gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_words_pinyin_tone_trgm ON words USING gin (pinyin_tone gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_words_pinyin_initials_trgm ON words USING gin (pinyin_initials gin_trgm_ops)",
+	}
+	for _, index := range indexes {
+		if err := DB.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreatePagingIndexes creates the indexes that back keyset pagination
+func CreatePagingIndexes() error {
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_words_english_id ON words (english, id)",
+		"CREATE INDEX IF NOT EXISTS idx_word_tags_user_known_id ON word_tags (user_id, known, id)",
+	}
+	for _, index := range indexes {
+		if err := DB.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create paging index: %w", err)
+		}
+	}
+	return nil
+}
+
+// BackfillWordPinyin computes the pinyin columns of existing words
+func BackfillWordPinyin() error {
+	log.Info().Msg("Computing pinyin for existing words...")
+	var words []table.Word
+	result := DB.Select("id", "chinese").FindInBatches(&words, 500, func(tx *gorm.DB, batch int) error {
+		for i := range words {
+			words[i].SetPinyin()
+			if err := tx.Model(&table.Word{}).Where("id = ?", words[i].ID).UpdateColumns(map[string]interface{}{
+				"pinyin":          words[i].Pinyin,
+				"pinyin_tone":     words[i].PinyinTone,
+				"pinyin_initials": words[i].PinyinInitials,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill word pinyin: %w", result.Error)
+	}
+	log.Info().Int64("words", result.RowsAffected).Msg("Word pinyin backfill completed")
+	return nil
+}
+
+// BackfillEmailVerification marks all existing users as verified
+func BackfillEmailVerification() error {
+	log.Info().Msg("Marking existing users as email verified...")
+	result := DB.Exec("UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL")
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill email verification: %w", result.Error)
+	}
+	log.Info().Int64("users", result.RowsAffected).Msg("Email verification backfill completed")
+	return nil
+}
+
+// BackfillIdentityProvisioned marks the identities that created their user.
+// Before the column existed an identity was recorded in the same request that
+// provisioned the user, while linked accounts had been created much earlier.
+func BackfillIdentityProvisioned() error {
+	log.Info().Msg("Marking identities that provisioned their user...")
+	result := DB.Exec("UPDATE user_identities SET provisioned = true FROM users " +
+		"WHERE users.id = user_identities.user_id AND user_identities.created_at - users.created_at BETWEEN 0 AND 60000")
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill identity provisioning: %w", result.Error)
+	}
+	log.Info().Int64("identities", result.RowsAffected).Msg("Identity provisioning backfill completed")
+	return nil
+}
+
+// MigrateUserEmailIndex recreates the unique email index of older databases so
+// that it skips empty emails, which several directory users may have
+func MigrateUserEmailIndex() error {
+	var definition string
+	if err := DB.Raw("SELECT indexdef FROM pg_indexes WHERE tablename = 'users' AND indexname = 'idx_users_email'").
+		Scan(&definition).Error; err != nil {
+		return fmt.Errorf("failed to read user email index: %w", err)
+	}
+	if strings.Contains(definition, "WHERE") {
+		return nil
+	}
+
+	log.Info().Msg("Recreating user email index without empty emails...")
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DROP INDEX IF EXISTS idx_users_email").Error; err != nil {
+			return fmt.Errorf("failed to drop user email index: %w", err)
+		}
+		if err := tx.Exec("CREATE UNIQUE INDEX idx_users_email ON users (email) WHERE email <> ''").Error; err != nil {
+			return fmt.Errorf("failed to create user email index: %w", err)
+		}
+		return nil
+	})
+}
+
 // CreateDefaultUser creates a default admin user if no users exist
 func CreateDefaultUser() error {
 	if DB == nil {
@@ -224,11 +372,13 @@ func CreateDefaultUser() error {
 
 	// In a real application, you would want to generate a secure random password
 	// and display it to the user or send it via email
+	verifiedAt := time.Now().UnixMilli()
 	defaultUser := &table.User{
-			Username: "admin",
-			Email:    "admin@wordhero.com",
-			FullName: "System Administrator",
-			Role:     "admin",
+			Username:        "admin",
+			Email:           "admin@wordhero.com",
+			FullName:        "System Administrator",
+			Role:            "admin",
+			EmailVerifiedAt: &verifiedAt,
 		}
 
 	// Hash the password (you should use a secure password in production)
@@ -0,0 +1,90 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// lastUsedResolution limits how often last-used tracking writes to the database
+const lastUsedResolution = time.Minute
+
+// PersonalAccessTokenDAO handles data access operations for personal access tokens
+type PersonalAccessTokenDAO struct {
+	db *gorm.DB
+}
+
+// NewPersonalAccessTokenDAO creates a new PersonalAccessTokenDAO instance
+func NewPersonalAccessTokenDAO() *PersonalAccessTokenDAO {
+	return &PersonalAccessTokenDAO{
+		db: DB,
+	}
+}
+
+// Create creates a new personal access token record
+func (dao *PersonalAccessTokenDAO) Create(token *table.PersonalAccessToken) error {
+	if err := dao.db.Create(token).Error; err != nil {
+		log.Error(err).Str("user_id", token.UserID).Msg("Failed to create personal access token")
+		return fmt.Errorf("failed to create personal access token: %w", err)
+	}
+	log.Info().Str("user_id", token.UserID).Str("token_id", token.ID).Str("scopes", token.Scopes).Msg("Personal access token created")
+	return nil
+}
+
+// FindActiveByHash finds an unrevoked, unexpired token by its hash
+func (dao *PersonalAccessTokenDAO) FindActiveByHash(tokenHash string) (*table.PersonalAccessToken, error) {
+	var token table.PersonalAccessToken
+	if err := dao.db.Where("token_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)",
+		tokenHash, time.Now().UnixMilli()).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("personal access token %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to find personal access token: %w", err)
+	}
+	return &token, nil
+}
+
+// ListByUser returns the unrevoked tokens of a user, newest first
+func (dao *PersonalAccessTokenDAO) ListByUser(userID string) ([]table.PersonalAccessToken, error) {
+	var tokens []table.PersonalAccessToken
+	if err := dao.db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").Find(&tokens).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to list personal access tokens")
+		return nil, fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke revokes a token owned by a user. It returns false if no such token exists.
+func (dao *PersonalAccessTokenDAO) Revoke(userID, tokenID string) (bool, error) {
+	result := dao.db.Model(&table.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("revoked_at", time.Now().UnixMilli())
+	if result.Error != nil {
+		log.Error(result.Error).Str("token_id", tokenID).Msg("Failed to revoke personal access token")
+		return false, fmt.Errorf("failed to revoke personal access token: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Info().Str("user_id", userID).Str("token_id", tokenID).Msg("Personal access token revoked")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// TouchLastUsed records a use of a token, at most once per lastUsedResolution
+func (dao *PersonalAccessTokenDAO) TouchLastUsed(tokenID, ip string) error {
+	now := time.Now()
+	if err := dao.db.Model(&table.PersonalAccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", tokenID, now.Add(-lastUsedResolution).UnixMilli()).
+		Updates(map[string]interface{}{
+			"last_used_at": now.UnixMilli(),
+			"last_used_ip": ip,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update token last used: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// RecoveryCodeDAO handles data access operations for 2FA recovery codes
+type RecoveryCodeDAO struct {
+	db *gorm.DB
+}
+
+// NewRecoveryCodeDAO creates a new RecoveryCodeDAO instance
+func NewRecoveryCodeDAO() *RecoveryCodeDAO {
+	return &RecoveryCodeDAO{
+		db: DB,
+	}
+}
+
+// Replace deletes all recovery codes of a user and stores the given code hashes
+func (dao *RecoveryCodeDAO) Replace(userID string, codeHashes []string) error {
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&table.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]table.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, table.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to replace recovery codes")
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	return nil
+}
+
+// Consume marks an unused recovery code as used. It returns false if the
+// code does not exist or was already used.
+func (dao *RecoveryCodeDAO) Consume(userID, codeHash string) (bool, error) {
+	result := dao.db.Model(&table.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now().UnixMilli())
+	if result.Error != nil {
+		log.Error(result.Error).Str("user_id", userID).Msg("Failed to consume recovery code")
+		return false, fmt.Errorf("failed to consume recovery code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnused returns the number of recovery codes a user has left
+func (dao *RecoveryCodeDAO) CountUnused(userID string) (int64, error) {
+	var count int64
+	if err := dao.db.Model(&table.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteAll deletes all recovery codes of a user
+func (dao *RecoveryCodeDAO) DeleteAll(userID string) error {
+	if err := dao.db.Where("user_id = ?", userID).Delete(&table.RecoveryCode{}).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to delete recovery codes")
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	return nil
+}
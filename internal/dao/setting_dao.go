@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// SettingDAO handles data access operations for runtime settings
+type SettingDAO struct {
+	db *gorm.DB
+}
+
+// NewSettingDAO creates a new SettingDAO instance
+func NewSettingDAO() *SettingDAO {
+	return &SettingDAO{
+		db: DB,
+	}
+}
+
+// GetBool returns a boolean setting, or def if it has not been set
+func (dao *SettingDAO) GetBool(key string, def bool) (bool, error) {
+	var settings []table.AppSetting
+	if err := dao.db.Where("key = ?", key).Limit(1).Find(&settings).Error; err != nil {
+		return def, fmt.Errorf("failed to get setting: %w", err)
+	}
+	if len(settings) == 0 {
+		return def, nil
+	}
+	return settings[0].Value == "true", nil
+}
+
+// Set creates or updates a setting
+func (dao *SettingDAO) Set(key, value string) error {
+	setting := table.AppSetting{Key: key, Value: value}
+	if err := dao.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&setting).Error; err != nil {
+		log.Error(err).Str("key", key).Msg("Failed to save setting")
+		return fmt.Errorf("failed to save setting: %w", err)
+	}
+	log.Info().Str("key", key).Str("value", value).Msg("Setting updated")
+	return nil
+}
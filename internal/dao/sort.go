@@ -0,0 +1,150 @@
+package dao
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+)
+
+// maxSortKeys bounds how many fields one request may sort by
+const maxSortKeys = 4
+
+// ErrSortNeedsUser is returned for sorts on a user's progress without a user
+var ErrSortNeedsUser = errors.New("sort field needs a signed in user")
+
+// SortField is a field a resource may be sorted by
+type SortField struct {
+	// Column is the fixed SQL expression ordered on
+	Column string
+	// ByUser marks expressions taking the current user ID as their only parameter
+	ByUser bool
+}
+
+// SortRegistry whitelists the fields of one resource that clients may sort
+// by, so that no client input ever reaches the ORDER BY clause
+type SortRegistry struct {
+	fields     map[string]SortField
+	tieBreaker string
+}
+
+// NewSortRegistry creates a registry of fields keyed by their API name.
+// tieBreaker is a unique column appended to every sort to make paging stable.
+func NewSortRegistry(tieBreaker string, fields map[string]SortField) *SortRegistry {
+	return &SortRegistry{fields: fields, tieBreaker: tieBreaker}
+}
+
+// Fields returns the API names of the sortable fields in alphabetical order
+func (r *SortRegistry) Fields() []string {
+	names := make([]string, 0, len(r.fields))
+	for name := range r.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SortError reports a sort parameter that cannot be applied. Key is a message
+// catalog key taking Args followed by the list of allowed fields.
+type SortError struct {
+	Key     string
+	Args    []interface{}
+	Allowed []string
+}
+
+func (e *SortError) Error() string {
+	return i18n.T(i18n.Default, e.Key, e.DetailArgs()...)
+}
+
+// DetailArgs returns the parameters of the message Key
+func (e *SortError) DetailArgs() []interface{} {
+	return append(append([]interface{}{}, e.Args...), strings.Join(e.Allowed, ", "))
+}
+
+// SortKey is one field of a parsed sort parameter
+type SortKey struct {
+	Field SortField
+	Desc  bool
+}
+
+// Parse parses a sort parameter of comma separated fields, each optionally
+// followed by |asc or |desc or prefixed with - for descending order, e.g.
+// "difficulty,english|desc" or "-createdAt"
+func (r *SortRegistry) Parse(input string) ([]SortKey, error) {
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, dir, hasDir := strings.Cut(part, "|")
+		desc := false
+		if strings.HasPrefix(name, "-") {
+			if hasDir {
+				return nil, r.sortError("sort.mixed_direction", part)
+			}
+			name, desc = name[1:], true
+		}
+		switch strings.ToLower(dir) {
+		case "", "asc":
+		case "desc":
+			desc = true
+		default:
+			return nil, r.sortError("sort.unknown_direction", dir)
+		}
+
+		field, ok := r.fields[name]
+		if !ok {
+			return nil, r.sortError("sort.unknown_field", name)
+		}
+		if seen[name] {
+			return nil, r.sortError("sort.duplicate_field", name)
+		}
+		seen[name] = true
+		keys = append(keys, SortKey{Field: field, Desc: desc})
+	}
+	if len(keys) > maxSortKeys {
+		return nil, r.sortError("sort.too_many_fields", maxSortKeys)
+	}
+	return keys, nil
+}
+
+// Apply orders tx by the sort parameter input. userID is bound into fields
+// that depend on a user and may be empty when none are requested. Without
+// any sort fields tx is returned unchanged.
+func (r *SortRegistry) Apply(tx *gorm.DB, input, userID string) (*gorm.DB, error) {
+	keys, err := r.Parse(input)
+	if err != nil || len(keys) == 0 {
+		return tx, err
+	}
+
+	// gorm merges ORDER BY expressions by replacing them, so the whole
+	// order goes into a single expression
+	terms := make([]string, 0, len(keys)+1)
+	var vars []interface{}
+	for _, key := range keys {
+		if key.Desc {
+			terms = append(terms, key.Field.Column+" DESC NULLS LAST")
+		} else {
+			terms = append(terms, key.Field.Column+" ASC NULLS LAST")
+		}
+		if key.Field.ByUser {
+			if userID == "" {
+				return nil, ErrSortNeedsUser
+			}
+			vars = append(vars, userID)
+		}
+	}
+	terms = append(terms, r.tieBreaker+" ASC")
+	return tx.Order(clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(terms, ", "), Vars: vars}}), nil
+}
+
+func (r *SortRegistry) sortError(key string, args ...interface{}) error {
+	return &SortError{Key: key, Args: args, Allowed: r.Fields()}
+}
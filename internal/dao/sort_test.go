@@ -0,0 +1,81 @@
+package dao_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+)
+
+var testSorts = dao.NewSortRegistry("words.id", map[string]dao.SortField{
+	"english":    {Column: "words.english"},
+	"difficulty": {Column: "words.difficulty"},
+	"known":      {Column: "(SELECT known FROM progress WHERE user_id = ?)", ByUser: true},
+})
+
+func TestSortParseRejectsBadInput(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		key   string
+	}{
+		"unknown field":     {"english,password", "sort.unknown_field"},
+		"mixed direction":   {"-english|asc", "sort.mixed_direction"},
+		"unknown direction": {"english|up", "sort.unknown_direction"},
+		"duplicate field":   {"english,-english", "sort.duplicate_field"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := testSorts.Parse(test.input)
+			var sortErr *dao.SortError
+			if !errors.As(err, &sortErr) {
+				t.Fatalf("Parse(%q) error = %v, want a SortError", test.input, err)
+			}
+			if sortErr.Key != test.key {
+				t.Errorf("Parse(%q) key = %q, want %q", test.input, sortErr.Key, test.key)
+			}
+			if want := []string{"difficulty", "english", "known"}; !slices.Equal(sortErr.Allowed, want) {
+				t.Errorf("Parse(%q) allowed = %v, want %v", test.input, sortErr.Allowed, want)
+			}
+		})
+	}
+}
+
+func TestSortParseDirections(t *testing.T) {
+	keys, err := testSorts.Parse(" difficulty|DESC, -english ,known|asc,")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var got []bool
+	for _, key := range keys {
+		got = append(got, key.Desc)
+	}
+	if want := []bool{true, true, false}; !slices.Equal(got, want) {
+		t.Errorf("Parse() descending = %v, want %v", got, want)
+	}
+}
+
+func TestSortApplyOrdersWithTieBreaker(t *testing.T) {
+	mock := daotest.Mock(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "words" ORDER BY words\.difficulty DESC NULLS LAST, ` +
+		`\(SELECT known FROM progress WHERE user_id = \$1\) ASC NULLS LAST, words\.id ASC$`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	query, err := testSorts.Apply(dao.DB.Table("words"), "-difficulty,known", "u1")
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	var rows []map[string]interface{}
+	if err := query.Find(&rows).Error; err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if _, err := testSorts.Apply(dao.DB.Table("words"), "known", ""); !errors.Is(err, dao.ErrSortNeedsUser) {
+		t.Errorf("Apply() without a user error = %v, want ErrSortNeedsUser", err)
+	}
+}
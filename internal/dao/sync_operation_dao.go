@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// SyncOperationDAO handles data access operations for synced client operations
+type SyncOperationDAO struct {
+	db *gorm.DB
+}
+
+// NewSyncOperationDAO creates a new SyncOperationDAO instance
+func NewSyncOperationDAO() *SyncOperationDAO {
+	return &SyncOperationDAO{
+		db: DB,
+	}
+}
+
+// SyncKey is the position of a word tag in the list of changes, encoded in
+// sync tokens
+type SyncKey struct {
+	UpdatedAt int64  `json:"u"`
+	ID        string `json:"i"`
+}
+
+// Apply records the operation opID of userID and sets whether wordID is
+// known, unless the operation was recorded before or the mark changed after
+// changedAt. It returns one of dto.SyncStatusApplied, dto.SyncStatusDuplicate
+// and dto.SyncStatusStale.
+func (dao *SyncOperationDAO) Apply(userID, opID, wordID string, known bool, changedAt int64) (string, error) {
+	status := dto.SyncStatusApplied
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&table.SyncOperation{
+			UserID:    userID,
+			ID:        opID,
+			AppliedAt: time.Now().UnixMilli(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			status = dto.SyncStatusDuplicate
+			return nil
+		}
+
+		var wordTag table.WordTag
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("word_id = ? AND user_id = ?", wordID, userID).
+			First(&wordTag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			wordTag = table.WordTag{WordID: wordID, UserID: userID}
+		} else if err != nil {
+			return err
+		} else if changedAt <= wordTag.LastChangedAt() {
+			status = dto.SyncStatusStale
+			return nil
+		}
+
+		// A word that stays known keeps the time it became known
+		if !known {
+			wordTag.Known = nil
+		} else if wordTag.Known == nil {
+			wordTag.Known = &changedAt
+		}
+		wordTag.ChangedAt = changedAt
+		return tx.Save(&wordTag).Error
+	})
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Str("op_id", opID).Msg("Failed to apply sync operation")
+		return "", fmt.Errorf("failed to apply sync operation: %w", err)
+	}
+	return status, nil
+}
+
+// ListChanges lists up to limit word tags of userID updated after the
+// position after, or from the start when it is nil, and before the cutoff
+// (unix millis), oldest first
+func (dao *SyncOperationDAO) ListChanges(userID string, after *SyncKey, before int64, limit int) ([]table.WordTag, error) {
+	query := dao.db.Where("user_id = ? AND updated_at < ?", userID, before)
+	if after != nil {
+		query = query.Where("(updated_at, id) > (?, ?)", after.UpdatedAt, after.ID)
+	}
+
+	var wordTags []table.WordTag
+	if err := query.Order("updated_at, id").Limit(limit).Find(&wordTags).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to list word tag changes")
+		return nil, fmt.Errorf("failed to list word tag changes: %w", err)
+	}
+	return wordTags, nil
+}
+
+// PurgeBefore removes the records of operations applied before the cutoff
+// (unix millis). It returns the number of records removed.
+func (dao *SyncOperationDAO) PurgeBefore(before int64) (int, error) {
+	result := dao.db.Where("applied_at < ?", before).Delete(&table.SyncOperation{})
+	if result.Error != nil {
+		log.Error(result.Error).Msg("Failed to purge sync operations")
+		return 0, fmt.Errorf("failed to purge sync operations: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
@@ -3,6 +3,7 @@ package dao
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -39,7 +40,7 @@ func (dao *UserDAO) FindByID(id string) (*table.User, error) {
 	var user table.User
 	if err := dao.db.First(&user, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", ErrNotFound)
 		}
 		log.Error(err).Str("user_id", id).Msg("Failed to find user by ID")
 		return nil, fmt.Errorf("failed to find user: %w", err)
@@ -52,7 +53,7 @@ func (dao *UserDAO) FindByUsername(username string) (*table.User, error) {
 	var user table.User
 	if err := dao.db.Where("username = ?", username).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", ErrNotFound)
 		}
 		log.Error(err).Str("username", username).Msg("Failed to find user by username")
 		return nil, fmt.Errorf("failed to find user: %w", err)
@@ -60,12 +61,16 @@ func (dao *UserDAO) FindByUsername(username string) (*table.User, error) {
 	return &user, nil
 }
 
-// FindByEmail finds a user by email
+// FindByEmail finds a user by email. An empty email never matches, since
+// several directory users may have none.
 func (dao *UserDAO) FindByEmail(email string) (*table.User, error) {
+	if email == "" {
+		return nil, fmt.Errorf("user %w", ErrNotFound)
+	}
 	var user table.User
 	if err := dao.db.Where("email = ?", email).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", ErrNotFound)
 		}
 		log.Error(err).Str("email", email).Msg("Failed to find user by email")
 		return nil, fmt.Errorf("failed to find user: %w", err)
@@ -75,11 +80,14 @@ func (dao *UserDAO) FindByEmail(email string) (*table.User, error) {
 
 // FindByUsernameOrEmail finds a user by either username or email
 func (dao *UserDAO) FindByUsernameOrEmail(usernameOrEmail string) (*table.User, error) {
+	if usernameOrEmail == "" {
+		return nil, fmt.Errorf("user %w", ErrNotFound)
+	}
 	var user table.User
 	err := dao.db.Where("username = ? OR email = ?", usernameOrEmail, usernameOrEmail).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", ErrNotFound)
 		}
 		log.Error(err).Str("identifier", usernameOrEmail).Msg("Failed to find user by username or email")
 		return nil, fmt.Errorf("failed to find user: %w", err)
@@ -102,7 +110,7 @@ func (dao *UserDAO) UpdateProfile(userID string, updateData *dto.UserUpdateReque
 	var user table.User
 	if err := dao.db.First(&user, "id = ?", userID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
@@ -118,6 +126,11 @@ func (dao *UserDAO) UpdateProfile(userID string, updateData *dto.UserUpdateReque
 	if updateData.Bio != "" {
 		updateMap["bio"] = updateData.Bio
 	}
+	if updateData.Language == "auto" {
+		updateMap["language"] = ""
+	} else if updateData.Language != "" {
+		updateMap["language"] = updateData.Language
+	}
 
 	if len(updateMap) == 0 {
 		return &user, nil // No updates needed
@@ -137,7 +150,7 @@ func (dao *UserDAO) UpdatePassword(userID string, newPassword string) error {
 	var user table.User
 	if err := dao.db.First(&user, "id = ?", userID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("user not found")
+			return fmt.Errorf("user %w", ErrNotFound)
 		}
 		return fmt.Errorf("failed to find user: %w", err)
 	}
@@ -156,6 +169,93 @@ func (dao *UserDAO) UpdatePassword(userID string, newPassword string) error {
 	return nil
 }
 
+// MarkEmailVerified records that a user has verified their email address
+func (dao *UserDAO) MarkEmailVerified(userID string, verifiedAt int64) error {
+	if err := dao.db.Model(&table.User{}).Where("id = ?", userID).Update("email_verified_at", verifiedAt).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to mark email as verified")
+		return fmt.Errorf("failed to mark email as verified: %w", err)
+	}
+	log.Info().Str("user_id", userID).Msg("User email verified")
+	return nil
+}
+
+// ResetPassword stores a new password hash and bumps the session version so
+// that every token issued before the reset stops working
+func (dao *UserDAO) ResetPassword(userID string, passwordHash string) error {
+	result := dao.db.Model(&table.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"password_hash":   passwordHash,
+		"session_version": gorm.Expr("session_version + 1"),
+	})
+	if result.Error != nil {
+		log.Error(result.Error).Str("user_id", userID).Msg("Failed to reset user password")
+		return fmt.Errorf("failed to reset user password: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user %w", ErrNotFound)
+	}
+	log.Info().Str("user_id", userID).Msg("User password reset successfully")
+	return nil
+}
+
+// UpdateRole changes the role of a user
+func (dao *UserDAO) UpdateRole(userID string, role string) error {
+	if err := dao.db.Model(&table.User{}).Where("id = ?", userID).Update("role", role).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to update user role")
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+	log.Info().Str("user_id", userID).Str("role", role).Msg("User role updated")
+	return nil
+}
+
+// SetTOTPSecret stores a pending TOTP secret for a user who has not yet enabled 2FA
+func (dao *UserDAO) SetTOTPSecret(userID string, secret string) error {
+	if err := dao.db.Model(&table.User{}).Where("id = ? AND totp_enabled_at IS NULL", userID).
+		Update("totp_secret", secret).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to store TOTP secret")
+		return fmt.Errorf("failed to store totp secret: %w", err)
+	}
+	return nil
+}
+
+// EnableTOTP activates the pending TOTP secret of a user
+func (dao *UserDAO) EnableTOTP(userID string, enabledAt int64, step int64) error {
+	if err := dao.db.Model(&table.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_enabled_at": enabledAt,
+		"totp_last_step":  step,
+	}).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to enable TOTP")
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+	log.Info().Str("user_id", userID).Msg("User TOTP enabled")
+	return nil
+}
+
+// DisableTOTP removes the TOTP secret of a user
+func (dao *UserDAO) DisableTOTP(userID string) error {
+	if err := dao.db.Model(&table.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":     "",
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	}).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to disable TOTP")
+		return fmt.Errorf("failed to disable totp: %w", err)
+	}
+	log.Info().Str("user_id", userID).Msg("User TOTP disabled")
+	return nil
+}
+
+// UseTOTPStep records that the code of a time step has been used. It returns
+// false if that step or a later one was already used, so a code cannot be replayed.
+func (dao *UserDAO) UseTOTPStep(userID string, step int64) (bool, error) {
+	result := dao.db.Model(&table.User{}).Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		log.Error(result.Error).Str("user_id", userID).Msg("Failed to record TOTP step")
+		return false, fmt.Errorf("failed to record totp step: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
 // Delete deletes a user from the database (soft delete)
 func (dao *UserDAO) Delete(userID string) error {
 	if err := dao.db.Delete(&table.User{}, "id = ?", userID).Error; err != nil {
@@ -235,4 +335,40 @@ func (dao *UserDAO) ExistsByEmail(email string) (bool, error) {
 		return false, fmt.Errorf("failed to check email existence: %w", err)
 	}
 	return count > 0, nil
-}
\ No newline at end of file
+}
+
+// PurgeDeleted permanently removes users soft-deleted before the cutoff
+// (unix millis) together with their word tags and account data. It returns the
+// number of users removed.
+func (dao *UserDAO) PurgeDeleted(before int64) (int, error) {
+	var ids []string
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&table.User{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", time.UnixMilli(before)).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, model := range []interface{}{
+			&table.WordTag{},
+			&table.UserToken{},
+			&table.RecoveryCode{},
+			&table.UserIdentity{},
+			&table.PersonalAccessToken{},
+			&table.SyncOperation{},
+		} {
+			if err := tx.Where("user_id IN ?", ids).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&table.User{}).Error
+	})
+	if err != nil {
+		log.Error(err).Msg("Failed to purge deleted users")
+		return 0, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// UserIdentityDAO handles data access operations for external identities
+type UserIdentityDAO struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityDAO creates a new UserIdentityDAO instance
+func NewUserIdentityDAO() *UserIdentityDAO {
+	return &UserIdentityDAO{
+		db: DB,
+	}
+}
+
+// Create links an external identity to a user
+func (dao *UserIdentityDAO) Create(identity *table.UserIdentity) error {
+	if err := dao.db.Create(identity).Error; err != nil {
+		log.Error(err).Str("user_id", identity.UserID).Str("provider", identity.Provider).Msg("Failed to create user identity")
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	log.Info().Str("user_id", identity.UserID).Str("provider", identity.Provider).Msg("External identity linked")
+	return nil
+}
+
+// FindByProviderSubject finds the identity issued by a provider for a subject
+func (dao *UserIdentityDAO) FindByProviderSubject(provider, subject string) (*table.UserIdentity, error) {
+	var identity table.UserIdentity
+	if err := dao.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user identity %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to find user identity: %w", err)
+	}
+	return &identity, nil
+}
+
+// UpdateLastLogin records a login through an identity
+func (dao *UserIdentityDAO) UpdateLastLogin(id string) error {
+	if err := dao.db.Model(&table.UserIdentity{}).Where("id = ?", id).
+		Update("last_login_at", time.Now().UnixMilli()).Error; err != nil {
+		return fmt.Errorf("failed to update identity last login: %w", err)
+	}
+	return nil
+}
+
+// ListByUser lists the external identities linked to a user
+func (dao *UserIdentityDAO) ListByUser(userID string) ([]table.UserIdentity, error) {
+	var identities []table.UserIdentity
+	if err := dao.db.Where("user_id = ?", userID).Order("created_at").Find(&identities).Error; err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+	return identities, nil
+}
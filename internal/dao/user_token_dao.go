@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// UserTokenDAO handles data access operations for single-use user tokens
+type UserTokenDAO struct {
+	db *gorm.DB
+}
+
+// NewUserTokenDAO creates a new UserTokenDAO instance
+func NewUserTokenDAO() *UserTokenDAO {
+	return &UserTokenDAO{
+		db: DB,
+	}
+}
+
+// Create creates a new user token record
+func (dao *UserTokenDAO) Create(token *table.UserToken) error {
+	if err := dao.db.Create(token).Error; err != nil {
+		log.Error(err).Str("user_id", token.UserID).Str("purpose", token.Purpose).Msg("Failed to create user token")
+		return fmt.Errorf("failed to create user token: %w", err)
+	}
+	return nil
+}
+
+// FindByHash finds a token of the given purpose by its hash
+func (dao *UserTokenDAO) FindByHash(purpose, tokenHash string) (*table.UserToken, error) {
+	var token table.UserToken
+	if err := dao.db.Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user token %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to find user token: %w", err)
+	}
+	return &token, nil
+}
+
+// FindLatest returns the most recently created token of a purpose for a user
+func (dao *UserTokenDAO) FindLatest(userID, purpose string) (*table.UserToken, error) {
+	var token table.UserToken
+	if err := dao.db.Where("user_id = ? AND purpose = ?", userID, purpose).Order("created_at DESC").First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user token %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to find user token: %w", err)
+	}
+	return &token, nil
+}
+
+// Consume marks an unused, unexpired token as used. It returns false if the
+// token was already used or has expired, so concurrent requests cannot both succeed.
+func (dao *UserTokenDAO) Consume(id string) (bool, error) {
+	now := time.Now().UnixMilli()
+	result := dao.db.Model(&table.UserToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		log.Error(result.Error).Str("token_id", id).Msg("Failed to consume user token")
+		return false, fmt.Errorf("failed to consume user token: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateAll marks every outstanding token of a purpose for a user as used
+func (dao *UserTokenDAO) InvalidateAll(userID, purpose string) error {
+	if err := dao.db.Model(&table.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now().UnixMilli()).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Str("purpose", purpose).Msg("Failed to invalidate user tokens")
+		return fmt.Errorf("failed to invalidate user tokens: %w", err)
+	}
+	return nil
+}
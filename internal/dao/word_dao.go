@@ -1,12 +1,13 @@
 package dao
 
 import (
+	"context"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/sanmu2018/word-hero/internal/dto"
 	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
 	"github.com/sanmu2018/word-hero/log"
 	"gorm.io/gorm"
 )
@@ -30,6 +31,7 @@ func (dao *WordDAO) Create(word *table.Word) error {
 		return fmt.Errorf("failed to create word: %w", err)
 	}
 
+	listCounts.Reset()
 	log.Info().Str("word_id", word.ID).Str("english", word.English).Msg("Word created successfully")
 	return nil
 }
@@ -39,7 +41,7 @@ func (dao *WordDAO) GetByID(id string) (*table.Word, error) {
 	var word table.Word
 	if err := dao.db.First(&word, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("word not found")
+			return nil, fmt.Errorf("word %w", ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get word: %w", err)
 	}
@@ -65,7 +67,7 @@ func (dao *WordDAO) GetByEnglish(english string) (*table.Word, error) {
 	var word table.Word
 	if err := dao.db.Where("english = ?", english).First(&word).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("word not found")
+			return nil, fmt.Errorf("word %w", ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get word by english: %w", err)
 	}
@@ -79,6 +81,7 @@ func (dao *WordDAO) Update(word *table.Word) error {
 		return fmt.Errorf("failed to update word: %w", err)
 	}
 
+	listCounts.Reset()
 	log.Info().Str("word_id", word.ID).Msg("Word updated successfully")
 	return nil
 }
@@ -90,38 +93,112 @@ func (dao *WordDAO) Delete(id string) error {
 		return fmt.Errorf("failed to delete word: %w", err)
 	}
 
+	listCounts.Reset()
 	log.Info().Str("word_id", id).Msg("Word deleted successfully")
 	return nil
 }
 
 // GetWordsByPage returns words for a specific page with pagination using BaseList
 func (dao *WordDAO) GetWordsByPage(baseList *BaseList) ([]table.Word, int64, error) {
+	words, total, _, err := dao.ListWords(baseList, nil)
+	return words, total, err
+}
+
+// WordSorts are the fields the word list may be sorted by
+var WordSorts = NewSortRegistry("words.id", map[string]SortField{
+	"english":    {Column: "words.english"},
+	"createdAt":  {Column: "words.created_at"},
+	"updatedAt":  {Column: "words.updated_at"},
+	"difficulty": {Column: "words.difficulty"},
+	"category":   {Column: "words.category"},
+	"length":     {Column: "CHAR_LENGTH(words.english)"},
+	"knownAt": {
+		Column: "(SELECT word_tags.known FROM word_tags WHERE word_tags.word_id = words.id AND word_tags.user_id = ?)",
+		ByUser: true,
+	},
+})
+
+// wordCursor is the keyset of the word list, ordered by headword
+type wordCursor struct {
+	English string `json:"e"`
+	ID      string `json:"id"`
+}
+
+// ListWords returns the words matching filter for a specific page, either by
+// page number or after a keyset cursor. The total is -1 when skipped and is
+// cached briefly for filters that do not depend on a user.
+func (dao *WordDAO) ListWords(baseList *BaseList, filter *WordFilter) ([]table.Word, int64, string, error) {
 	// baseList can be nil, meaning no pagination (return all data)
 	// No default values are set - pagination is completely optional
 
 	var words []table.Word
-	var total int64
+	total := int64(-1)
 
 	// Get total count
-	if err := dao.db.Model(&table.Word{}).Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to count words: %w", err)
+	if baseList == nil || !baseList.SkipTotal {
+		count := func() (int64, error) {
+			var n int64
+			err := filter.Apply(dao.db.Model(&table.Word{})).Count(&n).Error
+			return n, err
+		}
+		var err error
+		if filter.NeedsUser() {
+			total, err = count()
+		} else {
+			total, err = listCounts.Get("words:"+filter.cacheKey(), count)
+		}
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("failed to count words: %w", err)
+		}
 	}
 
 	// Build query with pagination
-	query := dao.db.Model(&table.Word{})
+	query := filter.Apply(dao.db.Model(&table.Word{}))
 
-	// Apply pagination and sorting
+	if baseList.UsesCursor() {
+		if baseList.Sort != "" {
+			return nil, 0, "", fmt.Errorf("%w: sort is not supported with cursor pagination", ErrInvalidCursor)
+		}
+		var after wordCursor
+		hasAfter, err := DecodeCursor(baseList.Cursor, &after)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		if hasAfter {
+			query = query.Where("(english, id) > (?, ?)", after.English, after.ID)
+		}
+
+		limit := baseList.Limit()
+		if err := query.Order("english ASC, id ASC").Limit(limit + 1).Find(&words).Error; err != nil {
+			return nil, 0, "", fmt.Errorf("failed to get words: %w", err)
+		}
+		next := ""
+		if len(words) > limit {
+			words = words[:limit]
+			last := words[limit-1]
+			next = EncodeCursor(wordCursor{English: last.English, ID: last.ID})
+		}
+		return words, total, next, nil
+	}
+
+	// Apply sorting and pagination
+	if baseList != nil {
+		var err error
+		if query, err = WordSorts.Apply(query, baseList.Sort, filter.userID()); err != nil {
+			return nil, 0, "", fmt.Errorf("failed to apply sort: %w", err)
+		}
+	}
 	query, err := PageList(query, baseList)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to apply pagination: %w", err)
+		return nil, 0, "", fmt.Errorf("failed to apply pagination: %w", err)
 	}
 
 	// Get paginated words
 	if err := query.Find(&words).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to get words: %w", err)
+		return nil, 0, "", fmt.Errorf("failed to get words: %w", err)
 	}
 
-	return words, total, nil
+	return words, total, "", nil
 }
 
 // GetWordsByPageLegacy 保持向后兼容的旧版本方法
@@ -142,71 +219,184 @@ func (dao *WordDAO) GetAllWords() ([]table.Word, error) {
 	return words, nil
 }
 
-// SearchWords searches for words matching the query in English and Chinese
-func (dao *WordDAO) SearchWords(param dto.WordSearchRequest) (int64, []table.Word, error) {
-	query := param.Q
+// SearchWords searches for words matching the query in English and Chinese,
+// or in the pinyin of the Chinese glosses, plus the headwords the query may be
+// an inflection of, restricted to words passing filter. Lemmas from the
+// irregular forms table rank above prefix matches, lemmas derived by suffix
+// rules below them. Besides substring matches it tolerates typos through
+// pg_trgm similarity. Results are ranked in the order of dto.MatchTypes.
+func (dao *WordDAO) SearchWords(param dto.WordSearchRequest, lemmas, derived []string, filter *WordFilter) (int64, []dto.WordSearchResult, string, error) {
+	query := strings.ToLower(param.Q)
 	if len(query) < 2 {
-		return 0, []table.Word{}, nil
+		return 0, []dto.WordSearchResult{}, "", nil
+	}
+
+	searchPattern := "%" + escapeLike(query) + "%"
+	pinyinMatch, pinyinArgs := pinyinCondition(query)
+	lemmaMatch, lemmaArgs := headwordCondition(lemmas)
+	derivedMatch, derivedArgs := headwordCondition(derived)
+	matchArgs := append([]interface{}{searchPattern, searchPattern, query}, pinyinArgs...)
+	matchArgs = append(matchArgs, lemmaArgs...)
+	matchArgs = append(matchArgs, derivedArgs...)
+	tx := filter.Apply(dao.db.Model(table.Word{})).Where(
+		"(LOWER(english) LIKE ? OR LOWER(chinese) LIKE ? OR LOWER(english) % ? OR "+pinyinMatch+" OR "+lemmaMatch+" OR "+derivedMatch+")",
+		matchArgs...,
+	)
+	total := int64(-1)
+	if !param.SkipTotal {
+		if err := tx.Count(&total).Error; err != nil {
+			log.Error(err).Send()
+			return 0, nil, "", fmt.Errorf("failed to count words: %w", err)
+		}
 	}
 
-	var words []table.Word
-	searchPattern := "%" + strings.ToLower(query) + "%"
-
-	tx := dao.db.Model(table.Word{}).Where(
-		"LOWER(english) LIKE ? OR LOWER(chinese) LIKE ?",
-		searchPattern, searchPattern,
+	rankArgs := append([]interface{}{query}, lemmaArgs...)
+	rankArgs = append(rankArgs, escapeLike(query)+"%")
+	rankArgs = append(rankArgs, derivedArgs...)
+	rankArgs = append(rankArgs, searchPattern, searchPattern)
+	rankArgs = append(rankArgs, pinyinArgs...)
+	rankArgs = append(rankArgs, query)
+	ranked := tx.Select(`words.*,
+		CASE
+			WHEN LOWER(english) = ? THEN 0
+			WHEN `+lemmaMatch+` THEN 1
+			WHEN LOWER(english) LIKE ? THEN 2
+			WHEN `+derivedMatch+` THEN 3
+			WHEN LOWER(english) LIKE ? OR LOWER(chinese) LIKE ? THEN 4
+			WHEN `+pinyinMatch+` THEN 5
+			ELSE 6
+		END AS match_rank,
+		similarity(LOWER(english), ?) AS similarity`,
+		rankArgs...,
 	)
-	var total int64
-	err := tx.Count(&total).Error
-	if err != nil {
-		log.Error(err).Send()
-		return 0, nil, fmt.Errorf("failed to count words: %w", err)
-	}
-	tx, err = PageList(tx, &BaseList{
-		PageNum:  param.PageNum,
-		PageSize: param.PageSize,
-	})
-	if err != nil {
-		return 0, nil, fmt.Errorf("failed to search words: %w", err)
-	}
-	err = tx.Order("english ASC").Find(&words).Error
-	if err != nil {
-		return 0, nil, fmt.Errorf("failed to search words: %w", err)
+
+	var results []dto.WordSearchResult
+	next := ""
+	if param.Cursor != "" {
+		// Keyset paging needs the computed rank columns, so page over the
+		// ranked rows as a subquery
+		var after searchCursor
+		hasAfter, err := DecodeCursor(param.Cursor, &after)
+		if err != nil {
+			return 0, nil, "", err
+		}
+		page := dao.db.Table("(?) AS ranked", ranked)
+		if hasAfter {
+			page = page.Where(`ranked.match_rank > ? OR (ranked.match_rank = ? AND (ranked.similarity < ?
+				OR (ranked.similarity = ? AND (ranked.english, ranked.id) > (?, ?))))`,
+				after.Rank, after.Rank, after.Similarity, after.Similarity, after.English, after.ID)
+		}
+
+		limit := (&BaseList{PageSize: param.PageSize}).Limit()
+		if err := page.Order("match_rank ASC, similarity DESC, english ASC, id ASC").Limit(limit + 1).Find(&results).Error; err != nil {
+			return 0, nil, "", fmt.Errorf("failed to search words: %w", err)
+		}
+		if len(results) > limit {
+			results = results[:limit]
+			last := results[limit-1]
+			next = EncodeCursor(searchCursor{Rank: last.MatchRank, Similarity: last.Similarity, English: last.English, ID: last.ID})
+		}
+	} else {
+		page, err := PageList(ranked, &BaseList{
+			PageNum:  param.PageNum,
+			PageSize: param.PageSize,
+		})
+		if err != nil {
+			return 0, nil, "", fmt.Errorf("failed to search words: %w", err)
+		}
+		if err := page.Order("match_rank ASC, similarity DESC, english ASC, id ASC").Find(&results).Error; err != nil {
+			return 0, nil, "", fmt.Errorf("failed to search words: %w", err)
+		}
 	}
 
-	log.Info().Str("query", query).Int("results", len(words)).Msg("Search completed")
-	return total, words, nil
+	log.Info().Str("query", query).Int("results", len(results)).Msg("Search completed")
+	return total, results, next, nil
 }
 
-// SearchWordsWithRegex searches for words using regex patterns
-func (dao *WordDAO) SearchWordsWithRegex(query string) ([]table.Word, error) {
-	if len(query) < 2 {
-		return []table.Word{}, nil
+// searchCursor is the keyset of ranked search results
+type searchCursor struct {
+	Rank       int     `json:"r"`
+	Similarity float64 `json:"s"`
+	English    string  `json:"e"`
+	ID         string  `json:"id"`
+}
+
+// pinyinCondition matches a query against the start of any gloss in the
+// pinyin columns. Queries that cannot be pinyin match nothing.
+func pinyinCondition(query string) (string, []interface{}) {
+	py := utils.NormalizePinyinQuery(query)
+	if py == "" {
+		return "FALSE", nil
 	}
 
-	// Compile regex pattern
-	pattern, err := regexp.Compile("(?i)" + query) // (?i) for case-insensitive
-	if err != nil {
-		return []table.Word{}, fmt.Errorf("invalid regex pattern: %w", err)
+	start, word := escapeLike(py)+"%", "% "+escapeLike(py)+"%"
+	return "(pinyin LIKE ? OR pinyin LIKE ? OR pinyin_tone LIKE ? OR pinyin_tone LIKE ? OR pinyin_initials LIKE ? OR pinyin_initials LIKE ?)",
+		[]interface{}{start, word, start, word, start, word}
+}
+
+// headwordCondition matches words whose English headword is one of
+// headwords. An empty list matches nothing.
+func headwordCondition(headwords []string) (string, []interface{}) {
+	if len(headwords) == 0 {
+		return "FALSE", nil
 	}
+	return "LOWER(english) IN ?", []interface{}{headwords}
+}
 
-	allWords, err := dao.GetAllWords()
-	if err != nil {
-		return nil, err
+// escapeLike escapes the LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// SearchWordsByPattern finds words whose English or Chinese text matches a
+// POSIX regular expression, case-insensitively. The pattern must already be
+// validated; ctx bounds how long the database may spend on it.
+func (dao *WordDAO) SearchWordsByPattern(ctx context.Context, pattern string, param dto.BaseList) (int64, []table.Word, string, error) {
+	tx := dao.db.WithContext(ctx).Model(table.Word{}).Where("english ~* ? OR chinese ~* ?", pattern, pattern)
+	total := int64(-1)
+	if !param.SkipTotal {
+		if err := tx.Count(&total).Error; err != nil {
+			return 0, nil, "", fmt.Errorf("failed to count words: %w", err)
+		}
 	}
 
-	var results []table.Word
-	for _, word := range allWords {
-		englishMatch := pattern.MatchString(word.English)
-		chineseMatch := pattern.MatchString(word.Chinese)
+	var words []table.Word
+	if param.Cursor != "" {
+		var after wordCursor
+		hasAfter, err := DecodeCursor(param.Cursor, &after)
+		if err != nil {
+			return 0, nil, "", err
+		}
+		if hasAfter {
+			tx = tx.Where("(english, id) > (?, ?)", after.English, after.ID)
+		}
 
-		if englishMatch || chineseMatch {
-			results = append(results, word)
+		limit := (&BaseList{PageSize: param.PageSize}).Limit()
+		if err := tx.Order("english ASC, id ASC").Limit(limit + 1).Find(&words).Error; err != nil {
+			return 0, nil, "", fmt.Errorf("failed to search words: %w", err)
+		}
+		next := ""
+		if len(words) > limit {
+			words = words[:limit]
+			last := words[limit-1]
+			next = EncodeCursor(wordCursor{English: last.English, ID: last.ID})
 		}
+		return total, words, next, nil
 	}
 
-	log.Info().Str("query", query).Int("results", len(results)).Msg("Regex search completed")
-	return results, nil
+	tx, err := PageList(tx, &BaseList{
+		PageNum:  param.PageNum,
+		PageSize: param.PageSize,
+	})
+	if err != nil {
+		return 0, nil, "", fmt.Errorf("failed to search words: %w", err)
+	}
+	if err := tx.Order("english ASC, id ASC").Find(&words).Error; err != nil {
+		return 0, nil, "", fmt.Errorf("failed to search words: %w", err)
+	}
+
+	log.Info().Str("pattern", pattern).Int("results", len(words)).Msg("Pattern search completed")
+	return total, words, "", nil
 }
 
 // GetWordsByChinese finds words by Chinese text
@@ -244,6 +434,21 @@ func (dao *WordDAO) GetWordCount() (int64, error) {
 	return count, nil
 }
 
+// GetVersion returns the word count and latest update time, which change
+// whenever words are added, edited or removed
+func (dao *WordDAO) GetVersion() (int64, int64, error) {
+	var version struct {
+		Count     int64
+		UpdatedAt int64
+	}
+	if err := dao.db.Model(&table.Word{}).
+		Select("COUNT(*) AS count, COALESCE(MAX(updated_at), 0) AS updated_at").
+		Scan(&version).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to get words version: %w", err)
+	}
+	return version.Count, version.UpdatedAt, nil
+}
+
 // GetWordsByCategory returns words filtered by category
 func (dao *WordDAO) GetWordsByCategory(category string) ([]table.Word, error) {
 	var words []table.Word
@@ -287,6 +492,7 @@ func (dao *WordDAO) BulkImport(words []table.Word) error {
 		log.Debug().Int("batch_start", i).Int("batch_end", end).Msg("Batch imported successfully")
 	}
 
+	listCounts.Reset()
 	log.Info().Int("total_imported", len(words)).Msg("Bulk import completed successfully")
 	return nil
 }
@@ -300,6 +506,7 @@ func (dao *WordDAO) DeleteAllWords() error {
 		return fmt.Errorf("failed to delete all words: %w", err)
 	}
 
+	listCounts.Reset()
 	log.Info().Msg("All words deleted successfully")
 	return nil
 }
@@ -348,6 +555,28 @@ func (dao *WordDAO) GetStats() (map[string]interface{}, error) {
 	return stats, nil
 }
 
+// facetColumns are the columns words may be counted by
+var facetColumns = map[string]bool{"category": true, "difficulty": true}
+
+// CountBy returns the number of words by value of column, category or
+// difficulty, most frequent first. Empty values are left out.
+func (dao *WordDAO) CountBy(column string) ([]dto.FacetCount, error) {
+	if !facetColumns[column] {
+		return nil, fmt.Errorf("cannot count words by %s", column)
+	}
+
+	var counts []dto.FacetCount
+	if err := dao.db.Model(&table.Word{}).
+		Select(column + " AS value, COUNT(*) AS count").
+		Where(column + " != ''").
+		Group(column).
+		Order("count DESC, value").
+		Find(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count words by %s: %w", column, err)
+	}
+	return counts, nil
+}
+
 // IsEmpty checks if the words table is empty
 func (dao *WordDAO) IsEmpty() (bool, error) {
 	var count int64
@@ -0,0 +1,96 @@
+package dao_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/dto"
+)
+
+// searchKey mirrors the keyset encoded in search cursors
+type searchKey struct {
+	Rank       int     `json:"r"`
+	Similarity float64 `json:"s"`
+	English    string  `json:"e"`
+	ID         string  `json:"id"`
+}
+
+var searchColumns = []string{"id", "english", "match_rank", "similarity"}
+
+func TestSearchWordsRanksLemmasAroundPrefixMatches(t *testing.T) {
+	mock := daotest.Mock(t)
+	d := dao.NewWordDAO()
+
+	// Irregular lemmas rank above prefix matches, derived lemmas below them
+	mock.ExpectQuery(`CASE\s+WHEN LOWER\(english\) = \$\d+ THEN 0\s+`+
+		`WHEN LOWER\(english\) IN \(\$\d+\) THEN 1\s+`+
+		`WHEN LOWER\(english\) LIKE \$\d+ THEN 2\s+`+
+		`WHEN LOWER\(english\) IN \(\$\d+\) THEN 3\s+`+
+		`WHEN LOWER\(english\) LIKE \$\d+ OR LOWER\(chinese\) LIKE \$\d+ THEN 4\s+`+
+		`WHEN .* THEN 5\s+ELSE 6\s+END AS match_rank.*`+
+		`ORDER BY match_rank ASC, similarity DESC, english ASC, id ASC`).
+		WithArgs(
+			// Rank: exact, irregular lemma, prefix, derived lemma, contains, pinyin, similarity
+			"news", "newsy", "news%", "new", "%news%", "%news%",
+			"news%", "% news%", "news%", "% news%", "news%", "% news%", "news",
+			// Match: contains, similarity, pinyin, lemmas
+			"%news%", "%news%", "news",
+			"news%", "% news%", "news%", "% news%", "news%", "% news%", "newsy", "new",
+			10).
+		WillReturnRows(sqlmock.NewRows(searchColumns))
+
+	param := dto.WordSearchRequest{Q: "News", BaseList: dto.BaseList{PageNum: 1, PageSize: 10, SkipTotal: true}}
+	if _, _, _, err := d.SearchWords(param, []string{"newsy"}, []string{"new"}, nil); err != nil {
+		t.Fatalf("SearchWords() error = %v", err)
+	}
+}
+
+func TestSearchWordsPagesByCursor(t *testing.T) {
+	mock := daotest.Mock(t)
+	d := dao.NewWordDAO()
+
+	mock.ExpectQuery(`SELECT \* FROM \(SELECT words\.\*,.*\) AS ranked ORDER BY match_rank ASC, similarity DESC, english ASC, id ASC LIMIT \$\d+`).
+		WillReturnRows(sqlmock.NewRows(searchColumns).
+			AddRow("w1", "run", 0, 1.0).
+			AddRow("w2", "running", 2, 0.5).
+			AddRow("w3", "rerun", 4, 0.5))
+
+	param := dto.WordSearchRequest{Q: "run", BaseList: dto.BaseList{Cursor: dao.CursorStart, PageSize: 2, SkipTotal: true}}
+	total, results, next, err := d.SearchWords(param, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("SearchWords() error = %v", err)
+	}
+	if total != -1 || len(results) != 2 {
+		t.Fatalf("SearchWords() = %d, %d results, want -1 and a page of 2", total, len(results))
+	}
+
+	var key searchKey
+	if ok, err := dao.DecodeCursor(next, &key); err != nil || !ok {
+		t.Fatalf("DecodeCursor(%q) = %v, %v", next, ok, err)
+	}
+	if want := (searchKey{Rank: 2, Similarity: 0.5, English: "running", ID: "w2"}); key != want {
+		t.Errorf("next cursor = %+v, want %+v", key, want)
+	}
+
+	// The next page continues after the last row, by rank, similarity, english and id
+	mock.ExpectQuery(`AS ranked WHERE ranked.match_rank > \$\d+ OR \(ranked.match_rank = \$\d+ AND \(ranked.similarity < \$\d+\s+` +
+		`OR \(ranked.similarity = \$\d+ AND \(ranked.english, ranked.id\) > \(\$\d+, \$\d+\)\)\)\) ORDER BY`).
+		WillReturnRows(sqlmock.NewRows(searchColumns).AddRow("w3", "rerun", 4, 0.5))
+
+	param.Cursor = next
+	_, results, next, err = d.SearchWords(param, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("SearchWords(next) error = %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "w3" || next != "" {
+		t.Errorf("SearchWords(next) = %d results, cursor %q, want the last row and no cursor", len(results), next)
+	}
+
+	param.Cursor = "not a cursor"
+	if _, _, _, err := d.SearchWords(param, nil, nil, nil); err == nil {
+		t.Error("SearchWords() with a tampered cursor succeeded, want an error")
+	}
+}
@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// WordFilter narrows word listings and searches. Zero values mean no
+// restriction; every condition is a fixed clause with bound parameters.
+type WordFilter struct {
+	Categories           []string
+	ExcludedCategories   []string
+	Difficulties         []string
+	ExcludedDifficulties []string
+
+	// UserID owns the word tags that Known, the learned range and sorting
+	// by knownAt refer to
+	UserID string
+	Known  *bool
+	// LearnedFrom and LearnedTo bound when a word was marked as known, in
+	// milliseconds; LearnedFrom is inclusive and LearnedTo exclusive
+	LearnedFrom int64
+	LearnedTo   int64
+
+	// MinLength and MaxLength bound the number of characters of the headword
+	MinLength int
+	MaxLength int
+}
+
+// Apply adds the filter conditions to a query on the words table
+func (f *WordFilter) Apply(tx *gorm.DB) *gorm.DB {
+	if f == nil {
+		return tx
+	}
+
+	if len(f.Categories) > 0 {
+		tx = tx.Where("words.category IN ?", f.Categories)
+	}
+	if len(f.ExcludedCategories) > 0 {
+		tx = tx.Where("words.category NOT IN ?", f.ExcludedCategories)
+	}
+	if len(f.Difficulties) > 0 {
+		tx = tx.Where("words.difficulty IN ?", f.Difficulties)
+	}
+	if len(f.ExcludedDifficulties) > 0 {
+		tx = tx.Where("words.difficulty NOT IN ?", f.ExcludedDifficulties)
+	}
+
+	const knownTag = "SELECT 1 FROM word_tags WHERE word_tags.word_id = words.id AND word_tags.user_id = ? AND word_tags.known IS NOT NULL"
+	if f.Known != nil {
+		if *f.Known {
+			tx = tx.Where("EXISTS ("+knownTag+")", f.UserID)
+		} else {
+			tx = tx.Where("NOT EXISTS ("+knownTag+")", f.UserID)
+		}
+	}
+	if f.LearnedFrom > 0 {
+		tx = tx.Where("EXISTS ("+knownTag+" AND word_tags.known >= ?)", f.UserID, f.LearnedFrom)
+	}
+	if f.LearnedTo > 0 {
+		tx = tx.Where("EXISTS ("+knownTag+" AND word_tags.known < ?)", f.UserID, f.LearnedTo)
+	}
+
+	if f.MinLength > 0 {
+		tx = tx.Where("CHAR_LENGTH(words.english) >= ?", f.MinLength)
+	}
+	if f.MaxLength > 0 {
+		tx = tx.Where("CHAR_LENGTH(words.english) <= ?", f.MaxLength)
+	}
+	return tx
+}
+
+// NeedsUser reports whether the filter refers to a user's progress
+func (f *WordFilter) NeedsUser() bool {
+	return f != nil && (f.Known != nil || f.LearnedFrom > 0 || f.LearnedTo > 0)
+}
+
+// userID returns the user the filter was built for, if any
+func (f *WordFilter) userID() string {
+	if f == nil {
+		return ""
+	}
+	return f.UserID
+}
+
+// cacheKey identifies the filter in the list count cache. The user only
+// matters to filters on their progress.
+func (f *WordFilter) cacheKey() string {
+	if f == nil {
+		return ""
+	}
+	key := *f
+	if !f.NeedsUser() {
+		key.UserID = ""
+	}
+	return fmt.Sprintf("%+v", key)
+}
@@ -3,7 +3,9 @@ package dao
 import (
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/sanmu2018/word-hero/internal/dto"
 	"github.com/sanmu2018/word-hero/internal/table"
 	"github.com/sanmu2018/word-hero/log"
 	"gorm.io/gorm"
@@ -37,7 +39,7 @@ func (dao *WordTagDAO) GetByID(id string) (*table.WordTag, error) {
 	var wordTag table.WordTag
 	if err := dao.db.First(&wordTag, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("word tag not found")
+			return nil, fmt.Errorf("word tag %w", ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get word tag: %w", err)
 	}
@@ -49,7 +51,7 @@ func (dao *WordTagDAO) GetByWordID(wordID string) (*table.WordTag, error) {
 	var wordTag table.WordTag
 	if err := dao.db.Where("word_id = ?", wordID).First(&wordTag).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("word tag not found for word")
+			return nil, fmt.Errorf("word tag %w for word", ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get word tag by word ID: %w", err)
 	}
@@ -61,7 +63,7 @@ func (dao *WordTagDAO) GetByWordIDAndUserID(wordID, userID string) (*table.WordT
 	var wordTag table.WordTag
 	if err := dao.db.Where("word_id = ? AND user_id = ?", wordID, userID).First(&wordTag).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("word tag not found for word and user")
+			return nil, fmt.Errorf("word tag %w for word and user", ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get word tag by word ID and user ID: %w", err)
 	}
@@ -143,7 +145,7 @@ func (dao *WordTagDAO) RemoveWordMark(wordID, userID string) error {
 func (dao *WordTagDAO) IsWordMarkedAsKnown(wordID, userID string) (bool, error) {
 	wordTag, err := dao.GetByWordIDAndUserID(wordID, userID)
 	if err != nil {
-		if err.Error() == "word tag not found for word and user" {
+		if errors.Is(err, ErrNotFound) {
 			return false, nil // Word not tagged by this user
 		}
 		return false, fmt.Errorf("failed to get word tag: %w", err)
@@ -152,29 +154,77 @@ func (dao *WordTagDAO) IsWordMarkedAsKnown(wordID, userID string) (bool, error)
 	return wordTag.IsKnown(), nil
 }
 
-// GetKnownWords returns all words marked as known by a user
-func (dao *WordTagDAO) GetKnownWords(userID string, baseList *BaseList) ([]string, int64, error) {
+// KnownWordSorts are the fields a user's known words may be sorted by
+var KnownWordSorts = NewSortRegistry("word_tags.id", map[string]SortField{
+	"knownAt":   {Column: "word_tags.known"},
+	"createdAt": {Column: "word_tags.created_at"},
+})
+
+// knownWordCursor is the keyset of a user's known words, newest first
+type knownWordCursor struct {
+	Known int64  `json:"k"`
+	ID    string `json:"id"`
+}
+
+// GetKnownWords returns all words marked as known by a user, by page number
+// or, newest first, after a keyset cursor. The total is -1 when skipped.
+func (dao *WordTagDAO) GetKnownWords(userID string, baseList *BaseList) ([]string, int64, string, error) {
 	var wordTags []table.WordTag
-	var total int64
+	total := int64(-1)
 
 	// Build query with pagination and user filtering
 	query := dao.db.Model(&table.WordTag{}).
 		Where("user_id = ? AND known IS NOT NULL", userID)
 
 	// Get total count
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to count known words: %w", err)
+	if baseList == nil || !baseList.SkipTotal {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, "", fmt.Errorf("failed to count known words: %w", err)
+		}
 	}
 
-	// Apply pagination and sorting
-	query, err := PageList(query, baseList)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to apply pagination: %w", err)
-	}
+	next := ""
+	if baseList.UsesCursor() {
+		if baseList.Sort != "" {
+			return nil, 0, "", fmt.Errorf("%w: sort is not supported with cursor pagination", ErrInvalidCursor)
+		}
+		var after knownWordCursor
+		hasAfter, err := DecodeCursor(baseList.Cursor, &after)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		if hasAfter {
+			query = query.Where("(known, id) < (?, ?)", after.Known, after.ID)
+		}
 
-	// Get paginated word tags
-	if err := query.Find(&wordTags).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to get known words: %w", err)
+		limit := baseList.Limit()
+		if err := query.Order("known DESC, id DESC").Limit(limit + 1).Find(&wordTags).Error; err != nil {
+			return nil, 0, "", fmt.Errorf("failed to get known words: %w", err)
+		}
+		if len(wordTags) > limit {
+			wordTags = wordTags[:limit]
+			last := wordTags[limit-1]
+			next = EncodeCursor(knownWordCursor{Known: last.GetKnownTimestamp(), ID: last.ID})
+		}
+	} else {
+		// Apply sorting and pagination
+		sort := ""
+		if baseList != nil {
+			sort = baseList.Sort
+		}
+		query, err := KnownWordSorts.Apply(query, sort, userID)
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("failed to apply sort: %w", err)
+		}
+		query, err = PageList(query, baseList)
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("failed to apply pagination: %w", err)
+		}
+
+		// Get paginated word tags
+		if err := query.Find(&wordTags).Error; err != nil {
+			return nil, 0, "", fmt.Errorf("failed to get known words: %w", err)
+		}
 	}
 
 	// Extract word IDs
@@ -183,7 +233,7 @@ func (dao *WordTagDAO) GetKnownWords(userID string, baseList *BaseList) ([]strin
 		wordIDs = append(wordIDs, wordTag.WordID)
 	}
 
-	return wordIDs, total, nil
+	return wordIDs, total, next, nil
 }
 
 // GetKnownWordsCount returns the count of words marked as known by a user
@@ -197,6 +247,21 @@ func (dao *WordTagDAO) GetKnownWordsCount(userID string) (int64, error) {
 	return count, nil
 }
 
+// GetKnownByWordIDs returns the tags of the words a user marked as known
+// among wordIDs, in a single query
+func (dao *WordTagDAO) GetKnownByWordIDs(userID string, wordIDs []string) ([]table.WordTag, error) {
+	if len(wordIDs) == 0 {
+		return []table.WordTag{}, nil
+	}
+
+	var wordTags []table.WordTag
+	if err := dao.db.Where("user_id = ? AND word_id IN ? AND known IS NOT NULL", userID, wordIDs).
+		Find(&wordTags).Error; err != nil {
+		return nil, fmt.Errorf("failed to get word tags by word IDs: %w", err)
+	}
+	return wordTags, nil
+}
+
 // BulkRemoveWordMarks removes marks from multiple words for a user in one operation
 func (dao *WordTagDAO) BulkRemoveWordMarks(wordIDs []string, userID string) (int, error) {
 	if len(wordIDs) == 0 {
@@ -206,7 +271,7 @@ func (dao *WordTagDAO) BulkRemoveWordMarks(wordIDs []string, userID string) (int
 	// Build the SQL query to set known to NULL for multiple words for a specific user
 	result := dao.db.Model(&table.WordTag{}).
 		Where("word_id IN ? AND user_id = ?", wordIDs, userID).
-		Update("known", nil)
+		Updates(map[string]interface{}{"known": nil, "changed_at": time.Now().UnixMilli()})
 
 	if result.Error != nil {
 		log.Error(result.Error).
@@ -229,7 +294,7 @@ func (dao *WordTagDAO) BulkRemoveWordMarks(wordIDs []string, userID string) (int
 func (dao *WordTagDAO) RemoveAllWordMarks(userID string) (int, error) {
 	result := dao.db.Model(&table.WordTag{}).
 		Where("user_id = ? AND known IS NOT NULL", userID).
-		Update("known", nil)
+		Updates(map[string]interface{}{"known": nil, "changed_at": time.Now().UnixMilli()})
 
 	if result.Error != nil {
 		log.Error(result.Error).Str("user_id", userID).Msg("Failed to remove all word marks")
@@ -282,3 +347,51 @@ func (dao *WordTagDAO) GetStats() (map[string]interface{}, error) {
 
 	return stats, nil
 }
+
+// MergeInto moves all word tags of one owner (a guest) to a user. For words
+// both have tagged, a known mark wins over an unknown one and the earliest
+// known timestamp is kept.
+func (dao *WordTagDAO) MergeInto(fromID, toID string) (int, error) {
+	var moved int64
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		// LEAST ignores NULLs, so it keeps whichever side is known
+		now := time.Now().UnixMilli()
+		if err := tx.Exec(`UPDATE word_tags AS u SET known = LEAST(u.known, g.known), changed_at = ?, updated_at = ?
+			FROM word_tags AS g
+			WHERE g.user_id = ? AND u.user_id = ? AND u.word_id = g.word_id`,
+			now, now, fromID, toID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM word_tags AS g
+			WHERE g.user_id = ? AND EXISTS (
+				SELECT 1 FROM word_tags AS u WHERE u.user_id = ? AND u.word_id = g.word_id)`,
+			fromID, toID).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&table.WordTag{}).Where("user_id = ?", fromID).Update("user_id", toID)
+		moved = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		log.Error(err).Str("from_id", fromID).Str("to_id", toID).Msg("Failed to merge word tags")
+		return 0, fmt.Errorf("failed to merge word tags: %w", err)
+	}
+
+	log.Info().Str("from_id", fromID).Str("to_id", toID).Int64("moved", moved).Msg("Word tags merged")
+	return int(moved), nil
+}
+
+// ListForExport lists all word tags of a user with the words they refer to
+func (dao *WordTagDAO) ListForExport(userID string) ([]dto.WordTagExport, error) {
+	var tags []dto.WordTagExport
+	if err := dao.db.Table("word_tags").
+		Select("word_tags.word_id, words.english, words.chinese, word_tags.known, word_tags.created_at, word_tags.updated_at").
+		Joins("LEFT JOIN words ON words.id = word_tags.word_id").
+		Where("word_tags.user_id = ?", userID).
+		Order("word_tags.created_at").
+		Scan(&tags).Error; err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to list word tags for export")
+		return nil, fmt.Errorf("failed to list word tags for export: %w", err)
+	}
+	return tags, nil
+}
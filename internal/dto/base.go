@@ -1,7 +1,9 @@
 package dto
 
 type BaseList struct {
-	PageNum  int    `form:"pageNum" json:"pageNum"`   // 页码，从1开始（可选）
-	PageSize int    `form:"pageSize" json:"pageSize"` // 每页大小（可选）
-	Sort     string `form:"sort" json:"sort"`         // 排序字段
+	PageNum   int    `form:"pageNum" json:"pageNum"`     // 页码，从1开始（可选）
+	PageSize  int    `form:"pageSize" json:"pageSize"`   // 每页大小（可选）
+	Sort      string `form:"sort" json:"sort"`           // 排序字段，如 difficulty,english|desc（可选）
+	Cursor    string `form:"cursor" json:"cursor"`       // 游标分页：首页传 start，之后传 nextCursor（可选）
+	SkipTotal bool   `form:"skipTotal" json:"skipTotal"` // 不统计总数，total 返回 -1（可选）
 }
@@ -0,0 +1,30 @@
+package dto
+
+// Types of the events pushed to a user's clients
+const (
+	// EventWordMarked is sent when a word is marked as known; Data is a WordMarkStatus
+	EventWordMarked = "word.marked"
+	// EventWordUnmarked is sent when the mark of a word is removed; Data is a WordMarkStatus
+	EventWordUnmarked = "word.unmarked"
+	// EventWordsMarked is sent when several words are marked at once; Data is a WordsEvent
+	EventWordsMarked = "words.marked"
+	// EventWordsForgotten is sent when known words are forgotten; Data is a WordsEvent
+	EventWordsForgotten = "words.forgotten"
+	// EventProgress is sent after every change of the known words; Data is a UserProgressResponse
+	EventProgress = "progress"
+)
+
+// Event is a change of a user's data pushed to all of the user's clients.
+// On the event stream it is sent as the data of an event named after Type.
+type Event struct {
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// WordsEvent is the data of events about several words
+type WordsEvent struct {
+	WordIDs []string `json:"wordIds,omitempty"`
+	// All is set when all known words were forgotten; WordIDs is then empty
+	All bool `json:"all,omitempty"`
+}
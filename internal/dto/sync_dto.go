@@ -0,0 +1,77 @@
+package dto
+
+// Types of the operations a client syncs
+const (
+	SyncOpMark   = "mark"
+	SyncOpUnmark = "unmark"
+	// SyncOpReview is the answer to a review of a word: a correct answer
+	// marks the word as known, a wrong one removes the mark
+	SyncOpReview = "review"
+)
+
+// Statuses of synced operations
+const (
+	// SyncStatusApplied means the operation changed the word's mark
+	SyncStatusApplied = "applied"
+	// SyncStatusDuplicate means the operation was already synced before
+	SyncStatusDuplicate = "duplicate"
+	// SyncStatusStale means the mark was changed after the operation, which
+	// is therefore ignored
+	SyncStatusStale = "stale"
+	// SyncStatusRejected means the operation is invalid and never applies
+	SyncStatusRejected = "rejected"
+	// SyncStatusFailed means the operation could not be applied now; sync it again later
+	SyncStatusFailed = "failed"
+)
+
+// SyncRequest represents a sync of an offline client: the operations made
+// since the last sync, and the token returned by it
+type SyncRequest struct {
+	// SyncToken is the token of the last sync; empty for the first sync,
+	// which returns all tags
+	SyncToken  string          `json:"syncToken"`
+	Operations []SyncOperation `json:"operations" binding:"max=500,dive"`
+	// Lang is the language of the error messages
+	Lang string `json:"-"`
+}
+
+// SyncOperation represents a change made by a client
+type SyncOperation struct {
+	// ID identifies the operation among the user's operations, e.g. a UUID;
+	// an operation synced again with the same ID is not applied twice
+	ID     string `json:"id" binding:"required,max=64"`
+	Type   string `json:"type" binding:"required,oneof=mark unmark review"`
+	WordID string `json:"wordId" binding:"required,uuid"`
+	// Timestamp is when the operation was made, in unix milliseconds by the
+	// client's clock. The latest change of a word wins.
+	Timestamp int64 `json:"timestamp" binding:"required,gt=0"`
+	// Correct is the answer of a review
+	Correct *bool `json:"correct,omitempty"`
+}
+
+// SyncResponse represents the result of a sync
+type SyncResponse struct {
+	// Results has the outcome of each operation, in request order
+	Results []SyncOperationResult `json:"results"`
+	// Changes are the tags changed since the last sync, oldest first
+	Changes []SyncChange `json:"changes"`
+	// SyncToken is to be sent with the next sync
+	SyncToken string `json:"syncToken"`
+	// HasMore is set when there are more changes; sync again right away
+	HasMore bool `json:"hasMore"`
+}
+
+// SyncOperationResult represents the outcome of a synced operation
+type SyncOperationResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SyncChange represents the current mark of a word changed since the last sync
+type SyncChange struct {
+	WordID    string `json:"wordId"`
+	IsMarked  bool   `json:"isMarked"`
+	MarkedAt  int64  `json:"markedAt,omitempty"`
+	ChangedAt int64  `json:"changedAt"`
+}
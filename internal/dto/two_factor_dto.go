@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"github.com/sanmu2018/word-hero/internal/table"
+)
+
+// LoginResult represents the outcome of a login step. Either Token is set,
+// or ChallengeToken is set and the login must be completed with a 2FA code.
+type LoginResult struct {
+	User                   *table.User
+	Token                  string
+	ChallengeToken         string
+	TwoFactorSetupRequired bool
+}
+
+// Login2FARequest completes a login with a TOTP or recovery code
+type Login2FARequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32"`
+	ClientIP       string `json:"-"`
+}
+
+// TwoFactorSetupResponse contains the pending TOTP secret for enrollment
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"` // PNG as a data URL
+}
+
+// TwoFactorCodeRequest carries a TOTP code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// TwoFactorDisableRequest represents a request to turn 2FA off
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required,max=32"`
+}
+
+// RecoveryCodesResponse contains newly generated recovery codes, shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorStatusResponse describes the 2FA state of the current user
+type TwoFactorStatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// SecuritySettings represents the security settings administrators can change.
+// Updates only change the settings present in the request.
+type SecuritySettings struct {
+	Require2FAForAdmin *bool `json:"require_2fa_for_admin,omitempty"`
+	LDAPLinkByEmail    *bool `json:"ldap_link_by_email,omitempty"`
+}
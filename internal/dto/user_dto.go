@@ -1,5 +1,9 @@
 package dto
 
+import (
+	"github.com/sanmu2018/word-hero/internal/table"
+)
+
 // UserRegisterRequest represents a user registration request
 type UserRegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
@@ -12,21 +16,36 @@ type UserRegisterRequest struct {
 type UserLoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	ClientIP string `json:"-"`
 }
 
 // UserResponse represents a user response (without sensitive data)
 type UserResponse struct {
-	ID        string  `json:"id"`
-	Username  string  `json:"username"`
-	Email     string  `json:"email"`
-	FullName  string  `json:"full_name"`
-	AvatarURL string  `json:"avatar_url"`
-	Bio       string  `json:"bio"`
-	Role      string  `json:"role"`
-	IsActive  bool    `json:"is_active"`
-	LastLogin *int64  `json:"last_login,omitempty"`
-	CreatedAt int64   `json:"createdAt"`
-	UpdatedAt int64   `json:"updatedAt"`
+	ID              string `json:"id"`
+	Username        string `json:"username"`
+	Email           string `json:"email"`
+	FullName        string `json:"full_name"`
+	AvatarURL       string `json:"avatar_url"`
+	Bio             string `json:"bio"`
+	Role            string `json:"role"`
+	IsActive        bool   `json:"is_active"`
+	LastLogin       *int64 `json:"last_login,omitempty"`
+	EmailVerifiedAt *int64 `json:"email_verified_at,omitempty"`
+	TOTPEnabled     bool   `json:"totp_enabled"`
+	Language        string `json:"language"`
+	CreatedAt       int64  `json:"createdAt"`
+	UpdatedAt       int64  `json:"updatedAt"`
+}
+
+// AuthResponse is returned when a user logs in. After a correct password
+// it may instead ask for a 2FA code, with TwoFactorRequired and ChallengeToken.
+type AuthResponse struct {
+	User                      *UserResponse `json:"user,omitempty"`
+	Token                     string        `json:"token,omitempty"`
+	EmailVerificationRequired bool          `json:"emailVerificationRequired,omitempty"`
+	TwoFactorRequired         bool          `json:"twoFactorRequired,omitempty"`
+	ChallengeToken            string        `json:"challengeToken,omitempty"`
+	TwoFactorSetupRequired    bool          `json:"twoFactorSetupRequired,omitempty"`
 }
 
 // UserUpdateRequest represents a user profile update request
@@ -35,10 +54,120 @@ type UserUpdateRequest struct {
 	AvatarURL string `json:"avatar_url" binding:"max=255"`
 	Bio       string `json:"bio"`
 	Email     string `json:"email" binding:"omitempty,email,max=100"`
+	// Language is the preferred message language; "auto" clears it
+	Language string `json:"language" binding:"omitempty,oneof=zh-CN en auto"`
+}
+
+// VerifyEmailRequest represents an email verification request
+type VerifyEmailRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// ResendVerificationRequest represents a request to resend the verification email
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPasswordRequest represents a request to send a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents a password reset using an emailed token
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6,max=100"`
 }
 
 // ChangePasswordRequest represents a password change request
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
 	NewPassword     string `json:"new_password" binding:"required,min=6,max=100"`
-}
\ No newline at end of file
+}
+
+// OIDCProviderInfo describes an external login provider shown on the login page
+type OIDCProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// OIDCFlowState is kept in a cookie between the redirect to the provider and
+// the callback
+type OIDCFlowState struct {
+	Provider string `json:"p"`
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+}
+
+// OIDCCallbackParams are the query parameters an identity provider redirects
+// back with
+type OIDCCallbackParams struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// CreateAccessTokenRequest represents a request to create a personal access token
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"min=0,max=365"` // 0 means no expiry
+}
+
+// AccessTokenCreatedResponse contains a new personal access token, shown only once
+type AccessTokenCreatedResponse struct {
+	Token       string                     `json:"token"`
+	AccessToken *table.PersonalAccessToken `json:"accessToken"`
+}
+
+// GuestSessionResponse describes the guest session set in the guest cookie
+type GuestSessionResponse struct {
+	GuestID   string `json:"guestId"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+// DeleteAccountRequest represents a request to delete the current account
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+	Confirm  string `json:"confirm" binding:"required"` // must repeat the username
+	ClientIP string `json:"-"`
+}
+
+// DeleteAccountResponse tells when a deleted account will be purged for good
+type DeleteAccountResponse struct {
+	PurgeAt int64 `json:"purgeAt"`
+}
+
+// UserExportParams selects the format of a personal data export
+type UserExportParams struct {
+	Format string `form:"format" binding:"omitempty,oneof=zip json"` // zip (default) or json
+}
+
+// UserExport is the personal data archive of a user
+type UserExport struct {
+	ExportedAt   int64                       `json:"exportedAt"`
+	Profile      UserResponse                `json:"profile"`
+	WordTags     []WordTagExport             `json:"wordTags"`
+	Identities   []table.UserIdentity        `json:"identities"`
+	AccessTokens []table.PersonalAccessToken `json:"accessTokens"`
+	Settings     UserSettingsExport          `json:"settings"`
+}
+
+// WordTagExport is a word tag together with the word it refers to
+type WordTagExport struct {
+	WordID    string `json:"wordId"`
+	English   string `json:"english"`
+	Chinese   string `json:"chinese"`
+	Known     *int64 `json:"known"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// UserSettingsExport holds the account settings included in an export
+type UserSettingsExport struct {
+	EmailVerifiedAt        *int64 `json:"emailVerifiedAt,omitempty"`
+	TwoFactorEnabledAt     *int64 `json:"twoFactorEnabledAt,omitempty"`
+	RecoveryCodesRemaining int64  `json:"recoveryCodesRemaining"`
+}
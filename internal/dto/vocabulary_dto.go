@@ -13,6 +13,13 @@ type VocabularyList struct {
 type VocabularyPage struct {
 	Words      []table.Word `json:"words"`
 	TotalCount int64        `json:"totalCount"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// FacetCount is the number of words with a category or difficulty
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
 }
 
 // VocabularyStats represents vocabulary statistics
@@ -37,4 +44,5 @@ type VocabularyPageWithMarks struct {
 	PageNumber int                  `json:"pageNumber"`
 	PageSize   int                  `json:"pageSize"`
 	TotalPages int                  `json:"totalPages"`
+	NextCursor string               `json:"nextCursor,omitempty"`
 }
@@ -16,4 +16,5 @@ type Page struct {
 	PageNumber int          `json:"page_number"`
 	PageSize   int          `json:"page_size"`
 	TotalPages int          `json:"total_pages"`
+	NextCursor string       `json:"next_cursor,omitempty"`
 }
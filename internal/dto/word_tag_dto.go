@@ -8,6 +8,8 @@ import (
 type WordMarkRequest struct {
 	WordID string `json:"wordId" binding:"required,uuid"`
 	UserID string `json:"userId"`
+	// Lang is the language of the response message
+	Lang string `json:"-"`
 }
 
 // WordMarkResponse represents a response for word mark operations
@@ -65,6 +67,8 @@ type WordMarkStatus struct {
 type BatchWordMarkRequest struct {
 	WordIDs []string `json:"wordIds" binding:"required,min=1"`
 	UserID  string   `json:"userId" binding:"required,uuid"`
+	// Lang is the language of the response messages
+	Lang string `json:"-"`
 }
 
 // BatchWordMarkResponse represents a response for batch word mark operations
@@ -102,6 +106,8 @@ type UserWordStats struct {
 // ForgetWordsRequest represents a request to forget specific words
 type ForgetWordsRequest struct {
 	WordIDs []string `json:"wordIds" binding:"required,min=1"`
+	// Lang is the language of the response message
+	Lang string `json:"-"`
 }
 
 // ForgetWordsResponse represents response for forget words operation
@@ -114,6 +120,8 @@ type ForgetWordsResponse struct {
 // ForgetAllRequest represents a request to forget all words
 type ForgetAllRequest struct {
 	Confirm bool `json:"confirm" binding:"required"`
+	// Lang is the language of the response message
+	Lang string `json:"-"`
 }
 
 // ForgetAllResponse represents response for all forget operation
@@ -1,6 +1,84 @@
 package dto
 
+import (
+	"github.com/sanmu2018/word-hero/internal/table"
+)
+
 type WordSearchRequest struct {
 	Q string `form:"q"  json:"q"`
 	BaseList
+	WordFilterParams
+}
+
+// Match types of a word search result
+const (
+	MatchExact    = "exact"
+	MatchLemma    = "lemma"
+	MatchPrefix   = "prefix"
+	MatchContains = "contains"
+	MatchPinyin   = "pinyin"
+	MatchFuzzy    = "fuzzy"
+)
+
+// MatchTypes lists the match types from best to worst, indexed by MatchRank.
+// Lemmas appear twice: irregular forms rank above prefix matches, lemmas
+// guessed by suffix rules below them.
+var MatchTypes = []string{MatchExact, MatchLemma, MatchPrefix, MatchLemma, MatchContains, MatchPinyin, MatchFuzzy}
+
+// WordSearchResult is a ranked word search hit
+type WordSearchResult struct {
+	table.Word
+	// MatchRank orders results, see MatchTypes
+	MatchRank  int     `json:"-" gorm:"column:match_rank"`
+	Similarity float64 `json:"similarity" gorm:"column:similarity"`
+	MatchType  string  `json:"matchType" gorm:"-"`
+	// MatchedForm is the inflected form of the headword that was searched,
+	// set for lemma matches
+	MatchedForm string `json:"matchedForm,omitempty" gorm:"-"`
+	// Highlight is the matched field as HTML-escaped text with the matched
+	// fragment wrapped in <mark>
+	Highlight      string `json:"highlight" gorm:"-"`
+	HighlightField string `json:"highlightField" gorm:"-"`
+}
+
+// WordPatternSearchRequest searches words by regular expression or wildcard
+type WordPatternSearchRequest struct {
+	Q    string `form:"q" json:"q"`
+	Mode string `form:"mode" json:"mode"` // regex (default) or wildcard
+	BaseList
+}
+
+// WordSuggestRequest asks for autocomplete suggestions
+type WordSuggestRequest struct {
+	Q     string `form:"q" json:"q"`
+	Limit int    `form:"limit" json:"limit"`
+}
+
+// WordSuggestion is an autocomplete hit on an English headword or a Chinese gloss
+type WordSuggestion struct {
+	WordID  string `json:"wordId"`
+	English string `json:"english"`
+	Chinese string `json:"chinese"`
+	Field   string `json:"field"` // english or chinese
+	Match   string `json:"match"` // the headword or gloss that matched
+}
+
+// WordFilterParams filters word listings and searches. Filter takes the
+// compact syntax, e.g. "cat:noun known:false len:>8"; the other parameters
+// are the same filters as separate query parameters.
+type WordFilterParams struct {
+	Filter      string `form:"filter" json:"filter"`
+	Category    string `form:"category" json:"category"`       // comma separated
+	Difficulty  string `form:"difficulty" json:"difficulty"`   // comma separated
+	Known       string `form:"known" json:"known"`             // true or false
+	LearnedFrom string `form:"learnedFrom" json:"learnedFrom"` // YYYY-MM-DD, inclusive
+	LearnedTo   string `form:"learnedTo" json:"learnedTo"`     // YYYY-MM-DD, inclusive
+	MinLength   int    `form:"minLen" json:"minLen"`
+	MaxLength   int    `form:"maxLen" json:"maxLen"`
+}
+
+// WordListRequest lists words page by page with optional filters
+type WordListRequest struct {
+	BaseList
+	WordFilterParams
 }
@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/sanmu2018/word-hero/internal/table"
+)
+
+// caller is who makes a request, stored in the request context
+type caller struct {
+	// user is nil for anonymous calls
+	user    *table.User
+	lang    string
+	loaders *loaders
+}
+
+// callerKey is the context key of the caller
+type callerKey struct{}
+
+// callerFromContext returns the caller of the request, which Server.Serve
+// stores in the context
+func callerFromContext(ctx context.Context) *caller {
+	if c, ok := ctx.Value(callerKey{}).(*caller); ok {
+		return c
+	}
+	return &caller{}
+}
+
+// userIDFromContext returns the ID of the signed in user, or "" for
+// anonymous calls
+func userIDFromContext(ctx context.Context) string {
+	if user := callerFromContext(ctx).user; user != nil {
+		return user.ID
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/graph/model"
+)
+
+// baseList converts a page input, applying the defaults of the REST API
+func baseList(page *model.PageInput) dao.BaseList {
+	list := dao.BaseList{PageNum: 1, PageSize: pageSize(page)}
+	if page == nil {
+		return list
+	}
+	if page.PageNum != nil && *page.PageNum > 0 {
+		list.PageNum = *page.PageNum
+	}
+	if page.Sort != nil {
+		list.Sort = *page.Sort
+	}
+	if page.Cursor != nil {
+		list.Cursor = *page.Cursor
+	}
+	if page.SkipTotal != nil {
+		list.SkipTotal = *page.SkipTotal
+	}
+	return list
+}
+
+// filterParams converts a word filter input
+func filterParams(filter *model.WordFilterInput) dto.WordFilterParams {
+	var params dto.WordFilterParams
+	if filter == nil {
+		return params
+	}
+	params.Filter = value(filter.Filter)
+	params.Category = value(filter.Category)
+	params.Difficulty = value(filter.Difficulty)
+	params.Known = value(filter.Known)
+	params.LearnedFrom = value(filter.LearnedFrom)
+	params.LearnedTo = value(filter.LearnedTo)
+	params.MinLength = value(filter.MinLen)
+	params.MaxLength = value(filter.MaxLen)
+	return params
+}
+
+// value returns the value of an optional input, or its zero value
+func value[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/vikstrous/dataloadgen"
+
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/internal/table"
+)
+
+// loaderWait is how long loaders collect keys before fetching them in one
+// batch. Sibling fields of a list are resolved concurrently, so a short wait
+// is enough to catch all items of a page.
+const loaderWait = 2 * time.Millisecond
+
+// loaders batch the lookups of a request, so that the fields of the items of
+// a list are fetched with one query instead of one query per item. They also
+// cache results for the duration of the request.
+type loaders struct {
+	// words loads words by ID
+	words *dataloadgen.Loader[string, table.Word]
+	// markStatuses loads the caller's mark status of words by word ID; nil
+	// for anonymous calls
+	markStatuses *dataloadgen.Loader[string, dto.WordMarkStatus]
+}
+
+// newLoaders creates the loaders of a request made by userID, "" for
+// anonymous calls
+func newLoaders(vocabularyService *service.VocabularyService, wordTagService *service.WordTagService, userID string) *loaders {
+	l := &loaders{
+		words: dataloadgen.NewMappedLoader(func(ctx context.Context, ids []string) (map[string]table.Word, error) {
+			words, err := vocabularyService.GetWordsByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			wordMap := make(map[string]table.Word, len(words))
+			for _, word := range words {
+				wordMap[word.ID] = word
+			}
+			return wordMap, nil
+		}, dataloadgen.WithWait(loaderWait)),
+	}
+
+	if userID != "" {
+		l.markStatuses = dataloadgen.NewMappedLoader(func(ctx context.Context, wordIDs []string) (map[string]dto.WordMarkStatus, error) {
+			return wordTagService.GetMarkStatuses(userID, wordIDs)
+		}, dataloadgen.WithWait(loaderWait))
+	}
+
+	return l
+}
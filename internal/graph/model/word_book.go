@@ -0,0 +1,7 @@
+package model
+
+// WordBook is the word book the vocabulary was imported from. Its counts are
+// resolved on demand.
+type WordBook struct {
+	Name string `json:"name"`
+}
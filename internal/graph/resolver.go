@@ -0,0 +1,10 @@
+package graph
+
+import "github.com/sanmu2018/word-hero/internal/service"
+
+// Resolver is the root resolver of the GraphQL API
+type Resolver struct {
+	vocabularyService *service.VocabularyService
+	wordTagService    *service.WordTagService
+	wordBookName      string
+}
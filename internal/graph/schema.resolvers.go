@@ -0,0 +1,199 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.95
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/graph/model"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+	"github.com/vikstrous/dataloadgen"
+)
+
+// Words is the resolver for the words field.
+func (r *queryResolver) Words(ctx context.Context, page *model.PageInput, filter *model.WordFilterInput) (*model.WordPage, error) {
+	wordFilter, err := r.vocabularyService.BuildWordFilter(filterParams(filter), nil, userIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	list := baseList(page)
+	vocabPage, err := r.vocabularyService.ListWords(&list, wordFilter)
+	if err != nil {
+		log.Error(err).Int("page", list.PageNum).Msg("Failed to list words for GraphQL")
+		return nil, err
+	}
+
+	return &model.WordPage{
+		Items:      vocabPage.Words,
+		Total:      int(vocabPage.TotalCount),
+		NextCursor: vocabPage.NextCursor,
+	}, nil
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, query string, page *model.PageInput, filter *model.WordFilterInput) (*model.SearchPage, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	list := baseList(page)
+	total, results, next, err := r.vocabularyService.SearchWords(dto.WordSearchRequest{
+		Q: query,
+		BaseList: dto.BaseList{
+			PageNum:   list.PageNum,
+			PageSize:  list.PageSize,
+			Sort:      list.Sort,
+			Cursor:    list.Cursor,
+			SkipTotal: list.SkipTotal,
+		},
+		WordFilterParams: filterParams(filter),
+	}, userIDFromContext(ctx))
+	if err != nil {
+		log.Error(err).Str("query", query).Msg("Search failed")
+		return nil, err
+	}
+
+	return &model.SearchPage{
+		Items:      results,
+		Total:      int(total),
+		NextCursor: next,
+	}, nil
+}
+
+// Word is the resolver for the word field.
+func (r *queryResolver) Word(ctx context.Context, id string) (*table.Word, error) {
+	word, err := callerFromContext(ctx).loaders.words.Load(ctx, id)
+	if errors.Is(err, dataloadgen.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// WordBook is the resolver for the wordBook field.
+func (r *queryResolver) WordBook(ctx context.Context) (*model.WordBook, error) {
+	return &model.WordBook{Name: r.wordBookName}, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*table.User, error) {
+	return callerFromContext(ctx).user, nil
+}
+
+// TotpEnabled is the resolver for the totpEnabled field.
+func (r *userResolver) TotpEnabled(ctx context.Context, obj *table.User) (bool, error) {
+	return obj.TOTPEnabledAt != nil, nil
+}
+
+// Progress is the resolver for the progress field.
+func (r *userResolver) Progress(ctx context.Context, obj *table.User) (*dto.UserProgressResponse, error) {
+	progress, err := r.wordTagService.GetUserProgress(obj.ID)
+	if err != nil {
+		log.Error(err).Str("user_id", obj.ID).Msg("Failed to get user progress")
+		return nil, err
+	}
+	return progress, nil
+}
+
+// KnownWords is the resolver for the knownWords field.
+func (r *userResolver) KnownWords(ctx context.Context, obj *table.User, page *model.PageInput) (*model.WordPage, error) {
+	list := baseList(page)
+	known, err := r.vocabularyService.GetKnownWordsByUser(obj.ID, &list)
+	if err != nil {
+		log.Error(err).Str("user_id", obj.ID).Msg("Failed to get known words")
+		return nil, err
+	}
+
+	// The page already has the tags of its words, which are the caller's
+	markStatuses := callerFromContext(ctx).loaders.markStatuses
+	words := make([]table.Word, 0, len(known.Words))
+	for _, word := range known.Words {
+		words = append(words, word.Word)
+		if markStatuses != nil {
+			markStatuses.Prime(word.ID, dto.WordMarkStatus{
+				WordID:    word.ID,
+				IsMarked:  word.IsMarked,
+				MarkCount: word.MarkCount,
+				MarkedAt:  word.MarkedAt,
+			})
+		}
+	}
+
+	return &model.WordPage{
+		Items:      words,
+		Total:      known.TotalCount,
+		NextCursor: known.NextCursor,
+	}, nil
+}
+
+// Tag is the resolver for the tag field.
+func (r *wordResolver) Tag(ctx context.Context, obj *table.Word) (*dto.WordMarkStatus, error) {
+	markStatuses := callerFromContext(ctx).loaders.markStatuses
+	if markStatuses == nil {
+		return nil, nil
+	}
+
+	status, err := markStatuses.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// TotalWords is the resolver for the totalWords field.
+func (r *wordBookResolver) TotalWords(ctx context.Context, obj *model.WordBook) (int, error) {
+	return r.vocabularyService.GetWordCount(), nil
+}
+
+// Categories is the resolver for the categories field.
+func (r *wordBookResolver) Categories(ctx context.Context, obj *model.WordBook) ([]dto.FacetCount, error) {
+	return r.vocabularyService.CountWordsBy("category")
+}
+
+// Difficulties is the resolver for the difficulties field.
+func (r *wordBookResolver) Difficulties(ctx context.Context, obj *model.WordBook) ([]dto.FacetCount, error) {
+	return r.vocabularyService.CountWordsBy("difficulty")
+}
+
+// MarkedAt is the resolver for the markedAt field.
+func (r *wordTagResolver) MarkedAt(ctx context.Context, obj *dto.WordMarkStatus) (*int64, error) {
+	if !obj.IsMarked {
+		return nil, nil
+	}
+	return &obj.MarkedAt, nil
+}
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+// Word returns WordResolver implementation.
+func (r *Resolver) Word() WordResolver { return &wordResolver{r} }
+
+// WordBook returns WordBookResolver implementation.
+func (r *Resolver) WordBook() WordBookResolver { return &wordBookResolver{r} }
+
+// WordTag returns WordTagResolver implementation.
+func (r *Resolver) WordTag() WordTagResolver { return &wordTagResolver{r} }
+
+type (
+	queryResolver    struct{ *Resolver }
+	userResolver     struct{ *Resolver }
+	wordResolver     struct{ *Resolver }
+	wordBookResolver struct{ *Resolver }
+	wordTagResolver  struct{ *Resolver }
+)
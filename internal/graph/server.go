@@ -0,0 +1,205 @@
+// Package graph serves the GraphQL API, which lets clients fetch words with
+// their tags, the user's progress and the word book stats in one request.
+// The resolvers are thin wrappers of the same services as the REST API.
+//
+// The schema is in schema.graphqls; generated.go and model/models_gen.go are
+// generated from it with `make graphql`.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/graph/model"
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// Server is the GraphQL server
+type Server struct {
+	resolver *Resolver
+	handler  *handler.Server
+}
+
+// NewServer creates a new GraphQL server. wordBookName is the name of the
+// word book the vocabulary was imported from.
+func NewServer(vocabularyService *service.VocabularyService, wordTagService *service.WordTagService, wordBookName string, limits *conf.GraphQLConfig) *Server {
+	resolver := &Resolver{
+		vocabularyService: vocabularyService,
+		wordTagService:    wordTagService,
+		wordBookName:      wordBookName,
+	}
+
+	config := Config{Resolvers: resolver}
+	// Lists cost their fields once per item of the requested page
+	config.Complexity.Query.Words = func(childComplexity int, page *model.PageInput, filter *model.WordFilterInput) int {
+		return 1 + childComplexity*pageSize(page)
+	}
+	config.Complexity.Query.Search = func(childComplexity int, query string, page *model.PageInput, filter *model.WordFilterInput) int {
+		return 1 + childComplexity*pageSize(page)
+	}
+	config.Complexity.User.KnownWords = func(childComplexity int, page *model.PageInput) int {
+		return 1 + childComplexity*pageSize(page)
+	}
+	schema := NewExecutableSchema(config)
+
+	h := handler.New(schema)
+	h.AddTransport(transport.GET{})
+	h.AddTransport(transport.POST{})
+	h.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	h.Use(extension.Introspection{})
+	h.Use(&queryLimits{maxDepth: limits.MaxDepth, maxComplexity: limits.MaxComplexity})
+	h.SetErrorPresenter(presentError)
+	h.SetRecoverFunc(func(ctx context.Context, err interface{}) error {
+		log.Error(fmt.Errorf("%v", err)).Msg("Panic in GraphQL resolver")
+		return pke.NewApiError(pke.CodeSystemError)
+	})
+
+	return &Server{resolver: resolver, handler: h}
+}
+
+// Serve serves a GraphQL request made by user, nil for anonymous calls.
+// Messages are in lang.
+func (s *Server) Serve(w http.ResponseWriter, r *http.Request, user *table.User, lang string) {
+	userID := ""
+	if user != nil {
+		userID = user.ID
+	}
+	ctx := context.WithValue(r.Context(), callerKey{}, &caller{
+		user:    user,
+		lang:    lang,
+		loaders: newLoaders(s.resolver.vocabularyService, s.resolver.wordTagService, userID),
+	})
+	s.handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// pageSize returns the size of a requested page, applying the default of the
+// REST API
+func pageSize(page *model.PageInput) int {
+	if page == nil || page.PageSize == nil || *page.PageSize <= 0 {
+		return 12
+	}
+	return *page.PageSize
+}
+
+// presentError reports API and domain errors with the localized message of
+// their code, like the REST API, and the code in the "code" extension. Other
+// resolver errors are reported as system errors and only logged.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	code := pke.CodeSystemError
+	detail := ""
+	var detailArgs []interface{}
+	var h *pke.APIResponse
+	var de *service.DomainError
+	if errors.As(err, &h) {
+		code = h.ErrorNo()
+		detail = h.Detail
+		detailArgs = h.DetailArgs
+	} else if errors.As(err, &de) {
+		code = de.ErrorNo()
+		detail = de.Detail
+	} else if gqlErr.Err == nil {
+		// Parse and validation errors of the query
+		return gqlErr
+	} else {
+		log.Error(err).Str("path", gqlErr.Path.String()).Msg("Unhandled error in GraphQL resolver")
+	}
+
+	lang := callerFromContext(ctx).lang
+	gqlErr.Message = i18n.ErrorMessage(lang, code)
+	if detail != "" {
+		gqlErr.Message += ": " + i18n.T(lang, detail, detailArgs...)
+	}
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = make(map[string]interface{})
+	}
+	gqlErr.Extensions["code"] = code
+	return gqlErr
+}
+
+// queryLimits rejects operations that nest fields too deeply or resolve too
+// many fields, before they run
+type queryLimits struct {
+	maxDepth      int
+	maxComplexity int
+	schema        graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &queryLimits{}
+
+func (l *queryLimits) ExtensionName() string {
+	return "QueryLimits"
+}
+
+func (l *queryLimits) Validate(schema graphql.ExecutableSchema) error {
+	l.schema = schema
+	return nil
+}
+
+func (l *queryLimits) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	lang := callerFromContext(ctx).lang
+	op := opCtx.Operation
+
+	if depth := selectionDepth(op.SelectionSet); l.maxDepth > 0 && depth > l.maxDepth {
+		return limitError(lang, "graphql.depth_exceeded", depth, l.maxDepth)
+	}
+
+	if cost := complexity.Calculate(ctx, l.schema, op, opCtx.Variables); l.maxComplexity > 0 && cost > l.maxComplexity {
+		return limitError(lang, "graphql.complexity_exceeded", cost, l.maxComplexity)
+	}
+
+	return nil
+}
+
+// limitError reports an operation over a query limit
+func limitError(lang, key string, value, limit int) *gqlerror.Error {
+	err := gqlerror.Errorf("%s: %s", i18n.ErrorMessage(lang, pke.CodeInvalidRequest), i18n.T(lang, key, value, limit))
+	err.Extensions = map[string]interface{}{"code": pke.CodeInvalidRequest}
+	return err
+}
+
+// selectionDepth returns how deeply the fields of set are nested, following
+// fragments. Introspection fields are not counted; fragment cycles have been
+// rejected by validation.
+func selectionDepth(set ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range set {
+		d := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+type testResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// query runs query on a server without services as an anonymous caller,
+// enough for queries that fail or resolve before reaching a service
+func query(t *testing.T, limits conf.GraphQLConfig, q string) testResponse {
+	t.Helper()
+	s := NewServer(nil, nil, "IELTS", &limits)
+
+	body, _ := json.Marshal(map[string]string{"query": q})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.Serve(w, req, nil, i18n.EN)
+
+	var resp testResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", w.Body.String(), err)
+	}
+	return resp
+}
+
+func errorCode(resp testResponse) int {
+	if len(resp.Errors) == 0 {
+		return 0
+	}
+	code, _ := resp.Errors[0].Extensions["code"].(float64)
+	return int(code)
+}
+
+func TestAnonymousQuery(t *testing.T) {
+	resp := query(t, conf.GraphQLConfig{MaxDepth: 8, MaxComplexity: 1000}, `{ wordBook { name } me { id } }`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors %v", resp.Errors)
+	}
+	book, _ := resp.Data["wordBook"].(map[string]interface{})
+	if book["name"] != "IELTS" {
+		t.Errorf("Expected word book IELTS, got %v", resp.Data)
+	}
+	if resp.Data["me"] != nil {
+		t.Errorf("Expected no user for anonymous calls, got %v", resp.Data["me"])
+	}
+}
+
+func TestSearchValidation(t *testing.T) {
+	resp := query(t, conf.GraphQLConfig{MaxDepth: 8, MaxComplexity: 1000}, `{ search(query: "  ") { total } }`)
+	if code := errorCode(resp); code != pke.CodeInvalidRequest {
+		t.Fatalf("Expected code %d, got %v", pke.CodeInvalidRequest, resp.Errors)
+	}
+	if msg := i18n.ErrorMessage(i18n.EN, pke.CodeInvalidRequest); resp.Errors[0].Message != msg {
+		t.Errorf("Expected English message %q, got %q", msg, resp.Errors[0].Message)
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	limits := conf.GraphQLConfig{MaxDepth: 3, MaxComplexity: 100}
+	cases := []struct {
+		name  string
+		query string
+		code  int
+	}{
+		{name: "within limits", query: `{ words(page: {pageSize: 5}) { items { id english } } }`},
+		{name: "too deep", query: `{ me { knownWords { items { tag { wordId } } } } }`, code: pke.CodeInvalidRequest},
+		{name: "too deep through fragment", query: `{ me { ...known } } fragment known on User { knownWords { items { id } } }`, code: pke.CodeInvalidRequest},
+		{name: "too complex", query: `{ words(page: {pageSize: 100}) { items { id english } } }`, code: pke.CodeInvalidRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := query(t, limits, tc.query)
+			// Queries within the limits reach the nil services and fail there
+			if tc.code == 0 && len(resp.Errors) > 0 && errorCode(resp) != pke.CodeSystemError {
+				t.Errorf("Expected the query to run, got %v", resp.Errors)
+			}
+			if tc.code != 0 && (errorCode(resp) != tc.code || resp.Data != nil) {
+				t.Errorf("Expected rejection with code %d, got %v", tc.code, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestSelectionDepth(t *testing.T) {
+	cases := map[string]int{
+		`{ wordBook { name } }`:                                   2,
+		`{ __schema { types { name } } wordBook { name } }`:       2,
+		`{ a: wordBook { name } me { progress { totalWords } } }`: 3,
+		`{ ... on Query { me { id } } }`:                          2,
+	}
+	for q, want := range cases {
+		doc, err := parser.ParseQuery(&ast.Source{Input: q})
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", q, err)
+		}
+		if got := selectionDepth(doc.Operations[0].SelectionSet); got != want {
+			t.Errorf("selectionDepth(%q) = %d, want %d", q, got, want)
+		}
+	}
+}
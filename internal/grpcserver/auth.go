@@ -0,0 +1,98 @@
+package grpcserver
+
+import (
+	"context"
+
+	wordherov1 "github.com/sanmu2018/word-hero/api/wordhero/v1"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// authServer implements wordherov1.AuthServiceServer
+type authServer struct {
+	wordherov1.UnimplementedAuthServiceServer
+	*Server
+}
+
+// Login logs in with username and password
+func (s *authServer) Login(ctx context.Context, req *wordherov1.LoginRequest) (*wordherov1.LoginResponse, error) {
+	if req.GetUsername() == "" || req.GetPassword() == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	result, err := s.authService.Login(&dto.UserLoginRequest{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+		ClientIP: clientIP(ctx),
+	})
+	if err != nil {
+		log.Error(err).Str("username", req.GetUsername()).Msg("Login failed")
+		return nil, err
+	}
+
+	// Password accepted, the client must now submit a 2FA code
+	if result.ChallengeToken != "" {
+		return &wordherov1.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+		}, nil
+	}
+
+	log.Info().Str("user_id", result.User.ID).Str("username", result.User.Username).Msg("User logged in over gRPC")
+
+	return &wordherov1.LoginResponse{
+		Token:                  result.Token,
+		User:                   toUser(result.User),
+		TwoFactorSetupRequired: result.TwoFactorSetupRequired,
+	}, nil
+}
+
+// LoginTwoFactor completes a login with a TOTP or recovery code
+func (s *authServer) LoginTwoFactor(ctx context.Context, req *wordherov1.LoginTwoFactorRequest) (*wordherov1.LoginTwoFactorResponse, error) {
+	if req.GetChallengeToken() == "" || req.GetCode() == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	result, err := s.authService.Login2FA(&dto.Login2FARequest{
+		ChallengeToken: req.GetChallengeToken(),
+		Code:           req.GetCode(),
+		ClientIP:       clientIP(ctx),
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("2FA login failed")
+		return nil, err
+	}
+
+	log.Info().Str("user_id", result.User.ID).Str("username", result.User.Username).Msg("User logged in with 2FA over gRPC")
+
+	return &wordherov1.LoginTwoFactorResponse{
+		Token:                  result.Token,
+		User:                   toUser(result.User),
+		TwoFactorSetupRequired: result.TwoFactorSetupRequired,
+	}, nil
+}
+
+// GetCurrentUser gets the user the token belongs to
+func (s *authServer) GetCurrentUser(ctx context.Context, req *wordherov1.GetCurrentUserRequest) (*wordherov1.GetCurrentUserResponse, error) {
+	user := userFromContext(ctx)
+	if user == nil {
+		return nil, pke.NewApiError(pke.CodeUserNotFound)
+	}
+	return &wordherov1.GetCurrentUserResponse{User: toUser(user)}, nil
+}
+
+// toUser converts a user, without sensitive data
+func toUser(user *table.User) *wordherov1.User {
+	return &wordherov1.User{
+		Id:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		FullName:    user.FullName,
+		Role:        user.Role,
+		Language:    user.Language,
+		TotpEnabled: user.TOTPEnabledAt != nil,
+		CreatedAt:   user.CreatedAt,
+	}
+}
@@ -0,0 +1,102 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// errorDomain is the domain of the ErrorInfo details of statuses
+const errorDomain = "word-hero"
+
+// statusCodes maps the HTTP statuses of pke codes to gRPC codes
+var statusCodes = map[int]codes.Code{
+	400: codes.InvalidArgument,
+	401: codes.Unauthenticated,
+	403: codes.PermissionDenied,
+	404: codes.NotFound,
+	405: codes.Unimplemented,
+	408: codes.DeadlineExceeded,
+	409: codes.AlreadyExists,
+	413: codes.ResourceExhausted,
+	429: codes.ResourceExhausted,
+	503: codes.Unavailable,
+	504: codes.DeadlineExceeded,
+}
+
+// grpcCode returns the gRPC code of a pke code
+func grpcCode(code int) codes.Code {
+	if c, ok := statusCodes[pke.HTTPStatus(code)]; ok {
+		return c
+	}
+	return codes.Internal
+}
+
+// toStatus converts an error returned by a handler to a gRPC status, the
+// counterpart of writeError in the REST API. Errors that are not API or
+// domain errors are reported as system errors and only logged.
+func toStatus(ctx context.Context, method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := pke.CodeSystemError
+	detail := ""
+	var detailArgs []interface{}
+	var h *pke.APIResponse
+	var de *service.DomainError
+	if errors.As(err, &h) {
+		code = h.ErrorNo()
+		detail = h.Detail
+		detailArgs = h.DetailArgs
+	} else if errors.As(err, &de) {
+		code = de.ErrorNo()
+		detail = de.Detail
+	} else {
+		log.Error(err).Str("method", method).Msg("Unhandled error in gRPC handler")
+	}
+
+	// 错误消息按调用的语言输出
+	lang := language(ctx)
+	msg := i18n.ErrorMessage(lang, code)
+	if detail != "" {
+		msg += ": " + i18n.T(lang, detail, detailArgs...)
+	}
+
+	var retryAfter time.Duration
+	var ra *pke.RetryAfterError
+	if errors.As(err, &ra) {
+		retryAfter = ra.RetryAfter
+	}
+	return newStatus(code, msg, retryAfter)
+}
+
+// newStatus creates the status of a pke code. The code is attached as the
+// reason of an ErrorInfo so clients can tell errors apart, and rate limits carry
+// RetryInfo.
+func newStatus(code int, msg string, retryAfter time.Duration) error {
+	st := status.New(grpcCode(code), msg)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: strconv.Itoa(code),
+		Domain: errorDomain,
+	}}
+	if retryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,237 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	wordherov1 "github.com/sanmu2018/word-hero/api/wordhero/v1"
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// authMode is how a method authenticates its caller
+type authMode int
+
+const (
+	// authRequired rejects calls without a valid token
+	authRequired authMode = iota
+	// authNone ignores the token
+	authNone
+	// authOptional identifies the caller when a valid token is sent
+	authOptional
+)
+
+// methodAccess is the access rule of a method, the counterpart of the
+// middlewares of a REST route
+type methodAccess struct {
+	auth authMode
+	// scope is required of personal access tokens
+	scope string
+	// pending2FA lets users through who still have to enroll in 2FA
+	pending2FA bool
+}
+
+// methodAccesses holds the access rules by full method name. Methods not
+// listed require authentication.
+var methodAccesses = map[string]methodAccess{
+	wordherov1.AuthService_Login_FullMethodName:          {auth: authNone},
+	wordherov1.AuthService_LoginTwoFactor_FullMethodName: {auth: authNone},
+	wordherov1.AuthService_GetCurrentUser_FullMethodName: {scope: table.ScopeReadUser, pending2FA: true},
+
+	wordherov1.VocabularyService_ListWords_FullMethodName:   {auth: authOptional, scope: table.ScopeReadWords},
+	wordherov1.VocabularyService_SearchWords_FullMethodName: {auth: authOptional, scope: table.ScopeReadWords},
+	wordherov1.VocabularyService_GetWord_FullMethodName:     {auth: authOptional},
+
+	wordherov1.WordTagService_MarkWord_FullMethodName:           {scope: table.ScopeWriteTags},
+	wordherov1.WordTagService_UnmarkWord_FullMethodName:         {scope: table.ScopeWriteTags},
+	wordherov1.WordTagService_BatchMarkWords_FullMethodName:     {scope: table.ScopeWriteTags},
+	wordherov1.WordTagService_BatchGetMarkStatus_FullMethodName: {scope: table.ScopeReadWords},
+	wordherov1.WordTagService_GetProgress_FullMethodName:        {scope: table.ScopeReadWords},
+}
+
+// accessOf returns the access rule of method
+func accessOf(method string) methodAccess {
+	if strings.HasPrefix(method, "/grpc.reflection.") {
+		return methodAccess{auth: authNone}
+	}
+	if access, ok := methodAccesses[method]; ok {
+		return access
+	}
+	return methodAccess{auth: authRequired}
+}
+
+// userKey is the context key of the authenticated user
+type userKey struct{}
+
+// userFromContext returns the authenticated user, or nil for anonymous calls
+func userFromContext(ctx context.Context) *table.User {
+	user, _ := ctx.Value(userKey{}).(*table.User)
+	return user
+}
+
+// userIDFromContext returns the ID of the authenticated user, or "" for
+// anonymous calls
+func userIDFromContext(ctx context.Context) string {
+	if user := userFromContext(ctx); user != nil {
+		return user.ID
+	}
+	return ""
+}
+
+// language returns the language of messages for the call: the caller's
+// preference, else the best match of the accept-language metadata
+func language(ctx context.Context) string {
+	if user := userFromContext(ctx); user != nil && user.Language != "" {
+		return user.Language
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return i18n.Match(strings.Join(md.Get("accept-language"), ","))
+}
+
+// clientIP returns the IP address of the caller
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// authInterceptor authenticates unary calls according to methodAccesses
+func (s *Server) authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuthInterceptor authenticates streaming calls according to methodAccesses
+func (s *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+}
+
+// authStream is a server stream carrying the authenticated context
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize checks the token of a call to method and returns the context
+// with the authenticated user
+func (s *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	access := accessOf(method)
+	if access.auth == authNone {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	authHeader := strings.Join(md.Get("authorization"), "")
+	if authHeader == "" {
+		if access.auth == authOptional {
+			return ctx, nil
+		}
+		return nil, reject(ctx, pke.CodeLoginRequired, "auth.header_required")
+	}
+
+	// Extract token from "Bearer <token>"
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		if access.auth == authOptional {
+			return ctx, nil
+		}
+		return nil, reject(ctx, pke.CodeInvalidToken, "auth.bearer_required")
+	}
+
+	// Validate token, either a personal access token or a JWT session
+	var user *table.User
+	var scopes []string
+	var err error
+	if service.IsAccessToken(tokenString) {
+		user, scopes, err = s.accessTokenService.Authenticate(tokenString, clientIP(ctx))
+	} else {
+		user, err = s.authService.ValidateToken(tokenString)
+	}
+	if err != nil {
+		if access.auth == authOptional {
+			return ctx, nil
+		}
+		return nil, reject(ctx, pke.CodeInvalidToken, "")
+	}
+
+	if !access.pending2FA && s.authService.TwoFactorSetupRequired(user) {
+		if access.auth == authOptional {
+			return ctx, nil
+		}
+		return nil, reject(ctx, pke.CodeTwoFactorSetupRequired, "")
+	}
+
+	ctx = context.WithValue(ctx, userKey{}, user)
+	if scopes != nil && access.scope != "" && !slices.Contains(scopes, access.scope) {
+		return nil, reject(ctx, pke.CodePermissionDenied, "auth.scope_required", access.scope)
+	}
+
+	return ctx, nil
+}
+
+// reject returns the status of a failed authentication. detail, a message key
+// or text, is appended to the code's message if not empty.
+func reject(ctx context.Context, code int, detail string, args ...interface{}) error {
+	lang := language(ctx)
+	msg := i18n.ErrorMessage(lang, code)
+	if detail != "" {
+		msg += ": " + i18n.T(lang, detail, args...)
+	}
+	return newStatus(code, msg, 0)
+}
+
+// errorInterceptor converts the errors of handlers to gRPC statuses
+func errorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, info.FullMethod, err)
+	}
+	return resp, nil
+}
+
+// loggingInterceptor logs gRPC calls with performance metrics
+func (s *Server) loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	// Process call
+	resp, err := handler(ctx, req)
+
+	// Log the call
+	duration := time.Since(start)
+	log.Info().
+		Str("method", info.FullMethod).
+		Str("ip", clientIP(ctx)).
+		Str("code", status.Code(err).String()).
+		Dur("duration", duration).
+		Msg("gRPC request")
+
+	return resp, err
+}
@@ -0,0 +1,65 @@
+// Package grpcserver serves the gRPC API used by internal tools. It exposes
+// the vocabulary, word tag and auth services on top of the same service layer
+// as the REST API.
+package grpcserver
+
+import (
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	wordherov1 "github.com/sanmu2018/word-hero/api/wordhero/v1"
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// Server is the gRPC server
+type Server struct {
+	vocabularyService  *service.VocabularyService
+	wordTagService     *service.WordTagService
+	authService        *service.AuthService
+	accessTokenService *service.AccessTokenService
+	server             *grpc.Server
+}
+
+// NewServer creates a new gRPC server. With enableReflection, clients such as
+// grpcurl can list the services and their messages.
+func NewServer(vocabularyService *service.VocabularyService, wordTagService *service.WordTagService, authService *service.AuthService, accessTokenService *service.AccessTokenService, enableReflection bool) *Server {
+	s := &Server{
+		vocabularyService:  vocabularyService,
+		wordTagService:     wordTagService,
+		authService:        authService,
+		accessTokenService: accessTokenService,
+	}
+
+	s.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.loggingInterceptor, s.authInterceptor, errorInterceptor),
+		grpc.ChainStreamInterceptor(s.streamAuthInterceptor),
+	)
+	wordherov1.RegisterVocabularyServiceServer(s.server, &vocabularyServer{Server: s})
+	wordherov1.RegisterWordTagServiceServer(s.server, &wordTagServer{Server: s})
+	wordherov1.RegisterAuthServiceServer(s.server, &authServer{Server: s})
+	if enableReflection {
+		reflection.Register(s.server)
+	}
+
+	return s
+}
+
+// Start starts the gRPC server on port and blocks until it stops
+func (s *Server) Start(port int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %d: %w", port, err)
+	}
+
+	log.Info().Int("port", port).Msg("Starting gRPC server")
+	return s.server.Serve(listener)
+}
+
+// Stop stops the server after pending calls have finished
+func (s *Server) Stop() {
+	s.server.GracefulStop()
+}
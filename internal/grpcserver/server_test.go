@@ -0,0 +1,168 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	wordherov1 "github.com/sanmu2018/word-hero/api/wordhero/v1"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// dialTestServer serves a server without services over an in-memory
+// connection, enough for calls that fail before reaching a service
+func dialTestServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	s := NewServer(nil, nil, nil, nil, true)
+	go s.server.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial test server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	conn := dialTestServer(t)
+	client := wordherov1.NewWordTagServiceClient(conn)
+
+	cases := []struct {
+		name   string
+		header string
+		code   codes.Code
+	}{
+		{name: "without token", header: "", code: codes.Unauthenticated},
+		{name: "without bearer prefix", header: "Token abc", code: codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if tc.header != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tc.header)
+			}
+
+			_, err := client.GetProgress(ctx, &wordherov1.GetProgressRequest{})
+			if code := status.Code(err); code != tc.code {
+				t.Errorf("Expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+func TestAnonymousValidation(t *testing.T) {
+	conn := dialTestServer(t)
+	client := wordherov1.NewVocabularyServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", "en-US")
+
+	_, err := client.SearchWords(ctx, &wordherov1.SearchWordsRequest{Query: "  "})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+	if msg := i18n.ErrorMessage(i18n.EN, pke.CodeInvalidRequest); st.Message() != msg {
+		t.Errorf("Expected English message %q, got %q", msg, st.Message())
+	}
+}
+
+func TestReflection(t *testing.T) {
+	conn := dialTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("Failed to open reflection stream: %v", err)
+	}
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to send reflection request: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to list services: %v", err)
+	}
+
+	var services []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services = append(services, s.GetName())
+	}
+	for _, name := range []string{
+		wordherov1.VocabularyService_ServiceDesc.ServiceName,
+		wordherov1.WordTagService_ServiceDesc.ServiceName,
+		wordherov1.AuthService_ServiceDesc.ServiceName,
+	} {
+		if !slices.Contains(services, name) {
+			t.Errorf("Service %s is not listed in %v", name, services)
+		}
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		code       codes.Code
+		pkeCode    int
+		retryAfter time.Duration
+	}{
+		{name: "domain error", err: fmt.Errorf("%w: %w", service.ErrWordNotFound, dao.ErrNotFound), code: codes.NotFound, pkeCode: pke.CodeWordNotFound},
+		{name: "api error", err: pke.NewApiError(pke.CodeInvalidRequest), code: codes.InvalidArgument, pkeCode: pke.CodeInvalidRequest},
+		{name: "rate limited", err: pke.NewRetryAfterError(pke.CodeTooManyRequests, 3*time.Second), code: codes.ResourceExhausted, pkeCode: pke.CodeTooManyRequests, retryAfter: 3 * time.Second},
+		{name: "unknown error", err: fmt.Errorf("connection refused"), code: codes.Internal, pkeCode: pke.CodeSystemError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := status.Convert(toStatus(context.Background(), "/test", tc.err))
+			if st.Code() != tc.code {
+				t.Errorf("Expected %s, got %s", tc.code, st.Code())
+			}
+
+			var info *errdetails.ErrorInfo
+			var retry *errdetails.RetryInfo
+			for _, detail := range st.Details() {
+				switch d := detail.(type) {
+				case *errdetails.ErrorInfo:
+					info = d
+				case *errdetails.RetryInfo:
+					retry = d
+				}
+			}
+			if info == nil || info.GetReason() != strconv.Itoa(tc.pkeCode) || info.GetDomain() != errorDomain {
+				t.Errorf("Unexpected error info %v", info)
+			}
+			if tc.retryAfter > 0 && (retry == nil || retry.GetRetryDelay().AsDuration() != tc.retryAfter) {
+				t.Errorf("Unexpected retry info %v", retry)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	wordherov1 "github.com/sanmu2018/word-hero/api/wordhero/v1"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// vocabularyServer implements wordherov1.VocabularyServiceServer
+type vocabularyServer struct {
+	wordherov1.UnimplementedVocabularyServiceServer
+	*Server
+}
+
+// ListWords lists words page by page
+func (s *vocabularyServer) ListWords(ctx context.Context, req *wordherov1.ListWordsRequest) (*wordherov1.ListWordsResponse, error) {
+	filter, err := s.vocabularyService.BuildWordFilter(filterParams(req.GetFilter()), nil, userIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	list := baseList(req.GetPage())
+	page, err := s.vocabularyService.ListWords(&dao.BaseList{
+		PageNum:   list.PageNum,
+		PageSize:  list.PageSize,
+		Sort:      list.Sort,
+		Cursor:    list.Cursor,
+		SkipTotal: list.SkipTotal,
+	}, filter)
+	if err != nil {
+		log.Error(err).Int("page", list.PageNum).Msg("Failed to list words for gRPC")
+		return nil, err
+	}
+
+	words := make([]*wordherov1.Word, 0, len(page.Words))
+	for i := range page.Words {
+		words = append(words, toWord(&page.Words[i]))
+	}
+	return &wordherov1.ListWordsResponse{
+		Words:      words,
+		Total:      page.TotalCount,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// SearchWords searches words by English, Chinese or pinyin
+func (s *vocabularyServer) SearchWords(ctx context.Context, req *wordherov1.SearchWordsRequest) (*wordherov1.SearchWordsResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	total, results, next, err := s.vocabularyService.SearchWords(dto.WordSearchRequest{
+		Q:                query,
+		BaseList:         baseList(req.GetPage()),
+		WordFilterParams: filterParams(req.GetFilter()),
+	}, userIDFromContext(ctx))
+	if err != nil {
+		log.Error(err).Str("query", query).Msg("Search failed")
+		return nil, err
+	}
+
+	response := &wordherov1.SearchWordsResponse{
+		Results:    make([]*wordherov1.SearchResult, 0, len(results)),
+		Total:      total,
+		NextCursor: next,
+	}
+	for i := range results {
+		result := &results[i]
+		response.Results = append(response.Results, &wordherov1.SearchResult{
+			Word:           toWord(&result.Word),
+			MatchType:      result.MatchType,
+			Similarity:     result.Similarity,
+			MatchedForm:    result.MatchedForm,
+			Highlight:      result.Highlight,
+			HighlightField: result.HighlightField,
+		})
+	}
+	return response, nil
+}
+
+// GetWord gets a word by ID
+func (s *vocabularyServer) GetWord(ctx context.Context, req *wordherov1.GetWordRequest) (*wordherov1.GetWordResponse, error) {
+	if req.GetId() == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	word, err := s.vocabularyService.GetWordByID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &wordherov1.GetWordResponse{Word: toWord(word)}, nil
+}
+
+// baseList converts a page request, applying the defaults of the REST API
+func baseList(page *wordherov1.PageRequest) dto.BaseList {
+	list := dto.BaseList{
+		PageNum:   int(page.GetPageNum()),
+		PageSize:  int(page.GetPageSize()),
+		Sort:      page.GetSort(),
+		Cursor:    page.GetCursor(),
+		SkipTotal: page.GetSkipTotal(),
+	}
+	if list.PageSize == 0 {
+		list.PageSize = 12
+	}
+	if list.PageNum == 0 {
+		list.PageNum = 1
+	}
+	return list
+}
+
+// filterParams converts a word filter
+func filterParams(filter *wordherov1.WordFilter) dto.WordFilterParams {
+	return dto.WordFilterParams{
+		Filter:      filter.GetFilter(),
+		Category:    filter.GetCategory(),
+		Difficulty:  filter.GetDifficulty(),
+		Known:       filter.GetKnown(),
+		LearnedFrom: filter.GetLearnedFrom(),
+		LearnedTo:   filter.GetLearnedTo(),
+		MinLength:   int(filter.GetMinLength()),
+		MaxLength:   int(filter.GetMaxLength()),
+	}
+}
+
+// toWord converts a word
+func toWord(word *table.Word) *wordherov1.Word {
+	return &wordherov1.Word{
+		Id:         word.ID,
+		English:    word.English,
+		Chinese:    word.Chinese,
+		Phonetic:   word.Phonetic,
+		Example:    word.Example,
+		Definition: word.Definition,
+		Difficulty: word.Difficulty,
+		Category:   word.Category,
+		CreatedAt:  word.CreatedAt,
+		UpdatedAt:  word.UpdatedAt,
+	}
+}
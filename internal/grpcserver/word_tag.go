@@ -0,0 +1,127 @@
+package grpcserver
+
+import (
+	"context"
+
+	wordherov1 "github.com/sanmu2018/word-hero/api/wordhero/v1"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// wordTagServer implements wordherov1.WordTagServiceServer
+type wordTagServer struct {
+	wordherov1.UnimplementedWordTagServiceServer
+	*Server
+}
+
+// MarkWord marks a word as known
+func (s *wordTagServer) MarkWord(ctx context.Context, req *wordherov1.MarkWordRequest) (*wordherov1.MarkWordResponse, error) {
+	if req.GetWordId() == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	userID := userIDFromContext(ctx)
+	response, err := s.wordTagService.MarkWordAsKnown(&dto.WordMarkRequest{
+		WordID: req.GetWordId(),
+		UserID: userID,
+		Lang:   language(ctx),
+	})
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Str("word_id", req.GetWordId()).Msg("Failed to mark word as known")
+		return nil, err
+	}
+
+	return &wordherov1.MarkWordResponse{
+		Status:  &wordherov1.WordMarkStatus{WordId: response.WordID, IsMarked: response.IsMarked},
+		Message: response.Message,
+	}, nil
+}
+
+// UnmarkWord removes the mark from a word
+func (s *wordTagServer) UnmarkWord(ctx context.Context, req *wordherov1.UnmarkWordRequest) (*wordherov1.UnmarkWordResponse, error) {
+	if req.GetWordId() == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	userID := userIDFromContext(ctx)
+	response, err := s.wordTagService.RemoveWordMark(&dto.WordMarkRequest{
+		WordID: req.GetWordId(),
+		UserID: userID,
+		Lang:   language(ctx),
+	})
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Str("word_id", req.GetWordId()).Msg("Failed to remove word mark")
+		return nil, err
+	}
+
+	return &wordherov1.UnmarkWordResponse{
+		Status:  &wordherov1.WordMarkStatus{WordId: response.WordID, IsMarked: response.IsMarked},
+		Message: response.Message,
+	}, nil
+}
+
+// BatchMarkWords marks several words as known
+func (s *wordTagServer) BatchMarkWords(ctx context.Context, req *wordherov1.BatchMarkWordsRequest) (*wordherov1.BatchMarkWordsResponse, error) {
+	if len(req.GetWordIds()) == 0 {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	userID := userIDFromContext(ctx)
+	response, err := s.wordTagService.BatchMarkWords(&dto.BatchWordMarkRequest{
+		WordIDs: req.GetWordIds(),
+		UserID:  userID,
+		Lang:    language(ctx),
+	})
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Strs("word_ids", req.GetWordIds()).Msg("Failed to mark words as known")
+		return nil, err
+	}
+
+	return &wordherov1.BatchMarkWordsResponse{
+		SuccessCount: int32(response.SuccessCount),
+		FailedCount:  int32(response.FailedCount),
+		Errors:       response.Errors,
+	}, nil
+}
+
+// BatchGetMarkStatus gets the mark status of several words
+func (s *wordTagServer) BatchGetMarkStatus(ctx context.Context, req *wordherov1.BatchGetMarkStatusRequest) (*wordherov1.BatchGetMarkStatusResponse, error) {
+	if len(req.GetWordIds()) == 0 {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	userID := userIDFromContext(ctx)
+	response, err := s.wordTagService.GetBatchWordMarkStatus(userID, req.GetWordIds())
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Strs("word_ids", req.GetWordIds()).Msg("Failed to get batch word mark status")
+		return nil, err
+	}
+
+	statuses := make([]*wordherov1.WordMarkStatus, 0, len(response.WordMarkStatuses))
+	for _, status := range response.WordMarkStatuses {
+		statuses = append(statuses, &wordherov1.WordMarkStatus{
+			WordId:   status.WordID,
+			IsMarked: status.IsMarked,
+			MarkedAt: status.MarkedAt,
+		})
+	}
+	return &wordherov1.BatchGetMarkStatusResponse{Statuses: statuses}, nil
+}
+
+// GetProgress gets the caller's learning progress
+func (s *wordTagServer) GetProgress(ctx context.Context, req *wordherov1.GetProgressRequest) (*wordherov1.GetProgressResponse, error) {
+	userID := userIDFromContext(ctx)
+	response, err := s.wordTagService.GetUserProgress(userID)
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to get user progress")
+		return nil, err
+	}
+
+	return &wordherov1.GetProgressResponse{
+		KnownWords:     response.KnownWords,
+		TotalWords:     response.TotalWords,
+		ProgressRate:   response.ProgressRate,
+		RecentActivity: int32(response.RecentActivity),
+	}, nil
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// DirSender writes each message as an .eml file to a local directory
+// instead of sending it, for development and tests
+type DirSender struct {
+	dir  string
+	from string
+}
+
+// NewDirSender creates a new directory sender, creating dir if needed
+func NewDirSender(dir, from string) (*DirSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &DirSender{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+// Send writes msg to the mail directory
+func (s *DirSender) Send(ctx context.Context, msg *Message) error {
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), utils.GenerateUUID()[:8])
+	path := filepath.Join(s.dir, name)
+
+	if err := os.WriteFile(path, buildMessage(s.from, msg), 0o644); err != nil {
+		log.Error(err).Str("path", path).Msg("Failed to write mail file")
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	log.Info().Str("to", msg.To).Str("subject", msg.Subject).Str("path", path).Msg("Mail written to directory")
+	return nil
+}
@@ -0,0 +1,67 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// SMTPSender delivers mail through an SMTP server. Authentication is only
+// used when a username is configured, so it also works against local
+// stand-ins such as MailHog or Mailpit.
+type SMTPSender struct {
+	addr     string
+	host     string
+	from     string
+	username string
+	password string
+}
+
+// NewSMTPSender creates a new SMTP sender
+func NewSMTPSender(config *conf.MailConfig) *SMTPSender {
+	return &SMTPSender{
+		addr:     net.JoinHostPort(config.SMTP.Host, strconv.Itoa(config.SMTP.Port)),
+		host:     config.SMTP.Host,
+		from:     config.From,
+		username: config.SMTP.Username,
+		password: config.SMTP.Password,
+	}
+}
+
+// Send delivers msg through the configured SMTP server
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	// net/smtp has no context support, so run the delivery in the background
+	// and stop waiting when the context is done
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(s.addr, auth, from.Address, []string{msg.To}, buildMessage(s.from, msg))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			log.Error(err).Str("addr", s.addr).Str("to", msg.To).Msg("Failed to send mail")
+			return fmt.Errorf("failed to send mail: %w", err)
+		}
+		log.Info().Str("to", msg.To).Str("subject", msg.Subject).Msg("Mail sent")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send mail: %w", ctx.Err())
+	}
+}
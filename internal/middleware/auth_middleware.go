@@ -2,68 +2,172 @@ package middleware
 
 import (
 	"errors"
-	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sanmu2018/word-hero/internal/service"
 	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
 )
 
+// contextKeyTokenScopes holds the scopes of a personal access token. It is
+// only set for requests authenticated with one; JWT sessions are unrestricted.
+const contextKeyTokenScopes = "token_scopes"
+
+// contextKeyGuest marks requests made by a guest. For these "user_id" holds
+// the guest ID and no "user" is set.
+const contextKeyGuest = "is_guest"
+
 // AuthMiddleware handles authentication for protected routes
 type AuthMiddleware struct {
-	authService *service.AuthService
+	authService        *service.AuthService
+	accessTokenService *service.AccessTokenService
+	guestService       *service.GuestService
 }
 
 // NewAuthMiddleware creates a new AuthMiddleware instance
-func NewAuthMiddleware(authService *service.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService *service.AuthService, accessTokenService *service.AccessTokenService, guestService *service.GuestService) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
+		authService:        authService,
+		accessTokenService: accessTokenService,
+		guestService:       guestService,
 	}
 }
 
-// RequireAuth middleware requires authentication
+// RequireAuth middleware requires authentication. Users whose role must use
+// 2FA are rejected until they have enrolled.
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get token from Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code": 401,
-				"msg":  "Authorization header is required",
-			})
-			c.Abort()
+		user, ok := m.authenticate(c)
+		if !ok {
+			return
+		}
+
+		if m.authService.TwoFactorSetupRequired(user) {
+			abort(c, pke.CodeTwoFactorSetupRequired, "")
 			return
 		}
 
-		// Extract token from "Bearer <token>"
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code": 401,
-				"msg":  "Bearer token is required",
-			})
-			c.Abort()
+		c.Next()
+	}
+}
+
+// RequireAuthPending2FA middleware requires authentication but lets users
+// who still have to enroll in 2FA through, for the enrollment endpoints
+func (m *AuthMiddleware) RequireAuthPending2FA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := m.authenticate(c); !ok {
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Validate token
-		user, err := m.authService.ValidateToken(tokenString)
+// RequireAuthOrGuest middleware accepts either an authenticated user, checked
+// like RequireAuth, or a guest with a valid guest cookie
+func (m *AuthMiddleware) RequireAuthOrGuest() gin.HandlerFunc {
+	requireAuth := m.RequireAuth()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			requireAuth(c)
+			return
+		}
+
+		token, _ := c.Cookie(service.GuestCookieName)
+		guestID, err := m.guestService.Authenticate(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code": 401,
-				"msg":  "Invalid or expired token",
-			})
-			c.Abort()
+			abort(c, pke.CodeLoginRequired, "auth.header_or_guest_required")
 			return
 		}
 
-		// Set user in context
-		c.Set("user", user)
-		c.Set("user_id", user.ID)
-		c.Set("username", user.Username)
-		c.Set("user_role", user.Role)
+		c.Set("user_id", guestID)
+		c.Set(contextKeyGuest, true)
+		c.Next()
+	}
+}
+
+// RequireUser middleware rejects guests on routes grouped under RequireAuthOrGuest
+func (m *AuthMiddleware) RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsGuest(c) {
+			abort(c, pke.CodeLoginRequired, "")
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate validates the bearer token and stores the user in the
+// context. It aborts the request and returns false if authentication fails.
+func (m *AuthMiddleware) authenticate(c *gin.Context) (*table.User, bool) {
+	// Get token from Authorization header
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		abort(c, pke.CodeLoginRequired, "auth.header_required")
+		return nil, false
+	}
+
+	// Extract token from "Bearer <token>"
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		abort(c, pke.CodeInvalidToken, "auth.bearer_required")
+		return nil, false
+	}
+
+	user, scopes, err := m.validate(c, tokenString)
+	if err != nil {
+		abort(c, pke.CodeInvalidToken, "")
+		return nil, false
+	}
+
+	setUser(c, user, scopes)
+	return user, true
+}
+
+// validate resolves a bearer token, either a personal access token or a JWT
+// session, to its user. Scopes are nil for sessions, which are unrestricted.
+func (m *AuthMiddleware) validate(c *gin.Context, tokenString string) (*table.User, []string, error) {
+	if service.IsAccessToken(tokenString) {
+		return m.accessTokenService.Authenticate(tokenString, c.ClientIP())
+	}
+	user, err := m.authService.ValidateToken(tokenString)
+	return user, nil, err
+}
+
+// setUser stores the authenticated user, and the scopes of a personal access
+// token, in the context
+func setUser(c *gin.Context, user *table.User, scopes []string) {
+	if scopes != nil {
+		c.Set(contextKeyTokenScopes, scopes)
+	}
+	c.Set("user", user)
+	c.Set("user_id", user.ID)
+	c.Set("username", user.Username)
+	c.Set("user_role", user.Role)
+}
 
+// RequireScope middleware requires a personal access token to carry scope.
+// Requests authenticated with a JWT session are always allowed.
+func (m *AuthMiddleware) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopes, ok := c.Get(contextKeyTokenScopes); ok && !slices.Contains(scopes.([]string), scope) {
+			abort(c, pke.CodePermissionDenied, "auth.scope_required", scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession middleware rejects personal access tokens, for account
+// management endpoints that need an interactive login
+func (m *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(contextKeyTokenScopes); ok {
+			abort(c, pke.CodePermissionDenied, "auth.session_required")
+			return
+		}
 		c.Next()
 	}
 }
@@ -73,22 +177,14 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// First check if user is authenticated
 		if _, exists := c.Get("user"); !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code": 401,
-				"msg":  "Authentication required",
-			})
-			c.Abort()
+			abort(c, pke.CodeLoginRequired, "")
 			return
 		}
 
 		// Check if user has admin role
 		userRole, exists := c.Get("user_role")
 		if !exists || userRole != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{
-				"code": 403,
-				"msg":  "Admin access required",
-			})
-			c.Abort()
+			abort(c, pke.CodePermissionDenied, "auth.admin_required")
 			return
 		}
 
@@ -96,19 +192,17 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	}
 }
 
-// OptionalAuth middleware optionally authenticates user
+// OptionalAuth middleware optionally authenticates user, with a JWT session
+// or a personal access token. Invalid tokens are treated as anonymous.
 func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader != "" {
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 			if tokenString != authHeader {
-				user, err := m.authService.ValidateToken(tokenString)
-				if err == nil {
-					c.Set("user", user)
-					c.Set("user_id", user.ID)
-					c.Set("username", user.Username)
-					c.Set("user_role", user.Role)
+				user, scopes, err := m.validate(c, tokenString)
+				if err == nil && !m.authService.TwoFactorSetupRequired(user) {
+					setUser(c, user, scopes)
 				}
 			}
 		}
@@ -161,8 +255,33 @@ func IsAdmin(c *gin.Context) bool {
 	return role == "admin"
 }
 
+// IsGuest checks if the current request is made by a guest
+func IsGuest(c *gin.Context) bool {
+	return c.GetBool(contextKeyGuest)
+}
+
+// Language returns the language of messages for the request: the signed in
+// user's preference, else the best match of the Accept-Language header
+func Language(c *gin.Context) string {
+	if user, err := GetUserFromContext(c); err == nil && user.Language != "" {
+		return user.Language
+	}
+	return i18n.Match(c.GetHeader("Accept-Language"))
+}
+
+// abort stops the request with the API error for code. detail, a message
+// key or text, is appended to the code's message if not empty.
+func abort(c *gin.Context, code int, detail string, args ...interface{}) {
+	lang := Language(c)
+	resp := pke.APIResponse{Code: code, Msg: i18n.ErrorMessage(lang, code)}
+	if detail != "" {
+		resp.Msg += ": " + i18n.T(lang, detail, args...)
+	}
+	c.AbortWithStatusJSON(pke.HTTPStatus(code), resp)
+}
+
 // IsAuthenticated checks if the current user is authenticated
 func IsAuthenticated(c *gin.Context) bool {
 	_, err := GetUserFromContext(c)
 	return err == nil
-}
\ No newline at end of file
+}
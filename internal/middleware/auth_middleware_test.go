@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
+)
+
+// newTestRouter serves a session-only route and one route per token scope
+func newTestRouter(t *testing.T) (*gin.Engine, *utils.JWTUtils, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := daotest.Mock(t)
+	jwtUtils, err := utils.NewJWTUtils(&conf.JWTConfig{Secret: "test-secret", ExpiresIn: "1h"})
+	if err != nil {
+		t.Fatalf("NewJWTUtils() error = %v", err)
+	}
+	userDAO := dao.NewUserDAO()
+	authService := service.NewAuthService(userDAO, dao.NewUserTokenDAO(), dao.NewRecoveryCodeDAO(), dao.NewSettingDAO(), jwtUtils, nil, nil, &conf.AuthConfig{}, "")
+	m := NewAuthMiddleware(authService, service.NewAccessTokenService(dao.NewPersonalAccessTokenDAO(), userDAO), nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.POST("/change-password", m.RequireAuth(), m.RequireSession(), ok)
+	r.GET("/words", m.RequireAuth(), m.RequireScope(table.ScopeReadWords), ok)
+	r.POST("/tags", m.RequireAuth(), m.RequireScope(table.ScopeWriteTags), ok)
+	r.GET("/search", m.OptionalAuth(), m.RequireScope(table.ScopeReadWords), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("user_id"))
+	})
+	return r, jwtUtils, mock
+}
+
+// expectUser returns bob, a regular user
+func expectUser(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).WithArgs("user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "role", "is_active"}).AddRow("user-1", "bob", "user", true))
+}
+
+// expectAccessToken resolves a personal access token limited to scopes
+func expectAccessToken(mock sqlmock.Sqlmock, scopes string) {
+	mock.ExpectQuery(`SELECT \* FROM "personal_access_tokens" WHERE token_hash = `).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "scopes"}).AddRow("token-1", "user-1", scopes))
+	expectUser(mock)
+	mock.ExpectExec(`UPDATE "personal_access_tokens" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func serve(r *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+const testAccessToken = table.PersonalAccessTokenPrefix + "0123456789abcdefghijklmnopqrstuvwxyzABCD"
+
+func TestAccessTokenLimitedToScopesAndKeptOffSessionRoutes(t *testing.T) {
+	r, _, mock := newTestRouter(t)
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/words", http.StatusOK},
+		{http.MethodPost, "/tags", http.StatusForbidden},
+		{http.MethodPost, "/change-password", http.StatusForbidden},
+	}
+	for _, test := range tests {
+		expectAccessToken(mock, table.ScopeReadWords)
+		if code := serve(r, test.method, test.path, testAccessToken).Code; code != test.want {
+			t.Errorf("%s %s with access token = %d, want %d", test.method, test.path, code, test.want)
+		}
+	}
+}
+
+func TestSessionIsNotLimitedByScopes(t *testing.T) {
+	r, jwtUtils, mock := newTestRouter(t)
+	session, err := jwtUtils.GenerateToken("user-1", "bob", "bob@example.com", "user", 0)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	for _, path := range []string{"/tags", "/change-password"} {
+		expectUser(mock)
+		if code := serve(r, http.MethodPost, path, session).Code; code != http.StatusOK {
+			t.Errorf("POST %s with session = %d, want 200", path, code)
+		}
+	}
+}
+
+func TestOptionalAuthAcceptsAccessToken(t *testing.T) {
+	r, _, mock := newTestRouter(t)
+
+	expectAccessToken(mock, table.ScopeReadWords)
+	if w := serve(r, http.MethodGet, "/search", testAccessToken); w.Code != http.StatusOK || w.Body.String() != "user-1" {
+		t.Errorf("GET /search with access token = %d %q, want 200 as user-1", w.Code, w.Body.String())
+	}
+
+	// A token without read:words may not read the user's marks
+	expectAccessToken(mock, table.ScopeWriteTags)
+	if w := serve(r, http.MethodGet, "/search", testAccessToken); w.Code != http.StatusForbidden {
+		t.Errorf("GET /search with write-only access token = %d, want 403", w.Code)
+	}
+
+	if w := serve(r, http.MethodGet, "/search", ""); w.Code != http.StatusOK || w.Body.String() != "" {
+		t.Errorf("GET /search anonymously = %d %q, want 200 without a user", w.Code, w.Body.String())
+	}
+}
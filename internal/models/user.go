@@ -24,17 +24,20 @@ func NewUserBusiness(user *table.User) *UserBusiness {
 // ToResponse converts User to UserResponse (hides sensitive data)
 func (u *UserBusiness) ToResponse() dto.UserResponse {
 	return dto.UserResponse{
-		ID:        u.ID,
-		Username:  u.Username,
-		Email:     u.Email,
-		FullName:  u.FullName,
-		AvatarURL: u.AvatarURL,
-		Bio:       u.Bio,
-		Role:      u.Role,
-		IsActive:  u.IsActive,
-		LastLogin: u.LastLogin,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:              u.ID,
+		Username:        u.Username,
+		Email:           u.Email,
+		FullName:        u.FullName,
+		AvatarURL:       u.AvatarURL,
+		Bio:             u.Bio,
+		Role:            u.Role,
+		IsActive:        u.IsActive,
+		LastLogin:       u.LastLogin,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		TOTPEnabled:     u.TOTPEnabledAt != nil,
+		Language:        u.Language,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
 	}
 }
 
@@ -67,6 +70,11 @@ func (u *UserBusiness) UpdateLastLogin() error {
 	return nil
 }
 
+// IsEmailVerified checks if the user has verified their email address
+func (u *UserBusiness) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 // IsAdmin checks if the user has admin role
 func (u *UserBusiness) IsAdmin() bool {
 	return u.Role == "admin"
@@ -75,4 +83,4 @@ func (u *UserBusiness) IsAdmin() bool {
 // IsActiveUser checks if the user account is active
 func (u *UserBusiness) IsActiveUser() bool {
 	return u.IsActive
-}
\ No newline at end of file
+}
@@ -0,0 +1,67 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/middleware"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// userExportHandler downloads the personal data of the current user as a ZIP
+// archive, or as a single JSON document with ?format=json
+func (ws *WebServer) userExportHandler(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		writeError(c, pke.NewApiError(pke.CodeUnauthorized))
+		return
+	}
+
+	var params dto.UserExportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		writeError(c, pke.NewApiError(pke.CodeInvalidRequest))
+		return
+	}
+
+	export, err := ws.accountService.Export(userID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("word-hero-export-%s", time.UnixMilli(export.ExportedAt).Format("20060102"))
+	c.Header("Cache-Control", "no-store")
+	if params.Format == "json" {
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+		c.IndentedJSON(http.StatusOK, export)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`.zip"`)
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+	if err := ws.accountService.WriteExportArchive(c.Writer, export); err != nil {
+		// Headers are already sent, so the client only sees a truncated archive
+		log.Error(err).Str("user_id", userID).Msg("Failed to write export archive")
+	}
+}
+
+// apiDeleteAccountHandler deletes the current user's account
+func (ws *WebServer) apiDeleteAccountHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+	req.ClientIP = c.ClientIP()
+
+	return ws.accountService.Delete(userID, &req)
+}
@@ -0,0 +1,71 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/middleware"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+const (
+	// eventHeartbeat is how often an idle event stream sends a comment, so
+	// that proxies do not close it
+	eventHeartbeat = 25 * time.Second
+	// eventRetry is how long clients wait before reconnecting, in milliseconds
+	eventRetry = 3000
+)
+
+// eventsHandler streams the events of the current user as server-sent
+// events until the client disconnects. A client that falls behind is
+// disconnected; it should reload its state when it reconnects.
+func (ws *WebServer) eventsHandler(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		writeError(c, pke.NewApiError(pke.CodeUnauthorized))
+		return
+	}
+
+	sub := ws.eventService.Subscribe(userID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Disable response buffering in nginx
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", eventRetry)
+	c.Writer.Flush()
+
+	log.Debug().Str("user_id", userID).Msg("Event stream opened")
+	defer log.Debug().Str("user_id", userID).Msg("Event stream closed")
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		case payload, ok := <-sub.Messages():
+			if !ok {
+				log.Warn().Str("user_id", userID).Msg("Event stream fell behind, disconnecting")
+				return
+			}
+			var event dto.Event
+			if err := json.Unmarshal(payload, &event); err != nil {
+				log.Error(err).Str("user_id", userID).Msg("Failed to decode event")
+				continue
+			}
+			fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, payload)
+		}
+		c.Writer.Flush()
+	}
+}
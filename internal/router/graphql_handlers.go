@@ -0,0 +1,13 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/middleware"
+)
+
+// graphQLHandler serves GraphQL queries. The responses follow the GraphQL
+// format rather than the API response envelope.
+func (ws *WebServer) graphQLHandler(c *gin.Context) {
+	user, _ := middleware.GetUserFromContext(c)
+	ws.graphQL.Serve(c.Writer, c.Request, user, middleware.Language(c))
+}
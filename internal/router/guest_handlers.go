@@ -0,0 +1,50 @@
+package router
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// guestCookiePath covers the word tag endpoints and the login endpoints that
+// merge guest progress
+const guestCookiePath = "/api"
+
+// apiStartGuestHandler starts or renews a guest session so anonymous visitors
+// can mark words
+func (ws *WebServer) apiStartGuestHandler(c *gin.Context) (interface{}, error) {
+	existing, _ := c.Cookie(service.GuestCookieName)
+	guestID, token, err := ws.guestService.Start(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := ws.guestService.TTL()
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(service.GuestCookieName, token, int(ttl.Seconds()), guestCookiePath, "", c.Request.TLS != nil, true)
+
+	return &dto.GuestSessionResponse{
+		GuestID:   guestID,
+		ExpiresAt: time.Now().Add(ttl).UnixMilli(),
+	}, nil
+}
+
+// mergeGuest moves the progress of the request's guest session, if any, into
+// the user's account and ends the guest session. Failures are logged and do
+// not fail the login.
+func (ws *WebServer) mergeGuest(c *gin.Context, userID string) {
+	token, err := c.Cookie(service.GuestCookieName)
+	if err != nil || token == "" {
+		return
+	}
+
+	if _, err := ws.guestService.MergeInto(token, userID); err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Failed to merge guest progress")
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(service.GuestCookieName, "", -1, guestCookiePath, "", c.Request.TLS != nil, true)
+}
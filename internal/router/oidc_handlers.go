@@ -0,0 +1,109 @@
+package router
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+const (
+	// oidcFlowCookie keeps the state, nonce and PKCE verifier between the
+	// redirect to the identity provider and the callback
+	oidcFlowCookie = "oidc_flow"
+	oidcCookiePath = "/api/auth/oidc"
+	oidcCookieAge  = 600
+)
+
+// apiOIDCProvidersHandler lists the external login providers
+func (ws *WebServer) apiOIDCProvidersHandler(c *gin.Context) (interface{}, error) {
+	return ws.oidcService.Providers(), nil
+}
+
+// oidcLoginHandler redirects the browser to the identity provider
+func (ws *WebServer) oidcLoginHandler(c *gin.Context) {
+	provider := c.Param("provider")
+
+	authURL, flow, err := ws.oidcService.BeginLogin(provider)
+	if err != nil {
+		log.Warn().Err(err).Str("provider", provider).Msg("Failed to start OIDC login")
+		ws.oidcRedirect(c, url.Values{"oidcError": {oidcErrorCode(err)}})
+		return
+	}
+
+	data, err := json.Marshal(flow)
+	if err != nil {
+		ws.oidcRedirect(c, url.Values{"oidcError": {oidcErrorCode(err)}})
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, base64.RawURLEncoding.EncodeToString(data), oidcCookieAge, oidcCookiePath, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// oidcCallbackHandler completes the login when the identity provider
+// redirects back, then hands the result to the page in the URL fragment so
+// tokens never reach server logs
+func (ws *WebServer) oidcCallbackHandler(c *gin.Context) {
+	provider := c.Param("provider")
+
+	// The flow cookie is single use
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, "", -1, oidcCookiePath, "", c.Request.TLS != nil, true)
+
+	var params dto.OIDCCallbackParams
+	_ = c.ShouldBindQuery(&params)
+	if params.Error != "" {
+		log.Warn().Str("provider", provider).Str("error", params.Error).Str("description", params.ErrorDescription).Msg("OIDC provider returned an error")
+		ws.oidcRedirect(c, url.Values{"oidcError": {oidcErrorCode(pke.NewApiError(pke.CodeAuthFailed))}})
+		return
+	}
+
+	var flow *dto.OIDCFlowState
+	if cookie, err := c.Cookie(oidcFlowCookie); err == nil {
+		if data, err := base64.RawURLEncoding.DecodeString(cookie); err == nil {
+			_ = json.Unmarshal(data, &flow)
+		}
+	}
+
+	result, err := ws.oidcService.CompleteLogin(c.Request.Context(), provider, params.Code, params.State, c.ClientIP(), flow)
+	if err != nil {
+		log.Warn().Err(err).Str("provider", provider).Msg("OIDC login failed")
+		ws.oidcRedirect(c, url.Values{"oidcError": {oidcErrorCode(err)}})
+		return
+	}
+
+	if result.ChallengeToken != "" {
+		ws.oidcRedirect(c, url.Values{"oidcChallenge": {result.ChallengeToken}})
+		return
+	}
+
+	log.Info().Str("user_id", result.User.ID).Str("provider", provider).Msg("User logged in via OIDC")
+	ws.mergeGuest(c, result.User.ID)
+	values := url.Values{"oidcToken": {result.Token}}
+	if result.TwoFactorSetupRequired {
+		values.Set("twoFactorSetup", "1")
+	}
+	ws.oidcRedirect(c, values)
+}
+
+// oidcRedirect sends the browser back to the home page with values in the fragment
+func (ws *WebServer) oidcRedirect(c *gin.Context, values url.Values) {
+	c.Redirect(http.StatusFound, "/#"+values.Encode())
+}
+
+// oidcErrorCode returns the error code shown to the user after a failed login
+func oidcErrorCode(err error) string {
+	var apiErr *pke.APIResponse
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.ErrorNo())
+	}
+	return strconv.Itoa(pke.CodeSystemError)
+}
@@ -0,0 +1,490 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/service"
+)
+
+// apiV1Prefix is the path of version 1 of the JSON API. The same routes are
+// served under /api for clients written before the API was versioned.
+const apiV1Prefix = "/api/v1"
+
+// Security schemes of the API document
+const (
+	securityBearer = "bearerAuth"
+	securityGuest  = "guestCookie"
+)
+
+// authMode is the authentication a route accepts, as documented
+type authMode int
+
+const (
+	authInherit     authMode = iota // same as the group
+	authNone                        // public
+	authOptional                    // bearer token optional
+	authUser                        // bearer token required
+	authUserOrGuest                 // bearer token or guest cookie
+)
+
+// groupDoc documents the routes of a group
+type groupDoc struct {
+	Tag  string
+	Auth authMode
+}
+
+// routeDoc documents an API route in the OpenAPI document
+type routeDoc struct {
+	Summary string
+	// Tag and Auth override those of the group when set
+	Tag  string
+	Auth authMode
+	// Query is a struct whose form tags are the query parameters
+	Query interface{}
+	// Body is the JSON request body
+	Body         interface{}
+	OptionalBody bool
+	// Response is the data of a successful response in the API envelope,
+	// nil for none. Use listOf for lists and oneOf for alternatives.
+	Response interface{}
+	// Redirect documents a route that redirects the browser instead
+	Redirect bool
+	// Produces documents a response without the envelope, by content type.
+	// The schema is generated from each value unless it is a *openapi3.Schema.
+	Produces map[string]interface{}
+}
+
+// listResponse documents a pke.BaseListResp of items
+type listResponse struct {
+	items interface{}
+}
+
+// listOf documents a pke.BaseListResp whose items have the type of item
+func listOf(item interface{}) listResponse {
+	return listResponse{items: item}
+}
+
+// oneOfResponse documents data that is one of several types
+type oneOfResponse []interface{}
+
+// oneOf documents data that is one of the types of values
+func oneOf(values ...interface{}) oneOfResponse {
+	return values
+}
+
+// apiRouter registers API routes on a gin router group and records them in
+// the API document. A nil doc only registers the routes, for aliases.
+type apiRouter struct {
+	group *gin.RouterGroup
+	doc   *apiDoc
+	path  string // relative to the API root
+	tag   string
+	auth  authMode
+}
+
+// Group creates a group of routes below path
+func (r *apiRouter) Group(path string, doc groupDoc, handlers ...gin.HandlerFunc) *apiRouter {
+	group := &apiRouter{
+		group: r.group.Group(path, handlers...),
+		doc:   r.doc,
+		path:  r.path + path,
+		tag:   r.tag,
+		auth:  r.auth,
+	}
+	if doc.Tag != "" {
+		group.tag = doc.Tag
+	}
+	if doc.Auth != authInherit {
+		group.auth = doc.Auth
+	}
+	return group
+}
+
+// GET registers and documents a GET route
+func (r *apiRouter) GET(path string, doc routeDoc, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodGet, path, doc, handlers)
+}
+
+// POST registers and documents a POST route
+func (r *apiRouter) POST(path string, doc routeDoc, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPost, path, doc, handlers)
+}
+
+// PUT registers and documents a PUT route
+func (r *apiRouter) PUT(path string, doc routeDoc, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPut, path, doc, handlers)
+}
+
+// DELETE registers and documents a DELETE route
+func (r *apiRouter) DELETE(path string, doc routeDoc, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodDelete, path, doc, handlers)
+}
+
+func (r *apiRouter) handle(method, path string, doc routeDoc, handlers []gin.HandlerFunc) {
+	r.group.Handle(method, path, handlers...)
+	if r.doc == nil {
+		return
+	}
+	if doc.Tag == "" {
+		doc.Tag = r.tag
+	}
+	if doc.Auth == authInherit {
+		doc.Auth = r.auth
+	}
+	r.doc.add(method, r.path+path, doc)
+}
+
+// apiDoc builds the OpenAPI document of the API from the documented routes
+// and the DTOs they bind
+type apiDoc struct {
+	spec *openapi3.T
+	gen  *openapi3gen.Generator
+	err  error
+}
+
+func newAPIDoc() *apiDoc {
+	errorSchema := openapi3.NewObjectSchema().
+		WithProperty("code", openapi3.NewIntegerSchema()).
+		WithProperty("msg", openapi3.NewStringSchema())
+	errorSchema.Required = []string{"code", "msg"}
+
+	spec := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "Word Hero API",
+			Description: "Responses are wrapped in {code, msg, data}. code is 0 on success; on failure it is an error code and the HTTP status follows it.",
+			Version:     "1.0.0",
+		},
+		Servers: openapi3.Servers{{URL: apiV1Prefix}},
+		Paths:   openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{
+				"Error": errorSchema.NewRef(),
+			},
+			Responses: openapi3.ResponseBodies{
+				"Error": &openapi3.ResponseRef{Value: openapi3.NewResponse().
+					WithDescription("Error").
+					WithJSONSchemaRef(&openapi3.SchemaRef{Ref: "#/components/schemas/Error"})},
+			},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				securityBearer: &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme().
+					WithDescription("A session token from login, or a personal access token")},
+				securityGuest: &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().
+					WithType("apiKey").
+					WithIn("cookie").
+					WithName(service.GuestCookieName).
+					WithDescription("The guest session started by POST /auth/guest")},
+			},
+		},
+	}
+
+	return &apiDoc{
+		spec: spec,
+		gen: openapi3gen.NewGenerator(
+			openapi3gen.CreateComponentSchemas(openapi3gen.ExportComponentSchemasOptions{
+				ExportComponentSchemas: true,
+				ExportTopLevelSchema:   true,
+			}),
+			openapi3gen.SchemaCustomizer(bindingSchema),
+		),
+	}
+}
+
+// pathParam matches the parameters of gin route paths
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+func (d *apiDoc) add(method, path string, doc routeDoc) {
+	op := openapi3.NewOperation()
+	op.Summary = doc.Summary
+	if doc.Tag != "" {
+		op.Tags = []string{doc.Tag}
+		if d.spec.Tags.Get(doc.Tag) == nil {
+			d.spec.Tags = append(d.spec.Tags, &openapi3.Tag{Name: doc.Tag})
+		}
+	}
+	op.OperationID = strings.ToLower(method) + operationName(path)
+
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		param := openapi3.NewPathParameter(match[1]).WithSchema(openapi3.NewStringSchema())
+		op.AddParameter(param)
+	}
+	if doc.Query != nil {
+		d.addQueryParameters(op, reflect.TypeOf(doc.Query))
+	}
+	if doc.Body != nil {
+		op.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+			WithRequired(!doc.OptionalBody).
+			WithJSONSchemaRef(d.schema(doc.Body))}
+	}
+
+	op.Responses = openapi3.NewResponses()
+	switch {
+	case doc.Redirect:
+		op.Responses.Set("302", &openapi3.ResponseRef{Value: openapi3.NewResponse().
+			WithDescription("Redirect to the home page with the result")})
+	case doc.Produces != nil:
+		content := openapi3.NewContent()
+		for contentType, value := range doc.Produces {
+			schema, ok := value.(*openapi3.Schema)
+			if ok {
+				content[contentType] = openapi3.NewMediaType().WithSchema(schema)
+			} else {
+				content[contentType] = openapi3.NewMediaType().WithSchemaRef(d.schema(value))
+			}
+		}
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().
+			WithDescription("Success").
+			WithContent(content)})
+	default:
+		data := openapi3.NewObjectSchema().
+			WithProperty("code", openapi3.NewIntegerSchema()).
+			WithProperty("msg", openapi3.NewStringSchema())
+		data.Required = []string{"code"}
+		if doc.Response != nil {
+			data.WithPropertyRef("data", d.dataSchema(doc.Response))
+		}
+		op.Responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().
+			WithDescription("Success").
+			WithJSONSchema(data)})
+		op.Responses.Set("default", &openapi3.ResponseRef{Ref: "#/components/responses/Error"})
+	}
+
+	switch doc.Auth {
+	case authOptional:
+		op.Security = openapi3.NewSecurityRequirements().
+			With(openapi3.NewSecurityRequirement().Authenticate(securityBearer)).
+			With(openapi3.NewSecurityRequirement())
+	case authUser:
+		op.Security = openapi3.NewSecurityRequirements().
+			With(openapi3.NewSecurityRequirement().Authenticate(securityBearer))
+	case authUserOrGuest:
+		op.Security = openapi3.NewSecurityRequirements().
+			With(openapi3.NewSecurityRequirement().Authenticate(securityBearer)).
+			With(openapi3.NewSecurityRequirement().Authenticate(securityGuest))
+	}
+
+	d.spec.AddOperation(pathParam.ReplaceAllString(path, "{$1}"), method, op)
+}
+
+// operationName turns a route path into a camel case operation name, e.g.
+// "/word-tags/status/:wordId" into "WordTagsStatusByWordId"
+func operationName(path string) string {
+	var name strings.Builder
+	for _, segment := range strings.Split(path, "/") {
+		if param, ok := strings.CutPrefix(segment, ":"); ok {
+			name.WriteString("By")
+			segment = param
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '.' || r == '_' }) {
+			name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return name.String()
+}
+
+// schema generates the schema of the type of value; structs become component
+// schemas
+func (d *apiDoc) schema(value interface{}) *openapi3.SchemaRef {
+	ref, err := d.gen.NewSchemaRefForValue(value, d.spec.Components.Schemas)
+	if err != nil {
+		d.fail(fmt.Errorf("schema of %T: %w", value, err))
+		return openapi3.NewSchemaRef("", openapi3.NewSchema())
+	}
+	return ref
+}
+
+// dataSchema generates the schema of the data of a successful response
+func (d *apiDoc) dataSchema(response interface{}) *openapi3.SchemaRef {
+	switch response := response.(type) {
+	case listResponse:
+		items := openapi3.NewArraySchema()
+		items.Items = d.schema(response.items)
+		list := openapi3.NewObjectSchema().
+			WithProperty("items", items).
+			WithProperty("total", openapi3.NewInt64Schema()).
+			WithProperty("nextCursor", openapi3.NewStringSchema())
+		list.Required = []string{"items", "total"}
+		return list.NewRef()
+	case oneOfResponse:
+		alternatives := openapi3.NewSchema()
+		for _, value := range response {
+			alternatives.OneOf = append(alternatives.OneOf, d.schema(value))
+		}
+		return alternatives.NewRef()
+	}
+	return d.schema(response)
+}
+
+// addQueryParameters documents the form tagged fields of t, including those
+// of embedded structs, as query parameters of op
+func (d *apiDoc) addQueryParameters(op *openapi3.Operation, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			d.addQueryParameters(op, field.Type)
+			continue
+		}
+		name := field.Tag.Get("form")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		var schema *openapi3.Schema
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			schema = openapi3.NewBoolSchema()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			schema = openapi3.NewIntegerSchema()
+		default:
+			schema = openapi3.NewStringSchema()
+		}
+		applyBinding(schema, field.Tag)
+
+		param := openapi3.NewQueryParameter(name).WithSchema(schema)
+		param.Required = hasRule(field.Tag, "required")
+		op.AddParameter(param)
+	}
+}
+
+func (d *apiDoc) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// build returns the OpenAPI document as JSON, after checking it is valid
+func (d *apiDoc) build() ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	data, err := json.Marshal(d.spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode API document: %w", err)
+	}
+
+	// Load the document back to resolve the references of the component schemas
+	loaded, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API document: %w", err)
+	}
+	if err := loaded.Validate(openapi3.NewLoader().Context); err != nil {
+		return nil, fmt.Errorf("invalid API document: %w", err)
+	}
+	return data, nil
+}
+
+// bindingSchema is the openapi3gen schema customizer that carries the gin
+// binding rules of DTO fields into the schemas of structs. Fields are handled
+// from their struct since the generator passes a field's tag on to the items
+// of slices, which the rules do not apply to.
+func bindingSchema(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if t.Kind() == reflect.Struct {
+		applyFieldBindings(t, schema)
+	}
+	return nil
+}
+
+// applyFieldBindings applies the binding rules of the fields of t, including
+// those of embedded structs, to the schema of t
+func applyFieldBindings(t reflect.Type, schema *openapi3.Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" {
+			applyFieldBindings(field.Type, schema)
+			continue
+		}
+		if name == "" || name == "-" || field.Tag.Get("binding") == "" {
+			continue
+		}
+
+		if hasRule(field.Tag, "required") {
+			schema.Required = append(schema.Required, name)
+		}
+		// Component schemas of structs are shared, only inline ones are changed
+		if property := schema.Properties[name]; property != nil && property.Value != nil && !strings.HasPrefix(property.Ref, "#/") {
+			applyBinding(property.Value, field.Tag)
+		}
+	}
+}
+
+// applyBinding sets the constraints of the binding rules in tag on schema.
+// A required string must not be empty, as gin checks.
+func applyBinding(schema *openapi3.Schema, tag reflect.StructTag) {
+	rules := strings.Split(tag.Get("binding"), ",")
+	omitEmpty := slices.Contains(rules, "omitempty")
+	for _, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			if schema.Type.Is(openapi3.TypeString) && schema.MinLength == 0 {
+				schema.MinLength = 1
+			}
+		case "email", "uuid":
+			// An empty value is allowed with omitempty, which no format
+			// can express
+			if !omitEmpty {
+				schema.Format = key
+			}
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, option)
+			}
+			if omitEmpty {
+				schema.Enum = append(schema.Enum, "")
+			}
+		case "min", "max":
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			applyLimit(schema, key == "min", n)
+		}
+	}
+}
+
+// applyLimit sets the lower or upper limit n on the length of strings and
+// arrays, or on the value of numbers
+func applyLimit(schema *openapi3.Schema, lower bool, n uint64) {
+	switch {
+	case schema.Type.Is(openapi3.TypeString) && lower:
+		schema.MinLength = n
+	case schema.Type.Is(openapi3.TypeString):
+		schema.MaxLength = &n
+	case schema.Type.Is(openapi3.TypeArray) && lower:
+		schema.MinItems = n
+	case schema.Type.Is(openapi3.TypeArray):
+		schema.MaxItems = &n
+	case lower:
+		limit := float64(n)
+		schema.Min = &limit
+	default:
+		limit := float64(n)
+		schema.Max = &limit
+	}
+}
+
+// hasRule reports whether the binding rules in tag include rule
+func hasRule(tag reflect.StructTag, rule string) bool {
+	return slices.Contains(strings.Split(tag.Get("binding"), ","), rule)
+}
+
+// openAPIHandler serves the OpenAPI document of the API
+func (ws *WebServer) openAPIHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", ws.openAPI)
+}
+
+// apiDocsHandler serves a page that renders the OpenAPI document
+func (ws *WebServer) apiDocsHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "api_docs.html", gin.H{"SpecURL": apiV1Prefix + "/openapi.json"})
+}
@@ -0,0 +1,181 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sanmu2018/word-hero/internal/dto"
+)
+
+func init() {
+	openapi3.DefineStringFormatValidator("email", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForEmail))
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForUUIDOfRFC4122))
+}
+
+// newTestServer creates a web server without services, enough to register
+// the routes and serve the API document
+func newTestServer(t *testing.T) *WebServer {
+	t.Helper()
+	return NewWebServer(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "../../web/templates")
+}
+
+// loadAPIDoc fetches the API document from the server
+func loadAPIDoc(t *testing.T, ws *WebServer) *openapi3.T {
+	t.Helper()
+	w := httptest.NewRecorder()
+	ws.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, apiV1Prefix+"/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET openapi.json returned %d", w.Code)
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to load API document: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("Invalid API document: %v", err)
+	}
+	return doc
+}
+
+// docParam matches the parameters of OpenAPI paths
+var docParam = regexp.MustCompile(`\{(\w+)\}`)
+
+func TestAPIDocumentCoversRoutes(t *testing.T) {
+	ws := newTestServer(t)
+	doc := loadAPIDoc(t, ws)
+
+	documented := 0
+	aliases := make(map[string]bool)
+	for _, route := range ws.engine.Routes() {
+		if path, ok := strings.CutPrefix(route.Path, apiV1Prefix); ok {
+			path = pathParam.ReplaceAllString(path, "{$1}")
+			item := doc.Paths.Find(path)
+			if item == nil || item.GetOperation(route.Method) == nil {
+				t.Errorf("Route %s %s is not documented", route.Method, route.Path)
+			}
+			documented++
+			continue
+		}
+		if path, ok := strings.CutPrefix(route.Path, "/api"); ok {
+			aliases[route.Method+" "+path] = true
+		}
+	}
+
+	operations := 0
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			operations++
+			if !aliases[method+" "+docParam.ReplaceAllString(path, ":$1")] {
+				t.Errorf("Operation %s %s has no /api alias", method, path)
+			}
+		}
+	}
+	if operations != documented {
+		t.Errorf("Document has %d operations for %d routes", operations, documented)
+	}
+}
+
+func TestAPIDocsPage(t *testing.T) {
+	ws := newTestServer(t)
+	w := httptest.NewRecorder()
+	ws.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, apiV1Prefix+"/docs", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), apiV1Prefix+"/openapi.json") {
+		t.Errorf("Unexpected docs page: %d %s", w.Code, w.Body.String())
+	}
+}
+
+// TestRequestValidation validates requests against the API document. Body
+// requests are also bound to their DTO, which must agree with the document.
+func TestRequestValidation(t *testing.T) {
+	doc := loadAPIDoc(t, newTestServer(t))
+	doc.Servers = openapi3.Servers{{URL: "http://localhost" + apiV1Prefix}}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		dto    interface{}
+		valid  bool
+	}{
+		{name: "list words", method: http.MethodGet, path: "/words?pageNum=2&pageSize=12&sort=english|desc", valid: true},
+		{name: "list words with bad page", method: http.MethodGet, path: "/words?pageNum=two", valid: false},
+		{name: "search", method: http.MethodGet, path: "/search?q=apple&skipTotal=true", valid: true},
+		{name: "verify email link without token", method: http.MethodGet, path: "/auth/verify-email", valid: false},
+		{name: "export as json", method: http.MethodGet, path: "/user/export?format=json", valid: true},
+		{name: "export as xml", method: http.MethodGet, path: "/user/export?format=xml", valid: false},
+		{name: "word status", method: http.MethodGet, path: "/word-tags/status/123", valid: true},
+		{name: "unknown route", method: http.MethodGet, path: "/nope", valid: false},
+
+		{name: "register", method: http.MethodPost, path: "/auth/register", body: `{"username":"alice","email":"alice@example.com","password":"secret1"}`, dto: &dto.UserRegisterRequest{}, valid: true},
+		{name: "register with short username", method: http.MethodPost, path: "/auth/register", body: `{"username":"al","email":"alice@example.com","password":"secret1"}`, dto: &dto.UserRegisterRequest{}, valid: false},
+		{name: "register with bad email", method: http.MethodPost, path: "/auth/register", body: `{"username":"alice","email":"alice","password":"secret1"}`, dto: &dto.UserRegisterRequest{}, valid: false},
+		{name: "register without password", method: http.MethodPost, path: "/auth/register", body: `{"username":"alice","email":"alice@example.com"}`, dto: &dto.UserRegisterRequest{}, valid: false},
+		{name: "login", method: http.MethodPost, path: "/auth/login", body: `{"username":"alice","password":"secret1"}`, dto: &dto.UserLoginRequest{}, valid: true},
+		{name: "login with empty password", method: http.MethodPost, path: "/auth/login", body: `{"username":"alice","password":""}`, dto: &dto.UserLoginRequest{}, valid: false},
+		{name: "update profile", method: http.MethodPut, path: "/auth/profile", body: `{"full_name":"Alice","email":"","language":"en"}`, dto: &dto.UserUpdateRequest{}, valid: true},
+		{name: "update profile with auto language", method: http.MethodPut, path: "/auth/profile", body: `{"language":"auto"}`, dto: &dto.UserUpdateRequest{}, valid: true},
+		{name: "update profile with bad language", method: http.MethodPut, path: "/auth/profile", body: `{"language":"fr"}`, dto: &dto.UserUpdateRequest{}, valid: false},
+		{name: "create token", method: http.MethodPost, path: "/user/tokens", body: `{"name":"ci","scopes":["read:words"],"expires_in_days":30}`, dto: &dto.CreateAccessTokenRequest{}, valid: true},
+		{name: "create token without scopes", method: http.MethodPost, path: "/user/tokens", body: `{"name":"ci","scopes":[]}`, dto: &dto.CreateAccessTokenRequest{}, valid: false},
+		{name: "create token with long expiry", method: http.MethodPost, path: "/user/tokens", body: `{"name":"ci","scopes":["read:words"],"expires_in_days":400}`, dto: &dto.CreateAccessTokenRequest{}, valid: false},
+		{name: "mark word", method: http.MethodPost, path: "/word-tags/mark", body: `{"wordId":"0b6f6c9e-3f7a-4c1e-9d2b-5a8e4f1c7d3a"}`, dto: &dto.WordMarkRequest{}, valid: true},
+		{name: "mark word with bad id", method: http.MethodPost, path: "/word-tags/mark", body: `{"wordId":"apple"}`, dto: &dto.WordMarkRequest{}, valid: false},
+		{name: "forget no words", method: http.MethodPost, path: "/word-tags/forget-words", body: `{"wordIds":[]}`, dto: &dto.ForgetWordsRequest{}, valid: false},
+		{name: "known words without body", method: http.MethodPost, path: "/word-tags/known?pageSize=20", valid: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://localhost"+apiV1Prefix+tc.path, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			err := validateRequest(router, req)
+			if tc.valid && err != nil {
+				t.Errorf("Expected a valid request, got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("Expected an invalid request")
+			}
+
+			// gin binding must agree with the document
+			if tc.dto != nil {
+				bindErr := binding.JSON.BindBody([]byte(tc.body), tc.dto)
+				if (bindErr == nil) != tc.valid {
+					t.Errorf("Binding disagrees with the document: %v", bindErr)
+				}
+			}
+		})
+	}
+}
+
+func validateRequest(router routers.Router, req *http.Request) error {
+	route, pathParams, err := router.FindRoute(req)
+	if err != nil {
+		return err
+	}
+	return openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	})
+}
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/middleware"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// apiSyncHandler applies the operations of an offline client and returns
+// the changes since its last sync
+func (ws *WebServer) apiSyncHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	var req dto.SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Invalid sync request")
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+	req.Lang = middleware.Language(c)
+
+	response, err := ws.syncService.Sync(userID, &req)
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to sync")
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -7,30 +7,44 @@ import (
 	"strings"
 	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/dao"
 	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/graph"
 	"github.com/sanmu2018/word-hero/internal/middleware"
 	"github.com/sanmu2018/word-hero/internal/models"
 	"github.com/sanmu2018/word-hero/internal/service"
 	"github.com/sanmu2018/word-hero/internal/table"
 	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
 	"github.com/sanmu2018/word-hero/pkg/pke"
 )
 
 // WebServer handles the web application with layered architecture
 type WebServer struct {
-	vocabularyService *service.VocabularyService
-	pagerService      *service.PagerService
-	authService       *service.AuthService
-	userService       *service.UserService
-	wordTagService    *service.WordTagService
-	authMiddleware    *middleware.AuthMiddleware
-	templateDir       string
-	engine            *gin.Engine
+	vocabularyService  *service.VocabularyService
+	suggestService     *service.SuggestService
+	pagerService       *service.PagerService
+	authService        *service.AuthService
+	oidcService        *service.OIDCService
+	accessTokenService *service.AccessTokenService
+	guestService       *service.GuestService
+	accountService     *service.AccountService
+	userService        *service.UserService
+	wordTagService     *service.WordTagService
+	eventService       *service.EventService
+	syncService        *service.SyncService
+	graphQL            *graph.Server
+	authMiddleware     *middleware.AuthMiddleware
+	templateDir        string
+	engine             *gin.Engine
+	// openAPI is the OpenAPI document of the API as JSON
+	openAPI []byte
 }
 
 // NewWebServer creates a new web server instance
-func NewWebServer(vocabularyService *service.VocabularyService, pagerService *service.PagerService, authService *service.AuthService, userService *service.UserService, wordTagService *service.WordTagService, authMiddleware *middleware.AuthMiddleware, templateDir string) *WebServer {
+func NewWebServer(vocabularyService *service.VocabularyService, suggestService *service.SuggestService, pagerService *service.PagerService, authService *service.AuthService, oidcService *service.OIDCService, accessTokenService *service.AccessTokenService, guestService *service.GuestService, accountService *service.AccountService, userService *service.UserService, wordTagService *service.WordTagService, eventService *service.EventService, syncService *service.SyncService, graphQL *graph.Server, authMiddleware *middleware.AuthMiddleware, templateDir string) *WebServer {
 	log.Info().Str("templateDir", templateDir).Msg("Creating web server")
 
 	// Create Gin engine
@@ -39,14 +53,22 @@ func NewWebServer(vocabularyService *service.VocabularyService, pagerService *se
 
 	// Set up routes
 	ws := &WebServer{
-		vocabularyService: vocabularyService,
-		pagerService:      pagerService,
-		authService:       authService,
-		userService:       userService,
-		wordTagService:    wordTagService,
-		authMiddleware:    authMiddleware,
-		templateDir:       templateDir,
-		engine:            engine,
+		vocabularyService:  vocabularyService,
+		suggestService:     suggestService,
+		pagerService:       pagerService,
+		authService:        authService,
+		oidcService:        oidcService,
+		accessTokenService: accessTokenService,
+		guestService:       guestService,
+		accountService:     accountService,
+		userService:        userService,
+		wordTagService:     wordTagService,
+		eventService:       eventService,
+		syncService:        syncService,
+		graphQL:            graphQL,
+		authMiddleware:     authMiddleware,
+		templateDir:        templateDir,
+		engine:             engine,
 	}
 
 	// Add middleware
@@ -87,47 +109,120 @@ func (ws *WebServer) setupRoutes() {
 	// Web routes
 	ws.engine.GET("/", ws.homeHandler)
 
-	// API routes
-	api := ws.engine.Group("/api")
+	// Public keys for services that verify our tokens
+	ws.engine.GET("/.well-known/jwks.json", ws.jwksHandler)
+
+	// API routes under /api/v1, documented in the OpenAPI document, and
+	// the same routes under /api for existing clients
+	doc := newAPIDoc()
+	ws.setupAPIRoutes(&apiRouter{group: ws.engine.Group(apiV1Prefix), doc: doc, auth: authNone})
+	ws.setupAPIRoutes(&apiRouter{group: ws.engine.Group("/api"), auth: authNone})
+
+	openAPI, err := doc.build()
+	if err != nil {
+		panic(fmt.Sprintf("failed to build API document: %v", err))
+	}
+	ws.openAPI = openAPI
+}
+
+// setupAPIRoutes configures the API routes on api
+func (ws *WebServer) setupAPIRoutes(api *apiRouter) {
+	// API documentation
+	docs := api.Group("", groupDoc{Tag: "Docs"})
 	{
-		// Public vocabulary endpoints
-		api.GET("/words", wrapper(ws.apiWordsHandler))
-		api.GET("/search", wrapper(ws.apiSearchHandler))
-		api.GET("/stats", wrapper(ws.apiStatsHandler))
+		docs.GET("/openapi.json", routeDoc{Summary: "OpenAPI document of the API", Produces: map[string]interface{}{"application/json": openapi3.NewObjectSchema()}}, ws.openAPIHandler)
+		docs.GET("/docs", routeDoc{Summary: "API documentation page", Produces: map[string]interface{}{"text/html": openapi3.NewStringSchema()}}, ws.apiDocsHandler)
+	}
 
-		// Authentication endpoints
-		auth := api.Group("/auth")
-		{
-			auth.POST("/register", wrapper(ws.apiRegisterHandler))
-			auth.POST("/login", wrapper(ws.apiLoginHandler))
-			auth.POST("/logout", wrapper(ws.apiLogoutHandler))
-			auth.GET("/me", ws.authMiddleware.RequireAuth(), wrapper(ws.apiGetCurrentUserHandler))
-			auth.PUT("/profile", ws.authMiddleware.RequireAuth(), wrapper(ws.apiUpdateProfileHandler))
-			auth.POST("/change-password", ws.authMiddleware.RequireAuth(), wrapper(ws.apiChangePasswordHandler))
-		}
+	// Public vocabulary endpoints. Signed in users can filter by their own
+	// marks, which personal access tokens need read:words for.
+	readWords := ws.authMiddleware.RequireScope(table.ScopeReadWords)
+	words := api.Group("", groupDoc{Tag: "Words"})
+	{
+		words.GET("/words", routeDoc{Summary: "List words page by page", Auth: authOptional, Query: dto.WordListRequest{}, Response: listOf(table.Word{})}, ws.authMiddleware.OptionalAuth(), readWords, wrapper(ws.apiWordsHandler))
+		words.GET("/search", routeDoc{Summary: "Search words", Auth: authOptional, Query: dto.WordSearchRequest{}, Response: listOf(dto.WordSearchResult{})}, ws.authMiddleware.OptionalAuth(), readWords, wrapper(ws.apiSearchHandler))
+		words.GET("/search/pattern", routeDoc{Summary: "Search words by regular expression or wildcard", Query: dto.WordPatternSearchRequest{}, Response: listOf(table.Word{})}, wrapper(ws.apiPatternSearchHandler))
+		words.GET("/suggest", routeDoc{Summary: "Autocomplete suggestions for a prefix", Query: dto.WordSuggestRequest{}, Response: []dto.WordSuggestion{}}, wrapper(ws.apiSuggestHandler))
+		words.GET("/stats", routeDoc{Summary: "Vocabulary statistics", Response: map[string]interface{}{}}, wrapper(ws.apiStatsHandler))
+	}
+
+	// GraphQL endpoint for clients that fetch words, tags and progress at once
+	api.POST("/graphql", routeDoc{Summary: "Run a GraphQL query", Tag: "GraphQL", Auth: authOptional, Body: dto.GraphQLRequest{}, Produces: map[string]interface{}{"application/json": dto.GraphQLResponse{}}}, ws.authMiddleware.OptionalAuth(), readWords, ws.graphQLHandler)
 
-		// Protected user endpoints
-		user := api.Group("/user")
-		user.Use(ws.authMiddleware.RequireAuth())
+	// Authentication endpoints
+	auth := api.Group("/auth", groupDoc{Tag: "Auth"})
+	{
+		auth.POST("/register", routeDoc{Summary: "Register a user", Body: dto.UserRegisterRequest{}, Response: dto.AuthResponse{}}, wrapper(ws.apiRegisterHandler))
+		auth.POST("/login", routeDoc{Summary: "Log in with username and password", Body: dto.UserLoginRequest{}, Response: dto.AuthResponse{}}, wrapper(ws.apiLoginHandler))
+		auth.POST("/login/2fa", routeDoc{Summary: "Complete a login with a 2FA code", Body: dto.Login2FARequest{}, Response: dto.AuthResponse{}}, wrapper(ws.apiLogin2FAHandler))
+		auth.POST("/logout", routeDoc{Summary: "Log out", Response: ""}, wrapper(ws.apiLogoutHandler))
+		auth.POST("/guest", routeDoc{Summary: "Start or renew a guest session", Response: dto.GuestSessionResponse{}}, wrapper(ws.apiStartGuestHandler))
+		auth.GET("/verify-email", routeDoc{Summary: "Verification link sent by email", Query: dto.VerifyEmailRequest{}, Redirect: true}, ws.verifyEmailLinkHandler)
+		auth.POST("/verify-email", routeDoc{Summary: "Verify an email address and log in", Body: dto.VerifyEmailRequest{}, Response: dto.AuthResponse{}}, wrapper(ws.apiVerifyEmailHandler))
+		auth.POST("/resend-verification", routeDoc{Summary: "Send a new verification email", Body: dto.ResendVerificationRequest{}, Response: ""}, wrapper(ws.apiResendVerificationHandler))
+		auth.POST("/forgot-password", routeDoc{Summary: "Send a password reset email", Body: dto.ForgotPasswordRequest{}, Response: ""}, wrapper(ws.apiForgotPasswordHandler))
+		auth.POST("/reset-password", routeDoc{Summary: "Set a new password with a reset token", Body: dto.ResetPasswordRequest{}, Response: ""}, wrapper(ws.apiResetPasswordHandler))
+		auth.GET("/oidc/providers", routeDoc{Summary: "List external login providers", Response: []dto.OIDCProviderInfo{}}, wrapper(ws.apiOIDCProvidersHandler))
+		auth.GET("/oidc/:provider/login", routeDoc{Summary: "Redirect to an external login provider", Redirect: true}, ws.oidcLoginHandler)
+		auth.GET("/oidc/:provider/callback", routeDoc{Summary: "Return from an external login provider", Query: dto.OIDCCallbackParams{}, Redirect: true}, ws.oidcCallbackHandler)
+		auth.GET("/me", routeDoc{Summary: "Get the current user", Auth: authUser, Response: dto.UserResponse{}}, ws.authMiddleware.RequireAuthPending2FA(), ws.authMiddleware.RequireScope(table.ScopeReadUser), wrapper(ws.apiGetCurrentUserHandler))
+		auth.PUT("/profile", routeDoc{Summary: "Update the profile of the current user", Auth: authUser, Body: dto.UserUpdateRequest{}, Response: dto.UserResponse{}}, ws.authMiddleware.RequireAuth(), ws.authMiddleware.RequireSession(), wrapper(ws.apiUpdateProfileHandler))
+		auth.POST("/change-password", routeDoc{Summary: "Change the password of the current user", Auth: authUser, Body: dto.ChangePasswordRequest{}, Response: ""}, ws.authMiddleware.RequireAuth(), ws.authMiddleware.RequireSession(), wrapper(ws.apiChangePasswordHandler))
+
+		// Two-factor enrollment stays reachable for users who are required to enroll
+		twoFactor := auth.Group("/2fa", groupDoc{Tag: "Two-factor authentication", Auth: authUser}, ws.authMiddleware.RequireAuthPending2FA(), ws.authMiddleware.RequireSession())
 		{
-			user.GET("/profile", wrapper(ws.apiGetUserProfileHandler))
+			twoFactor.GET("", routeDoc{Summary: "Get the 2FA state", Response: dto.TwoFactorStatusResponse{}}, wrapper(ws.api2FAStatusHandler))
+			twoFactor.POST("/setup", routeDoc{Summary: "Start TOTP enrollment", Response: dto.TwoFactorSetupResponse{}}, wrapper(ws.api2FASetupHandler))
+			twoFactor.POST("/enable", routeDoc{Summary: "Confirm TOTP enrollment with a first code", Body: dto.TwoFactorCodeRequest{}, Response: dto.RecoveryCodesResponse{}}, wrapper(ws.api2FAEnableHandler))
+			twoFactor.POST("/disable", routeDoc{Summary: "Turn 2FA off", Body: dto.TwoFactorDisableRequest{}, Response: ""}, wrapper(ws.api2FADisableHandler))
+			twoFactor.POST("/recovery-codes", routeDoc{Summary: "Replace the recovery codes", Body: dto.TwoFactorCodeRequest{}, Response: dto.RecoveryCodesResponse{}}, wrapper(ws.api2FARecoveryCodesHandler))
 		}
+	}
 
-		// Word tag endpoints
-		wordTags := api.Group("/word-tags")
-		// All word tag operations require authentication
-		wordTags.Use(ws.authMiddleware.RequireAuth())
+	// Admin endpoints
+	admin := api.Group("/admin", groupDoc{Tag: "Admin", Auth: authUser}, ws.authMiddleware.RequireAuth(), ws.authMiddleware.RequireSession(), ws.authMiddleware.RequireAdmin())
+	{
+		admin.GET("/settings/security", routeDoc{Summary: "Get the security settings", Response: dto.SecuritySettings{}}, wrapper(ws.apiGetSecuritySettingsHandler))
+		admin.PUT("/settings/security", routeDoc{Summary: "Update the security settings", Body: dto.SecuritySettings{}, Response: dto.SecuritySettings{}}, wrapper(ws.apiUpdateSecuritySettingsHandler))
+	}
+
+	// Protected user endpoints
+	user := api.Group("/user", groupDoc{Tag: "Account", Auth: authUser}, ws.authMiddleware.RequireAuth())
+	{
+		user.GET("/profile", routeDoc{Summary: "Get the profile of the current user", Response: dto.UserResponse{}}, ws.authMiddleware.RequireScope(table.ScopeReadUser), wrapper(ws.apiGetUserProfileHandler))
+		user.GET("/export", routeDoc{Summary: "Download the personal data of the current user", Query: dto.UserExportParams{}, Produces: map[string]interface{}{"application/zip": openapi3.NewStringSchema().WithFormat("binary"), "application/json": dto.UserExport{}}}, ws.authMiddleware.RequireSession(), ws.userExportHandler)
+		user.POST("/delete", routeDoc{Summary: "Delete the current user's account", Body: dto.DeleteAccountRequest{}, Response: dto.DeleteAccountResponse{}}, ws.authMiddleware.RequireSession(), wrapper(ws.apiDeleteAccountHandler))
+
+		// Personal access tokens can only be managed from a login session
+		tokens := user.Group("/tokens", groupDoc{Tag: "Access tokens"}, ws.authMiddleware.RequireSession())
 		{
-			wordTags.POST("/mark", wrapper(ws.apiMarkWordHandler))
-			wordTags.DELETE("/unmark", wrapper(ws.apiUnmarkWordHandler))
-			wordTags.GET("/status/:wordId", wrapper(ws.apiGetWordMarkStatusHandler))
-			wordTags.POST("/known", wrapper(ws.apiGetKnownWordsHandler))
-			wordTags.GET("/progress", wrapper(ws.apiGetUserProgressHandler))
-			wordTags.GET("/stats", wrapper(ws.apiGetWordTagStatsHandler))
-			wordTags.POST("/forget-words", wrapper(ws.apiForgetWordsHandler))
-			wordTags.POST("/forget-all", wrapper(ws.apiForgetAllHandler))
+			tokens.GET("", routeDoc{Summary: "List personal access tokens", Response: []table.PersonalAccessToken{}}, wrapper(ws.apiListAccessTokensHandler))
+			tokens.POST("", routeDoc{Summary: "Create a personal access token", Body: dto.CreateAccessTokenRequest{}, Response: dto.AccessTokenCreatedResponse{}}, wrapper(ws.apiCreateAccessTokenHandler))
+			tokens.DELETE("/:id", routeDoc{Summary: "Revoke a personal access token", Response: ""}, wrapper(ws.apiRevokeAccessTokenHandler))
 		}
 	}
+
+	// Word tag operations require authentication or a guest session
+	wordTags := api.Group("/word-tags", groupDoc{Tag: "Word tags", Auth: authUserOrGuest}, ws.authMiddleware.RequireAuthOrGuest())
+	{
+		writeTags := ws.authMiddleware.RequireScope(table.ScopeWriteTags)
+
+		wordTags.POST("/mark", routeDoc{Summary: "Mark a word as known", Body: dto.WordMarkRequest{}, Response: dto.WordMarkResponse{}}, writeTags, wrapper(ws.apiMarkWordHandler))
+		wordTags.DELETE("/unmark", routeDoc{Summary: "Remove the mark from a word", Body: dto.WordMarkRequest{}, Response: dto.WordMarkResponse{}}, writeTags, wrapper(ws.apiUnmarkWordHandler))
+		wordTags.GET("/status/:wordId", routeDoc{Summary: "Get the mark status of a word", Response: dto.WordMarkStatus{}}, readWords, wrapper(ws.apiGetWordMarkStatusHandler))
+		wordTags.POST("/known", routeDoc{Summary: "Get the mark status of the given words, or list the known words", Query: dto.BaseList{}, Body: dto.WordMarkStatusRequest{}, OptionalBody: true, Response: oneOf(dto.WordMarkStatusResponse{}, dto.VocabularyPageWithMarks{})}, readWords, wrapper(ws.apiGetKnownWordsHandler))
+		wordTags.GET("/progress", routeDoc{Summary: "Get the learning progress", Response: dto.UserProgressResponse{}}, readWords, wrapper(ws.apiGetUserProgressHandler))
+		wordTags.GET("/stats", routeDoc{Summary: "Get word tag statistics", Auth: authUser, Response: dto.WordTagStats{}}, ws.authMiddleware.RequireUser(), readWords, wrapper(ws.apiGetWordTagStatsHandler))
+		wordTags.POST("/forget-words", routeDoc{Summary: "Forget known words", Body: dto.ForgetWordsRequest{}, Response: dto.ForgetWordsResponse{}}, writeTags, wrapper(ws.apiForgetWordsHandler))
+		wordTags.POST("/forget-all", routeDoc{Summary: "Forget all known words", Body: dto.ForgetAllRequest{}, Response: dto.ForgetAllResponse{}}, writeTags, wrapper(ws.apiForgetAllHandler))
+	}
+
+	// Offline clients send their changes and fetch those of the other clients
+	api.POST("/sync", routeDoc{Summary: "Sync the changes of an offline client", Tag: "Word tags", Auth: authUserOrGuest, Body: dto.SyncRequest{}, Response: dto.SyncResponse{}}, ws.authMiddleware.RequireAuthOrGuest(), ws.authMiddleware.RequireScope(table.ScopeWriteTags), wrapper(ws.apiSyncHandler))
+
+	// Stream of changes made by the user's other clients
+	api.GET("/events", routeDoc{Summary: "Stream changes of known words and progress as server-sent events", Tag: "Word tags", Auth: authUserOrGuest, Produces: map[string]interface{}{"text/event-stream": dto.Event{}}}, ws.authMiddleware.RequireAuthOrGuest(), ws.authMiddleware.RequireScope(table.ScopeReadWords), ws.eventsHandler)
 }
 
 // Start starts the web server
@@ -175,6 +270,13 @@ func (ws *WebServer) loggingMiddleware() gin.HandlerFunc {
 	}
 }
 
+// jwksHandler serves the JSON Web Key Set. It is a standard document, so it
+// is not wrapped in the API response envelope.
+func (ws *WebServer) jwksHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ws.authService.JWKS())
+}
+
 // homeHandler handles the main page
 func (ws *WebServer) homeHandler(c *gin.Context) {
 	// Prepare minimal template data - no word data
@@ -200,7 +302,7 @@ func (ws *WebServer) homeHandler(c *gin.Context) {
 // apiWordsHandler handles API requests for words with pagination
 func (ws *WebServer) apiWordsHandler(c *gin.Context) (interface{}, error) {
 
-	var req dto.BaseList
+	var req dto.WordListRequest
 	if err := c.ShouldBind(&req); err != nil {
 		log.Error(err).Send()
 		return nil, err
@@ -211,8 +313,14 @@ func (ws *WebServer) apiWordsHandler(c *gin.Context) (interface{}, error) {
 	if req.PageNum == 0 {
 		req.PageNum = 1
 	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	filter, err := ws.vocabularyService.BuildWordFilter(req.WordFilterParams, nil, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get page data using service layer
-	responseData, err := ws.pagerService.GetPageData(req)
+	responseData, err := ws.pagerService.GetPageData(req.BaseList, filter)
 	if err != nil {
 		log.Error(err).Int("page", req.PageNum).Msg("Failed to get page data for API")
 		return nil, err
@@ -237,7 +345,8 @@ func (ws *WebServer) apiSearchHandler(c *gin.Context) (interface{}, error) {
 	log.Debug().Str("query", req.Q).Msg("Search request")
 
 	// Use service layer for search
-	total, results, err := ws.vocabularyService.SearchWords(req)
+	userID, _ := middleware.GetUserIDFromContext(c)
+	total, results, next, err := ws.vocabularyService.SearchWords(req, userID)
 	if err != nil {
 		log.Error(err).Str("query", req.Q).Msg("Search failed")
 		return nil, err
@@ -246,11 +355,45 @@ func (ws *WebServer) apiSearchHandler(c *gin.Context) (interface{}, error) {
 	log.Debug().Str("query", req.Q).Int("results", len(results)).Msg("Search completed")
 
 	return pke.BaseListResp{
-		Items: results,
-		Total: total,
+		Items:      results,
+		Total:      total,
+		NextCursor: next,
+	}, nil
+}
+
+// apiPatternSearchHandler handles regular expression and wildcard searches
+func (ws *WebServer) apiPatternSearchHandler(c *gin.Context) (interface{}, error) {
+	var req dto.WordPatternSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+	req.Q = strings.TrimSpace(req.Q)
+	if req.Q == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	total, results, next, err := ws.vocabularyService.SearchWordsByPattern(c.Request.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pke.BaseListResp{
+		Items:      results,
+		Total:      total,
+		NextCursor: next,
 	}, nil
 }
 
+// apiSuggestHandler returns autocomplete suggestions for a prefix
+func (ws *WebServer) apiSuggestHandler(c *gin.Context) (interface{}, error) {
+	var req dto.WordSuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	return ws.suggestService.Suggest(req.Q, req.Limit), nil
+}
+
 // apiStatsHandler handles statistics requests using service layer
 func (ws *WebServer) apiStatsHandler(c *gin.Context) (interface{}, error) {
 	// Get stats from service layer
@@ -280,11 +423,92 @@ func (ws *WebServer) apiRegisterHandler(c *gin.Context) (interface{}, error) {
 	}
 
 	log.Info().Str("user_id", user.ID).Str("username", user.Username).Msg("User registered successfully")
+	ws.mergeGuest(c, user.ID)
 
-	return map[string]interface{}{
-		"user":  models.NewUserBusiness(user).ToResponse(),
-		"token": token,
-	}, nil
+	response := authResponse(user, token)
+	response.EmailVerificationRequired = token == ""
+	return response, nil
+}
+
+// apiVerifyEmailHandler verifies a user's email address and logs them in
+func (ws *WebServer) apiVerifyEmailHandler(c *gin.Context) (interface{}, error) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	user, token, err := ws.authService.VerifyEmail(req.Token)
+	if err != nil {
+		log.Error(err).Msg("Email verification failed")
+		return nil, err
+	}
+
+	return authResponse(user, token), nil
+}
+
+// verifyEmailLinkHandler handles the verification link sent by email and
+// redirects the browser back to the home page with the result
+func (ws *WebServer) verifyEmailLinkHandler(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Redirect(http.StatusFound, "/?emailVerified=0")
+		return
+	}
+
+	if _, _, err := ws.authService.VerifyEmail(req.Token); err != nil {
+		log.Error(err).Msg("Email verification link failed")
+		c.Redirect(http.StatusFound, "/?emailVerified=0")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/?emailVerified=1")
+}
+
+// apiResendVerificationHandler sends a new verification email
+func (ws *WebServer) apiResendVerificationHandler(c *gin.Context) (interface{}, error) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	if err := ws.authService.ResendVerification(req.Email); err != nil {
+		log.Error(err).Str("email", req.Email).Msg("Failed to resend verification email")
+		return nil, err
+	}
+
+	// Same response whether or not the address is registered
+	return i18n.T(middleware.Language(c), "auth.verification_sent"), nil
+}
+
+// apiForgotPasswordHandler sends a password reset email
+func (ws *WebServer) apiForgotPasswordHandler(c *gin.Context) (interface{}, error) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	if err := ws.authService.ForgotPassword(req.Email); err != nil {
+		log.Error(err).Str("email", req.Email).Msg("Failed to process forgot password request")
+		return nil, err
+	}
+
+	// Same response whether or not the address is registered
+	return i18n.T(middleware.Language(c), "auth.reset_sent"), nil
+}
+
+// apiResetPasswordHandler sets a new password using a reset token
+func (ws *WebServer) apiResetPasswordHandler(c *gin.Context) (interface{}, error) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	if err := ws.authService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		log.Warn().Err(err).Msg("Password reset failed")
+		return nil, err
+	}
+
+	return i18n.T(middleware.Language(c), "auth.password_reset"), nil
 }
 
 // apiLoginHandler handles user login
@@ -294,27 +518,74 @@ func (ws *WebServer) apiLoginHandler(c *gin.Context) (interface{}, error) {
 		return nil, pke.NewApiError(pke.CodeInvalidRequest)
 	}
 
+	// Set client IP for rate limiting
+	req.ClientIP = c.ClientIP()
+
 	log.Debug().Str("username", req.Username).Msg("Login request")
 
-	user, token, err := ws.authService.Login(&req)
+	result, err := ws.authService.Login(&req)
 	if err != nil {
 		log.Error(err).Str("username", req.Username).Msg("Login failed")
 		return nil, err
 	}
 
-	log.Info().Str("user_id", user.ID).Str("username", user.Username).Msg("User logged in successfully")
+	// Password accepted, the client must now submit a 2FA code
+	if result.ChallengeToken != "" {
+		return &dto.AuthResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+		}, nil
+	}
+
+	log.Info().Str("user_id", result.User.ID).Str("username", result.User.Username).Msg("User logged in successfully")
+	ws.mergeGuest(c, result.User.ID)
 
-	return map[string]interface{}{
-		"user":  models.NewUserBusiness(user).ToResponse(),
-		"token": token,
-	}, nil
+	return loginResponse(result), nil
+}
+
+// apiLogin2FAHandler completes a login with a TOTP or recovery code
+func (ws *WebServer) apiLogin2FAHandler(c *gin.Context) (interface{}, error) {
+	var req dto.Login2FARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	// Set client IP for rate limiting
+	req.ClientIP = c.ClientIP()
+
+	result, err := ws.authService.Login2FA(&req)
+	if err != nil {
+		log.Warn().Err(err).Msg("2FA login failed")
+		return nil, err
+	}
+
+	log.Info().Str("user_id", result.User.ID).Str("username", result.User.Username).Msg("User logged in with 2FA")
+	ws.mergeGuest(c, result.User.ID)
+
+	return loginResponse(result), nil
+}
+
+// loginResponse builds the response for a completed login
+func loginResponse(result *dto.LoginResult) *dto.AuthResponse {
+	response := authResponse(result.User, result.Token)
+	response.TwoFactorSetupRequired = result.TwoFactorSetupRequired
+	return response
+}
+
+// authResponse builds the response for a user who got a session token
+func authResponse(user *table.User, token string) *dto.AuthResponse {
+	userResponse := models.NewUserBusiness(user).ToResponse()
+	return &dto.AuthResponse{
+		User:  &userResponse,
+		Token: token,
+	}
 }
 
 // apiLogoutHandler handles user logout
 func (ws *WebServer) apiLogoutHandler(c *gin.Context) (interface{}, error) {
 	// For JWT, logout is typically handled client-side by token removal
 	// Server-side logout could involve token blacklisting if needed
-	return "Logout successful", nil
+	return i18n.T(middleware.Language(c), "auth.logout_success"), nil
 }
 
 // apiGetCurrentUserHandler gets the current authenticated user
@@ -373,7 +644,7 @@ func (ws *WebServer) apiChangePasswordHandler(c *gin.Context) (interface{}, erro
 
 	log.Info().Str("user_id", userID).Msg("User password changed successfully")
 
-	return "Password changed successfully", nil
+	return i18n.T(middleware.Language(c), "auth.password_changed"), nil
 }
 
 // apiGetUserProfileHandler gets user profile
@@ -411,6 +682,7 @@ func (ws *WebServer) apiMarkWordHandler(c *gin.Context) (interface{}, error) {
 
 	// Set user ID from context
 	req.UserID = userID
+	req.Lang = middleware.Language(c)
 
 	response, err := ws.wordTagService.MarkWordAsKnown(&req)
 	if err != nil {
@@ -437,6 +709,7 @@ func (ws *WebServer) apiUnmarkWordHandler(c *gin.Context) (interface{}, error) {
 
 	// Set user ID from context
 	req.UserID = userID
+	req.Lang = middleware.Language(c)
 
 	response, err := ws.wordTagService.RemoveWordMark(&req)
 	if err != nil {
@@ -492,7 +765,23 @@ func (ws *WebServer) apiGetKnownWordsHandler(c *gin.Context) (interface{}, error
 		return response, nil
 	}
 
-	response, err := ws.vocabularyService.GetKnownWordsByUser(userID, nil)
+	// Paging is optional; without it all known words are returned
+	var list dto.BaseList
+	if err := c.ShouldBindQuery(&list); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidPagination)
+	}
+	var baseList *dao.BaseList
+	if list.Cursor != "" || list.PageSize > 0 {
+		baseList = &dao.BaseList{
+			PageNum:   list.PageNum,
+			PageSize:  list.PageSize,
+			Sort:      list.Sort,
+			Cursor:    list.Cursor,
+			SkipTotal: list.SkipTotal,
+		}
+	}
+
+	response, err := ws.vocabularyService.GetKnownWordsByUser(userID, baseList)
 	if err != nil {
 		log.Error(err).Str("user_id", userID).Msg("Failed to get known words")
 		return nil, err
@@ -549,6 +838,7 @@ func (ws *WebServer) apiForgetWordsHandler(c *gin.Context) (interface{}, error)
 
 	log.Debug().Str("user_id", userID).Int("word_count", len(req.WordIDs)).Msg("Forget words request")
 
+	req.Lang = middleware.Language(c)
 	response, err := ws.wordTagService.ForgetWords(userID, &req)
 	if err != nil {
 		log.Error(err).Str("user_id", userID).Int("word_count", len(req.WordIDs)).Msg("Failed to forget words")
@@ -574,6 +864,7 @@ func (ws *WebServer) apiForgetAllHandler(c *gin.Context) (interface{}, error) {
 
 	log.Debug().Str("user_id", userID).Bool("confirm", req.Confirm).Msg("Forget all request")
 
+	req.Lang = middleware.Language(c)
 	response, err := ws.wordTagService.ForgetAllWords(userID, &req)
 	if err != nil {
 		log.Error(err).Str("user_id", userID).Msg("Failed to forget all words")
@@ -582,3 +873,149 @@ func (ws *WebServer) apiForgetAllHandler(c *gin.Context) (interface{}, error) {
 
 	return response, nil
 }
+
+// api2FAStatusHandler returns the 2FA state of the current user
+func (ws *WebServer) api2FAStatusHandler(c *gin.Context) (interface{}, error) {
+	user, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	return ws.authService.Get2FAStatus(user)
+}
+
+// api2FASetupHandler starts TOTP enrollment and returns the secret and QR code
+func (ws *WebServer) api2FASetupHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	return ws.authService.Setup2FA(userID)
+}
+
+// api2FAEnableHandler confirms TOTP enrollment with a first code
+func (ws *WebServer) api2FAEnableHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	codes, err := ws.authService.Enable2FA(userID, req.Code)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Failed to enable 2FA")
+		return nil, err
+	}
+
+	return dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// api2FADisableHandler turns 2FA off for the current user
+func (ws *WebServer) api2FADisableHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	var req dto.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	if err := ws.authService.Disable2FA(userID, &req); err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Failed to disable 2FA")
+		return nil, err
+	}
+
+	return i18n.T(middleware.Language(c), "auth.two_factor_disabled"), nil
+}
+
+// api2FARecoveryCodesHandler replaces the recovery codes of the current user
+func (ws *WebServer) api2FARecoveryCodesHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	codes, err := ws.authService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// apiGetSecuritySettingsHandler returns the security settings
+func (ws *WebServer) apiGetSecuritySettingsHandler(c *gin.Context) (interface{}, error) {
+	return ws.authService.GetSecuritySettings()
+}
+
+// apiUpdateSecuritySettingsHandler updates the security settings
+func (ws *WebServer) apiUpdateSecuritySettingsHandler(c *gin.Context) (interface{}, error) {
+	var req dto.SecuritySettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	if err := ws.authService.UpdateSecuritySettings(&req); err != nil {
+		return nil, err
+	}
+
+	settings, err := ws.authService.GetSecuritySettings()
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	log.Info().Str("user_id", userID).Bool("require_2fa_for_admin", *settings.Require2FAForAdmin).Bool("ldap_link_by_email", *settings.LDAPLinkByEmail).Msg("Security settings updated")
+
+	return settings, nil
+}
+
+// apiListAccessTokensHandler lists the personal access tokens of the current user
+func (ws *WebServer) apiListAccessTokensHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	return ws.accessTokenService.List(userID)
+}
+
+// apiCreateAccessTokenHandler creates a personal access token
+func (ws *WebServer) apiCreateAccessTokenHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	var req dto.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	return ws.accessTokenService.Create(userID, &req)
+}
+
+// apiRevokeAccessTokenHandler revokes a personal access token
+func (ws *WebServer) apiRevokeAccessTokenHandler(c *gin.Context) (interface{}, error) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUnauthorized)
+	}
+
+	if err := ws.accessTokenService.Revoke(userID, c.Param("id")); err != nil {
+		return nil, err
+	}
+	return i18n.T(middleware.Language(c), "auth.token_revoked"), nil
+}
@@ -2,9 +2,15 @@ package router
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sanmu2018/word-hero/internal/middleware"
+	"github.com/sanmu2018/word-hero/internal/service"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
 	"github.com/sanmu2018/word-hero/pkg/pke"
 )
 
@@ -14,20 +20,45 @@ func wrapper(handler RequestProcessor) func(c *gin.Context) {
 	return func(c *gin.Context) {
 
 		data, err := handler(c)
-		resp := pke.APIResponse{Data: data}
-
-		statusCode := http.StatusOK
 		if err != nil { //process for error
-			statusCode = http.StatusBadRequest
-			var h *pke.APIResponse
-			if errors.As(err, &h) {
-				resp.Code = h.ErrorNo()
-				resp.Msg = h.Error()
-			} else { //如果不是规范的错误，则统一返回 "系统内部错误: << .msg >>"
-				resp.Code = pke.CodeSystemError
-				resp.Msg = pke.GetErrorMessage(pke.CodeSystemError)
-			}
+			writeError(c, err)
+			return
 		}
-		c.JSON(statusCode, resp)
+		c.JSON(http.StatusOK, pke.APIResponse{Data: data})
+	}
+}
+
+// writeError writes err in the API response format, for handlers that write
+// their own response on success. The HTTP status follows the error code.
+func writeError(c *gin.Context, err error) {
+	resp := pke.APIResponse{}
+	detail := ""
+	var detailArgs []interface{}
+	var h *pke.APIResponse
+	var de *service.DomainError
+	if errors.As(err, &h) {
+		resp.Code = h.ErrorNo()
+		detail = h.Detail
+		detailArgs = h.DetailArgs
+	} else if errors.As(err, &de) {
+		resp.Code = de.ErrorNo()
+		detail = de.Detail
+	} else { //如果不是规范的错误，则统一返回 "系统内部错误"，原因只记录在日志中
+		log.Error(err).Str("path", c.FullPath()).Msg("Unhandled error in API handler")
+		resp.Code = pke.CodeSystemError
+	}
+
+	// 错误消息按请求的语言输出
+	lang := middleware.Language(c)
+	resp.Msg = i18n.ErrorMessage(lang, resp.Code)
+	if detail != "" {
+		resp.Msg += ": " + i18n.T(lang, detail, detailArgs...)
+	}
+
+	// 限流错误通过Retry-After告知客户端等待的秒数
+	var ra *pke.RetryAfterError
+	if errors.As(err, &ra) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(ra.RetryAfter.Seconds()))))
 	}
+	c.JSON(pke.HTTPStatus(resp.Code), resp)
 }
@@ -0,0 +1,43 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+func TestWriteErrorLocalizesDetail(t *testing.T) {
+	registry := dao.NewSortRegistry("id", map[string]dao.SortField{
+		"english":    {Column: "english"},
+		"difficulty": {Column: "difficulty"},
+	})
+	_, sortErr := registry.Parse("chinese")
+	parsed := sortErr.(*dao.SortError)
+	err := pke.NewApiError(pke.CodeInvalidRequest).WithDetail(parsed.Key, parsed.DetailArgs()...)
+
+	for lang, want := range map[string]string{
+		i18n.EN:   "Invalid request parameters: Unknown sort field \"chinese\"; allowed sort fields: difficulty, english",
+		i18n.ZhCN: i18n.ErrorMessage(i18n.ZhCN, pke.CodeInvalidRequest) + ": 未知的排序字段 \"chinese\"，可排序字段: difficulty, english",
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/words", nil)
+		c.Request.Header.Set("Accept-Language", lang)
+		writeError(c, err)
+
+		var resp pke.APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		if w.Code != http.StatusBadRequest || resp.Msg != want {
+			t.Errorf("%s: writeError() = %d %q, want 400 %q", lang, w.Code, resp.Msg, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+const (
+	// accessTokenLength is the number of random characters after the prefix
+	accessTokenLength = 40
+	// maxAccessTokensPerUser bounds how many active tokens a user can hold
+	maxAccessTokensPerUser = 50
+)
+
+// AccessTokenService manages personal access tokens used by scripts and integrations
+type AccessTokenService struct {
+	tokenDAO *dao.PersonalAccessTokenDAO
+	userDAO  *dao.UserDAO
+}
+
+// NewAccessTokenService creates a new AccessTokenService instance
+func NewAccessTokenService(tokenDAO *dao.PersonalAccessTokenDAO, userDAO *dao.UserDAO) *AccessTokenService {
+	return &AccessTokenService{
+		tokenDAO: tokenDAO,
+		userDAO:  userDAO,
+	}
+}
+
+// Create issues a new token. The plain token is only returned here.
+func (s *AccessTokenService) Create(userID string, req *dto.CreateAccessTokenRequest) (*dto.AccessTokenCreatedResponse, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.tokenDAO.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAccessTokensPerUser {
+		return nil, pke.NewApiError(pke.CodeOutOfRange)
+	}
+
+	token := table.PersonalAccessTokenPrefix + utils.GenerateRandomString(accessTokenLength)
+	record := &table.PersonalAccessToken{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		TokenPrefix: token[:len(table.PersonalAccessTokenPrefix)+4],
+		TokenHash:   utils.HashToken(token),
+		Scopes:      strings.Join(scopes, " "),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays).UnixMilli()
+		record.ExpiresAt = &expiresAt
+	}
+
+	if err := s.tokenDAO.Create(record); err != nil {
+		return nil, err
+	}
+	return &dto.AccessTokenCreatedResponse{
+		Token:       token,
+		AccessToken: record,
+	}, nil
+}
+
+// List returns the active tokens of a user
+func (s *AccessTokenService) List(userID string) ([]table.PersonalAccessToken, error) {
+	return s.tokenDAO.ListByUser(userID)
+}
+
+// Revoke revokes one of the user's tokens
+func (s *AccessTokenService) Revoke(userID, tokenID string) error {
+	revoked, err := s.tokenDAO.Revoke(userID, tokenID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return pke.NewApiError(pke.CodeNotFound)
+	}
+	return nil
+}
+
+// Authenticate resolves a personal access token to its user and scopes and
+// records the use
+func (s *AccessTokenService) Authenticate(token, clientIP string) (*table.User, []string, error) {
+	record, err := s.tokenDAO.FindActiveByHash(utils.HashToken(token))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	user, err := s.userDAO.FindByID(record.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: user: %w", ErrInvalidToken, err)
+	}
+	if !user.IsActive {
+		return nil, nil, ErrUserDisabled
+	}
+
+	if err := s.tokenDAO.TouchLastUsed(record.ID, clientIP); err != nil {
+		log.Warn().Err(err).Str("token_id", record.ID).Msg("Failed to record access token use")
+	}
+	return user, strings.Fields(record.Scopes), nil
+}
+
+// IsAccessToken reports whether a bearer token is a personal access token
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, table.PersonalAccessTokenPrefix)
+}
+
+// normalizeScopes validates requested scopes and removes duplicates
+func normalizeScopes(requested []string) ([]string, error) {
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		scope = strings.TrimSpace(scope)
+		if !slices.Contains(table.PersonalAccessTokenScopes, scope) {
+			return nil, pke.NewApiError(pke.CodeInvalidRequest)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+	slices.Sort(scopes)
+	return scopes, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+func TestCreateAccessTokenNormalizesScopes(t *testing.T) {
+	mock := daotest.Mock(t)
+	s := NewAccessTokenService(dao.NewPersonalAccessTokenDAO(), dao.NewUserDAO())
+
+	for _, scopes := range [][]string{nil, {"admin"}, {table.ScopeReadWords, "write:words"}} {
+		if _, err := s.Create("user-1", &dto.CreateAccessTokenRequest{Name: "script", Scopes: scopes}); errorNo(err) != pke.CodeInvalidRequest {
+			t.Errorf("Create(%q) error = %v, want CodeInvalidRequest", scopes, err)
+		}
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "personal_access_tokens" WHERE user_id = .* AND revoked_at IS NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`INSERT INTO "personal_access_tokens"`).
+		WithArgs("user-1", "script", sqlmock.AnyArg(), sqlmock.AnyArg(), "read:words write:tags", nil, nil, "", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("token-1"))
+
+	created, err := s.Create("user-1", &dto.CreateAccessTokenRequest{
+		Name:   "script",
+		Scopes: []string{table.ScopeWriteTags, " " + table.ScopeReadWords, table.ScopeWriteTags},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !IsAccessToken(created.Token) {
+		t.Errorf("Create() token %q lacks the access token prefix", created.Token)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "personal_access_tokens" WHERE token_hash = .* AND revoked_at IS NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "scopes"}).AddRow("token-1", "user-1", created.AccessToken.Scopes))
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "is_active"}).AddRow("user-1", "bob", true))
+	mock.ExpectExec(`UPDATE "personal_access_tokens" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, scopes, err := s.Authenticate(created.Token, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if !slices.Equal(scopes, []string{table.ScopeReadWords, table.ScopeWriteTags}) {
+		t.Errorf("Authenticate() scopes = %q, want read:words and write:tags", scopes)
+	}
+}
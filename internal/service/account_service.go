@@ -0,0 +1,209 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/models"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// accountPurgeInterval is how often deleted accounts and stale guests are purged
+const accountPurgeInterval = time.Hour
+
+// AccountService handles personal data export and account deletion
+type AccountService struct {
+	authService     *AuthService
+	userDAO         *dao.UserDAO
+	wordTagDAO      *dao.WordTagDAO
+	identityDAO     *dao.UserIdentityDAO
+	tokenDAO        *dao.PersonalAccessTokenDAO
+	recoveryCodeDAO *dao.RecoveryCodeDAO
+	guestDAO        *dao.GuestDAO
+	gracePeriod     time.Duration
+	guestTTL        time.Duration
+}
+
+// NewAccountService creates a new AccountService instance
+func NewAccountService(authService *AuthService, userDAO *dao.UserDAO, wordTagDAO *dao.WordTagDAO, identityDAO *dao.UserIdentityDAO, tokenDAO *dao.PersonalAccessTokenDAO, recoveryCodeDAO *dao.RecoveryCodeDAO, guestDAO *dao.GuestDAO, config *conf.AuthConfig) (*AccountService, error) {
+	gracePeriod, err := time.ParseDuration(config.DeletionGracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deletion grace period: %w", err)
+	}
+	guestTTL, err := time.ParseDuration(config.GuestTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid guest ttl: %w", err)
+	}
+
+	return &AccountService{
+		authService:     authService,
+		userDAO:         userDAO,
+		wordTagDAO:      wordTagDAO,
+		identityDAO:     identityDAO,
+		tokenDAO:        tokenDAO,
+		recoveryCodeDAO: recoveryCodeDAO,
+		guestDAO:        guestDAO,
+		gracePeriod:     gracePeriod,
+		guestTTL:        guestTTL,
+	}, nil
+}
+
+// Export collects the personal data stored for a user
+func (s *AccountService) Export(userID string) (*dto.UserExport, error) {
+	user, err := s.userDAO.FindByID(userID)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUserNotFound)
+	}
+
+	wordTags, err := s.wordTagDAO.ListForExport(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := s.identityDAO.ListByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.tokenDAO.ListByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodes, err := s.recoveryCodeDAO.CountUnused(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("user_id", user.ID).Int("word_tags", len(wordTags)).Msg("Personal data exported")
+	return &dto.UserExport{
+		ExportedAt:   time.Now().UnixMilli(),
+		Profile:      models.NewUserBusiness(user).ToResponse(),
+		WordTags:     wordTags,
+		Identities:   identities,
+		AccessTokens: tokens,
+		Settings: dto.UserSettingsExport{
+			EmailVerifiedAt:        user.EmailVerifiedAt,
+			TwoFactorEnabledAt:     user.TOTPEnabledAt,
+			RecoveryCodesRemaining: recoveryCodes,
+		},
+	}, nil
+}
+
+// WriteExportArchive writes an export as a ZIP archive with one JSON file per section
+func (s *AccountService) WriteExportArchive(w io.Writer, export *dto.UserExport) error {
+	archive := zip.NewWriter(w)
+	for _, file := range []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"word_tags.json", export.WordTags},
+		{"identities.json", export.Identities},
+		{"access_tokens.json", export.AccessTokens},
+		{"settings.json", export.Settings},
+	} {
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: time.UnixMilli(export.ExportedAt),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s to export: %w", file.name, err)
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return fmt.Errorf("failed to write %s to export: %w", file.name, err)
+		}
+	}
+	return archive.Close()
+}
+
+// Delete soft-deletes the account after the user confirms it. The account
+// stops working immediately; its data is purged after the grace period.
+func (s *AccountService) Delete(userID string, req *dto.DeleteAccountRequest) (*dto.DeleteAccountResponse, error) {
+	user, err := s.userDAO.FindByID(userID)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUserNotFound)
+	}
+	if req.Confirm != user.Username {
+		return nil, pke.NewApiError(pke.CodeInvalidRequest)
+	}
+
+	// Users provisioned by an identity provider may never have set a
+	// password; for them the confirmation and current session must do.
+	// Directory users confirm with their directory password.
+	passwordRequired := user.PasswordHash != ""
+	if !passwordRequired {
+		identities, err := s.identityDAO.ListByUser(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, identity := range identities {
+			if identity.Provider == ldapProvider {
+				passwordRequired = true
+			}
+		}
+	}
+	if passwordRequired {
+		if err := s.authService.verifyCredentials(user, req.Password, req.ClientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	// Never leave the application without an administrator
+	if user.Role == "admin" {
+		admins, err := s.userDAO.FindAdmins()
+		if err != nil {
+			return nil, err
+		}
+		if len(admins) <= 1 {
+			return nil, pke.NewApiError(pke.CodeInvalidOperation)
+		}
+	}
+
+	if err := s.userDAO.Delete(user.ID); err != nil {
+		return nil, err
+	}
+
+	purgeAt := time.Now().Add(s.gracePeriod)
+	log.Info().Str("user_id", user.ID).Str("username", user.Username).Time("purge_at", purgeAt).Msg("Account deleted by user")
+	return &dto.DeleteAccountResponse{PurgeAt: purgeAt.UnixMilli()}, nil
+}
+
+// StartPurger purges expired data now and then periodically in the background
+func (s *AccountService) StartPurger() {
+	go func() {
+		ticker := time.NewTicker(accountPurgeInterval)
+		defer ticker.Stop()
+		for {
+			s.Purge()
+			<-ticker.C
+		}
+	}()
+}
+
+// Purge permanently removes accounts deleted longer than the grace period ago
+// and guests inactive for longer than the guest TTL
+func (s *AccountService) Purge() {
+	now := time.Now()
+
+	users, err := s.userDAO.PurgeDeleted(now.Add(-s.gracePeriod).UnixMilli())
+	if err != nil {
+		log.Error(err).Msg("Failed to purge deleted accounts")
+	} else if users > 0 {
+		log.Info().Int("users", users).Msg("Purged deleted accounts")
+	}
+
+	guests, err := s.guestDAO.PurgeInactive(now.Add(-s.guestTTL).UnixMilli())
+	if err != nil {
+		log.Error(err).Msg("Failed to purge inactive guests")
+	} else if guests > 0 {
+		log.Info().Int("guests", guests).Msg("Purged inactive guests")
+	}
+}
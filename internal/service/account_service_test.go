@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+func newTestAccountService(t *testing.T) (*AccountService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := daotest.Mock(t)
+	userDAO := dao.NewUserDAO()
+	authService := &AuthService{userDAO: userDAO, loginGuard: newTestLoginGuard(t)}
+	authService.SetAuthenticators(&stubAuthenticator{
+		user:     &table.User{ID: "user-1", Username: "bob"},
+		password: "secret",
+	})
+	return &AccountService{
+		authService: authService,
+		userDAO:     userDAO,
+		identityDAO: dao.NewUserIdentityDAO(),
+	}, mock
+}
+
+// expectAccount returns bob, with a local password or without one
+func expectAccount(mock sqlmock.Sqlmock, passwordHash string) {
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).WithArgs("user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password_hash", "role", "is_active"}).
+			AddRow("user-1", "bob", passwordHash, "user", true))
+}
+
+func TestDeleteAccountRequiresLocalPassword(t *testing.T) {
+	s, mock := newTestAccountService(t)
+
+	// A linked provider identity does not replace a password the user has set
+	expectAccount(mock, "$2a$10$hash")
+	if _, err := s.Delete("user-1", &dto.DeleteAccountRequest{Confirm: "bob", ClientIP: "10.0.0.1"}); errorNo(err) != pke.CodeInvalidCredentials {
+		t.Fatalf("Delete() without password error = %v, want CodeInvalidCredentials", err)
+	}
+
+	expectAccount(mock, "$2a$10$hash")
+	mock.ExpectExec(`UPDATE "users" SET "deleted_at"=.* WHERE id = `).WillReturnResult(sqlmock.NewResult(0, 1))
+	if _, err := s.Delete("user-1", &dto.DeleteAccountRequest{Password: "secret", Confirm: "bob", ClientIP: "10.0.0.1"}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+}
+
+func TestDeleteAccountWithoutPassword(t *testing.T) {
+	s, mock := newTestAccountService(t)
+
+	expectAccount(mock, "")
+	mock.ExpectQuery(`SELECT \* FROM "user_identities" WHERE user_id = `).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider"}).AddRow("identity-1", "user-1", "school"))
+	mock.ExpectExec(`UPDATE "users" SET "deleted_at"=.* WHERE id = `).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := s.Delete("user-1", &dto.DeleteAccountRequest{Confirm: "bob", ClientIP: "10.0.0.1"}); err != nil {
+		t.Fatalf("Delete() error = %v, want the confirmation to suffice", err)
+	}
+}
+
+func TestDeleteAccountPasswordGuessesLockAccount(t *testing.T) {
+	s, mock := newTestAccountService(t)
+
+	var err error
+	for attempt := 1; attempt <= 3; attempt++ {
+		expectAccount(mock, "$2a$10$hash")
+		_, err = s.Delete("user-1", &dto.DeleteAccountRequest{Password: "guess", Confirm: "bob", ClientIP: "10.0.0.1"})
+	}
+	if errorNo(err) != pke.CodeAccountLocked {
+		t.Fatalf("third wrong password: Delete() error = %v, want CodeAccountLocked", err)
+	}
+
+	expectAccount(mock, "$2a$10$hash")
+	if _, err := s.Delete("user-1", &dto.DeleteAccountRequest{Password: "secret", Confirm: "bob", ClientIP: "10.0.0.2"}); errorNo(err) != pke.CodeAccountLocked {
+		t.Errorf("Delete() while locked error = %v, want CodeAccountLocked", err)
+	}
+}
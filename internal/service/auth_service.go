@@ -4,32 +4,62 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/sanmu2018/word-hero/internal/conf"
 	"github.com/sanmu2018/word-hero/internal/dao"
 	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/mailer"
 	"github.com/sanmu2018/word-hero/internal/models"
 	"github.com/sanmu2018/word-hero/internal/table"
 	"github.com/sanmu2018/word-hero/internal/utils"
 	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
 )
 
+// invalidUsernameChars matches characters not allowed in usernames
+var invalidUsernameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
 // AuthService handles authentication business logic
 type AuthService struct {
-	userDAO   *dao.UserDAO
-	jwtUtils  *utils.JWTUtils
+	userDAO         *dao.UserDAO
+	userTokenDAO    *dao.UserTokenDAO
+	recoveryCodeDAO *dao.RecoveryCodeDAO
+	settingDAO      *dao.SettingDAO
+	jwtUtils        *utils.JWTUtils
+	authenticators  []Authenticator
+	loginGuard      *LoginGuard
+	mailSender      mailer.Sender
+	authConfig      *conf.AuthConfig
+	baseURL         string
 }
 
 // NewAuthService creates a new AuthService instance
-func NewAuthService(userDAO *dao.UserDAO, jwtUtils *utils.JWTUtils) *AuthService {
+func NewAuthService(userDAO *dao.UserDAO, userTokenDAO *dao.UserTokenDAO, recoveryCodeDAO *dao.RecoveryCodeDAO, settingDAO *dao.SettingDAO, jwtUtils *utils.JWTUtils, loginGuard *LoginGuard, mailSender mailer.Sender, authConfig *conf.AuthConfig, baseURL string) *AuthService {
 	return &AuthService{
-		userDAO:  userDAO,
-		jwtUtils: jwtUtils,
+		userDAO:         userDAO,
+		userTokenDAO:    userTokenDAO,
+		recoveryCodeDAO: recoveryCodeDAO,
+		settingDAO:      settingDAO,
+		jwtUtils:        jwtUtils,
+		authenticators:  []Authenticator{NewPasswordAuthenticator(userDAO)},
+		loginGuard:      loginGuard,
+		mailSender:      mailSender,
+		authConfig:      authConfig,
+		baseURL:         strings.TrimRight(baseURL, "/"),
 	}
 }
 
-// Register registers a new user
+// SetAuthenticators replaces the credential sources Login tries, in order
+func (s *AuthService) SetAuthenticators(authenticators ...Authenticator) {
+	s.authenticators = authenticators
+}
+
+// Register registers a new user. When email verification is required the
+// returned token is empty until the user has verified their email address.
 func (s *AuthService) Register(req *dto.UserRegisterRequest) (*table.User, string, error) {
 	// Validate input
 	if err := s.validateRegistrationInput(req.Username, req.Email, req.Password); err != nil {
@@ -42,7 +72,7 @@ func (s *AuthService) Register(req *dto.UserRegisterRequest) (*table.User, strin
 		return nil, "", fmt.Errorf("failed to check username availability: %w", err)
 	}
 	if exists {
-		return nil, "", errors.New("username already exists")
+		return nil, "", ErrUsernameTaken
 	}
 
 	// Check if email already exists
@@ -51,7 +81,7 @@ func (s *AuthService) Register(req *dto.UserRegisterRequest) (*table.User, strin
 		return nil, "", fmt.Errorf("failed to check email availability: %w", err)
 	}
 	if exists {
-		return nil, "", errors.New("email already exists")
+		return nil, "", ErrEmailTaken
 	}
 
 	// Create new user
@@ -73,8 +103,22 @@ func (s *AuthService) Register(req *dto.UserRegisterRequest) (*table.User, strin
 		return nil, "", fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// Send verification email; the account exists either way and the user can request a resend
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Error(err).Str("user_id", user.ID).Msg("Failed to send verification email")
+	}
+
+	if s.authConfig.RequireEmailVerification {
+		log.Info().
+			Str("user_id", user.ID).
+			Str("username", user.Username).
+			Str("email", user.Email).
+			Msg("User registered, awaiting email verification")
+		return user, "", nil
+	}
+
 	// Generate JWT token
-	token, err := s.jwtUtils.GenerateToken(user.ID, user.Username, user.Email, user.Role)
+	token, err := s.jwtUtils.GenerateToken(user.ID, user.Username, user.Email, user.Role, user.SessionVersion)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -88,24 +132,48 @@ func (s *AuthService) Register(req *dto.UserRegisterRequest) (*table.User, strin
 	return user, token, nil
 }
 
-// Login authenticates a user and returns a token
-func (s *AuthService) Login(req *dto.UserLoginRequest) (*table.User, string, error) {
-	// Find user by username or email
-	user, err := s.userDAO.FindByUsernameOrEmail(req.Username)
+// Login authenticates a user. Users with 2FA enabled get a challenge token
+// instead of a session token and must finish with Login2FA.
+func (s *AuthService) Login(req *dto.UserLoginRequest) (*dto.LoginResult, error) {
+	// Throttle attempts per IP and username, and reject locked accounts
+	if err := s.loginGuard.Allow(req.ClientIP, req.Username); err != nil {
+		return nil, err
+	}
+
+	// Verify credentials
+	user, err := s.authenticate(req.Username, req.Password)
 	if err != nil {
-		return nil, "", errors.New("invalid username or password")
+		return nil, s.loginFailed(req)
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, "", errors.New("account is deactivated")
+		return nil, ErrUserDisabled
+	}
+
+	// Check if email is verified
+	if err := s.checkEmailVerified(user); err != nil {
+		return nil, err
 	}
 
-	// Verify password
-	if !s.verifyPassword(user, req.Password) {
-		return nil, "", errors.New("invalid username or password")
+	// Ask for the second factor before issuing a session. Failures are only
+	// cleared once Login2FA accepts a code, so repeating the password does not
+	// reset the lockout for code guesses.
+	if user.TOTPEnabledAt != nil {
+		challenge, err := s.issueLoginChallenge(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue login challenge: %w", err)
+		}
+		log.Info().Str("user_id", user.ID).Msg("Password accepted, awaiting 2FA code")
+		return &dto.LoginResult{User: user, ChallengeToken: challenge}, nil
 	}
+	s.loginGuard.Succeed(req.Username)
 
+	return s.completeLogin(user)
+}
+
+// completeLogin records the login and issues a session token
+func (s *AuthService) completeLogin(user *table.User) (*dto.LoginResult, error) {
 	// Update last login time
 	businessUser := models.NewUserBusiness(user)
 	if err := businessUser.UpdateLastLogin(); err != nil {
@@ -113,9 +181,9 @@ func (s *AuthService) Login(req *dto.UserLoginRequest) (*table.User, string, err
 	}
 
 	// Generate JWT token
-	token, err := s.jwtUtils.GenerateToken(user.ID, user.Username, user.Email, user.Role)
+	token, err := s.jwtUtils.GenerateToken(user.ID, user.Username, user.Email, user.Role, user.SessionVersion)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	log.Info().
@@ -123,30 +191,97 @@ func (s *AuthService) Login(req *dto.UserLoginRequest) (*table.User, string, err
 		Str("username", user.Username).
 		Msg("User logged in successfully")
 
-	return user, token, nil
+	return &dto.LoginResult{
+		User:                   user,
+		Token:                  token,
+		TwoFactorSetupRequired: s.TwoFactorSetupRequired(user),
+	}, nil
+}
+
+// checkEmailVerified applies the email verification policy. Users without an
+// address have nothing to verify: they were provisioned by a directory or
+// provider that vouches for them, and their address belongs to another
+// account or was never confirmed there.
+func (s *AuthService) checkEmailVerified(user *table.User) error {
+	if s.authConfig.RequireEmailVerification && user.Email != "" && user.EmailVerifiedAt == nil {
+		return pke.NewApiError(pke.CodeUserNotVerified)
+	}
+	return nil
+}
+
+// authenticate tries each authenticator in order and returns the first match
+func (s *AuthService) authenticate(username, password string) (*table.User, error) {
+	for _, authenticator := range s.authenticators {
+		user, err := authenticator.Authenticate(username, password)
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(err, ErrInvalidCredentials) {
+			log.Error(err).Str("authenticator", authenticator.Name()).Str("username", username).Msg("Authenticator unavailable")
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// verifyCredentials checks a password for an existing user against the
+// configured authenticators, such as before deleting the account. Wrong
+// passwords count towards the same limits and lockout as logins.
+func (s *AuthService) verifyCredentials(user *table.User, password, clientIP string) error {
+	if err := s.loginGuard.Allow(clientIP, user.Username); err != nil {
+		return err
+	}
+	authenticated, err := s.authenticate(user.Username, password)
+	if err != nil || authenticated.ID != user.ID {
+		if err := s.loginGuard.Fail(clientIP, user.Username); err != nil {
+			return err
+		}
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// loginFailed records a failed attempt and returns the error reported to the client
+func (s *AuthService) loginFailed(req *dto.UserLoginRequest) error {
+	if err := s.loginGuard.Fail(req.ClientIP, req.Username); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
 }
 
 // ValidateToken validates a JWT token and returns the user
 func (s *AuthService) ValidateToken(tokenString string) (*table.User, error) {
 	claims, err := s.jwtUtils.ValidateToken(tokenString)
 	if err != nil {
-		return nil, fmt.Errorf("invalid token: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
 	}
 
 	user, err := s.userDAO.FindByID(claims.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, fmt.Errorf("%w: user: %w", ErrInvalidToken, err)
 	}
 
 	if !user.IsActive {
-		return nil, errors.New("user account is deactivated")
+		return nil, ErrUserDisabled
+	}
+
+	// Tokens issued before a password reset are revoked
+	if claims.SessionVersion != user.SessionVersion {
+		return nil, fmt.Errorf("%w: token has been revoked", ErrInvalidToken)
 	}
 
 	return user, nil
 }
 
+// JWKS returns the public keys that verify issued tokens
+func (s *AuthService) JWKS() *utils.JWKSet {
+	return s.jwtUtils.JWKS()
+}
+
 // RefreshToken generates a new token for a valid existing token
 func (s *AuthService) RefreshToken(tokenString string) (string, error) {
+	if _, err := s.ValidateToken(tokenString); err != nil {
+		return "", err
+	}
 	return s.jwtUtils.RefreshToken(tokenString)
 }
 
@@ -155,12 +290,12 @@ func (s *AuthService) ChangePassword(userID string, req *dto.ChangePasswordReque
 	// Find user
 	user, err := s.userDAO.FindByID(userID)
 	if err != nil {
-		return fmt.Errorf("user not found: %w", err)
+		return notFound(ErrUserNotFound, err)
 	}
 
 	// Verify current password
 	if !s.verifyPassword(user, req.CurrentPassword) {
-		return errors.New("current password is incorrect")
+		return ErrIncorrectPassword
 	}
 
 	// Hash and update new password
@@ -180,17 +315,17 @@ func (s *AuthService) ChangePassword(userID string, req *dto.ChangePasswordReque
 func (s *AuthService) validateRegistrationInput(username, email, password string) error {
 	// Validate username format
 	if !s.isValidUsername(username) {
-		return errors.New("username must be 3-50 characters and contain only letters, numbers, and underscores")
+		return invalidInput("validation.username")
 	}
 
 	// Validate email format
 	if !s.isValidEmail(email) {
-		return errors.New("invalid email format")
+		return invalidInput("validation.email")
 	}
 
 	// Validate password strength
 	if !s.isValidPassword(password) {
-		return errors.New("password must be at least 6 characters long")
+		return invalidInput("validation.password_length")
 	}
 
 	return nil
@@ -215,6 +350,69 @@ func (s *AuthService) isValidPassword(password string) bool {
 	return len(password) >= 6
 }
 
+// provisionExternalUser creates a local user for someone authenticated by an
+// external identity source. The username is derived from the preferred name
+// and made unique; no password is set, so the account can only sign in
+// through that source until the user sets a password with a reset.
+func (s *AuthService) provisionExternalUser(preferredName, email, fullName, role string, emailVerified bool) (*table.User, error) {
+	username, err := s.uniqueUsername(preferredName, email)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &table.User{
+		Username: username,
+		Email:    email,
+		FullName: fullName,
+		Role:     role,
+		IsActive: true,
+	}
+	if emailVerified {
+		verifiedAt := time.Now().UnixMilli()
+		user.EmailVerifiedAt = &verifiedAt
+	}
+	if err := s.userDAO.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	log.Info().
+		Str("user_id", user.ID).
+		Str("username", user.Username).
+		Str("email", user.Email).
+		Str("role", user.Role).
+		Msg("User provisioned from external identity")
+	return user, nil
+}
+
+// uniqueUsername turns a preferred name (or the local part of email) into a
+// valid username that is not taken yet
+func (s *AuthService) uniqueUsername(preferredName, email string) (string, error) {
+	base := preferredName
+	if base == "" {
+		base, _, _ = strings.Cut(email, "@")
+	}
+	base = invalidUsernameChars.ReplaceAllString(base, "_")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	if len(base) < 3 {
+		base = "user_" + base
+	}
+
+	candidate := base
+	for i := 0; i < 10; i++ {
+		exists, err := s.userDAO.ExistsByUsername(candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check username availability: %w", err)
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = base + "_" + strings.ToLower(utils.GenerateRandomString(4))
+	}
+	return "", errors.New("failed to find an available username")
+}
+
 // hashPassword hashes a password using bcrypt
 func (s *AuthService) hashPassword(user *table.User, password string) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
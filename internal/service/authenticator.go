@@ -0,0 +1,52 @@
+package service
+
+import (
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// ErrInvalidCredentials is returned by an Authenticator that does not know
+// the user or rejects the password
+var ErrInvalidCredentials error = &DomainError{Code: pke.CodeInvalidCredentials}
+
+// Authenticator checks a username and password against one credential
+// source and returns the matching local user
+type Authenticator interface {
+	// Name identifies the authenticator in logs
+	Name() string
+	// Authenticate returns ErrInvalidCredentials for unknown users or wrong
+	// passwords, and other errors when the source is unavailable
+	Authenticate(username, password string) (*table.User, error)
+}
+
+// PasswordAuthenticator checks passwords against the bcrypt hashes stored in users
+type PasswordAuthenticator struct {
+	userDAO *dao.UserDAO
+}
+
+// NewPasswordAuthenticator creates a new PasswordAuthenticator instance
+func NewPasswordAuthenticator(userDAO *dao.UserDAO) *PasswordAuthenticator {
+	return &PasswordAuthenticator{
+		userDAO: userDAO,
+	}
+}
+
+// Name returns the authenticator name
+func (a *PasswordAuthenticator) Name() string {
+	return "password"
+}
+
+// Authenticate finds the user by username or email and verifies the password
+func (a *PasswordAuthenticator) Authenticate(username, password string) (*table.User, error) {
+	user, err := a.userDAO.FindByUsernameOrEmail(username)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/sanmu2018/word-hero/internal/mailer"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+const (
+	// resendInterval is the minimum time between two verification emails to one user
+	resendInterval = time.Minute
+	// mailTimeout bounds how long a background mail delivery may take
+	mailTimeout = 30 * time.Second
+)
+
+// VerifyEmail consumes a verification token, marks the user's email as
+// verified and returns the user with a new session token
+func (s *AuthService) VerifyEmail(tokenString string) (*table.User, string, error) {
+	claims, err := s.jwtUtils.ValidatePurposeToken(tokenString, table.TokenPurposeEmailVerification)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, "", pke.NewApiError(pke.CodeTokenExpired)
+		}
+		return nil, "", pke.NewApiError(pke.CodeInvalidToken)
+	}
+
+	// The token must be on record and unused
+	record, err := s.userTokenDAO.FindByHash(table.TokenPurposeEmailVerification, utils.HashToken(tokenString))
+	if err != nil || record.ID != claims.ID || record.UserID != claims.UserID {
+		return nil, "", pke.NewApiError(pke.CodeInvalidToken)
+	}
+	consumed, err := s.userTokenDAO.Consume(record.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to consume verification token: %w", err)
+	}
+	if !consumed {
+		return nil, "", pke.NewApiError(pke.CodeInvalidToken)
+	}
+
+	user, err := s.userDAO.FindByID(claims.UserID)
+	if err != nil {
+		return nil, "", pke.NewApiError(pke.CodeUserNotFound)
+	}
+
+	if user.EmailVerifiedAt == nil {
+		verifiedAt := time.Now().UnixMilli()
+		if err := s.userDAO.MarkEmailVerified(user.ID, verifiedAt); err != nil {
+			return nil, "", err
+		}
+		user.EmailVerifiedAt = &verifiedAt
+	}
+
+	token, err := s.jwtUtils.GenerateToken(user.ID, user.Username, user.Email, user.Role, user.SessionVersion)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	log.Info().Str("user_id", user.ID).Str("email", user.Email).Msg("Email verified successfully")
+	return user, token, nil
+}
+
+// ResendVerification sends a new verification email. It reports success even
+// when the address is unknown or already verified, so it cannot be used to
+// discover which emails are registered.
+func (s *AuthService) ResendVerification(email string) error {
+	user, err := s.userDAO.FindByEmail(email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		log.Debug().Str("email", email).Msg("Verification resend skipped")
+		return nil
+	}
+
+	if latest, err := s.userTokenDAO.FindLatest(user.ID, table.TokenPurposeEmailVerification); err == nil {
+		if time.Since(time.UnixMilli(latest.CreatedAt)) < resendInterval {
+			log.Debug().Str("user_id", user.ID).Msg("Verification resend throttled")
+			return nil
+		}
+	}
+
+	// Only the most recent link stays valid
+	if err := s.userTokenDAO.InvalidateAll(user.ID, table.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+	return s.sendVerificationEmail(user)
+}
+
+// sendVerificationEmail issues a verification token and mails a link to the user
+func (s *AuthService) sendVerificationEmail(user *table.User) error {
+	ttl, err := time.ParseDuration(s.authConfig.VerificationTTL)
+	if err != nil {
+		return fmt.Errorf("invalid verification ttl: %w", err)
+	}
+
+	token, tokenID, err := s.jwtUtils.GeneratePurposeToken(user.ID, table.TokenPurposeEmailVerification, ttl)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	if err := s.userTokenDAO.Create(&table.UserToken{
+		ID:        tokenID,
+		UserID:    user.ID,
+		Purpose:   table.TokenPurposeEmailVerification,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl).UnixMilli(),
+	}); err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/api/auth/verify-email?token=" + url.QueryEscape(token)
+	s.sendMail(&mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(mailLanguage(user), "mail.verify_subject"),
+		Body:    i18n.T(mailLanguage(user), "mail.verify_body", displayName(user), ttl, link),
+	})
+	return nil
+}
+
+// sendMail delivers msg in the background so slow mail servers do not block requests
+func (s *AuthService) sendMail(msg *mailer.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+		defer cancel()
+		if err := s.mailSender.Send(ctx, msg); err != nil {
+			log.Error(err).Str("to", msg.To).Str("subject", msg.Subject).Msg("Failed to deliver mail")
+		}
+	}()
+}
+
+// displayName returns the name used to greet a user
+func displayName(user *table.User) string {
+	if user.FullName != "" {
+		return user.FullName
+	}
+	return user.Username
+}
+
+// mailLanguage returns the language of mails to user, who may not have
+// chosen one
+func mailLanguage(user *table.User) string {
+	if user.Language != "" {
+		return user.Language
+	}
+	return i18n.Default
+}
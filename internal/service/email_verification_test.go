@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+func newTestTokenAuthService(t *testing.T) (*AuthService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := daotest.Mock(t)
+	jwtUtils, err := utils.NewJWTUtils(&conf.JWTConfig{Secret: "test-secret", ExpiresIn: "1h"})
+	if err != nil {
+		t.Fatalf("NewJWTUtils() error = %v", err)
+	}
+	return &AuthService{
+		userDAO:      dao.NewUserDAO(),
+		userTokenDAO: dao.NewUserTokenDAO(),
+		jwtUtils:     jwtUtils,
+		loginGuard:   newTestLoginGuard(t),
+		authConfig:   &conf.AuthConfig{},
+	}, mock
+}
+
+// expectTokenRecord returns the stored token with the given id for the user
+func expectTokenRecord(mock sqlmock.Sqlmock, purpose, tokenID string, expiresAt int64) {
+	mock.ExpectQuery(`SELECT \* FROM "user_tokens" WHERE purpose = .* AND token_hash = `).
+		WithArgs(purpose, sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "purpose", "expires_at"}).
+			AddRow(tokenID, "user-1", purpose, expiresAt))
+}
+
+// expectConsume expects the token to be consumed, which only succeeds once
+func expectConsume(mock sqlmock.Sqlmock, tokenID string, consumed bool) {
+	rows := int64(1)
+	if !consumed {
+		rows = 0
+	}
+	mock.ExpectExec(`UPDATE "user_tokens" SET "used_at"=.* WHERE id = .* AND used_at IS NULL AND expires_at > `).
+		WithArgs(sqlmock.AnyArg(), tokenID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, rows))
+}
+
+func TestVerifyEmailTokenIsSingleUse(t *testing.T) {
+	s, mock := newTestTokenAuthService(t)
+	token, tokenID, err := s.jwtUtils.GeneratePurposeToken("user-1", table.TokenPurposeEmailVerification, time.Hour)
+	if err != nil {
+		t.Fatalf("GeneratePurposeToken() error = %v", err)
+	}
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+
+	expectTokenRecord(mock, table.TokenPurposeEmailVerification, tokenID, expiresAt)
+	expectConsume(mock, tokenID, true)
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).WithArgs("user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "role", "is_active"}).
+			AddRow("user-1", "alice", "alice@example.com", "user", true))
+	mock.ExpectExec(`UPDATE "users" SET "email_verified_at"=.* WHERE id = `).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	user, session, err := s.VerifyEmail(token)
+	if err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	if user.EmailVerifiedAt == nil || session == "" {
+		t.Fatalf("VerifyEmail() = %+v, %q, want a verified user and a session", user, session)
+	}
+
+	expectTokenRecord(mock, table.TokenPurposeEmailVerification, tokenID, expiresAt)
+	expectConsume(mock, tokenID, false)
+	if _, _, err := s.VerifyEmail(token); errorNo(err) != pke.CodeInvalidToken {
+		t.Errorf("second VerifyEmail() error = %v, want CodeInvalidToken", err)
+	}
+}
+
+func TestVerifyEmailRejectsExpiredAndForeignTokens(t *testing.T) {
+	s, _ := newTestTokenAuthService(t)
+
+	expired, _, err := s.jwtUtils.GeneratePurposeToken("user-1", table.TokenPurposeEmailVerification, -time.Minute)
+	if err != nil {
+		t.Fatalf("GeneratePurposeToken() error = %v", err)
+	}
+	if _, _, err := s.VerifyEmail(expired); errorNo(err) != pke.CodeTokenExpired {
+		t.Errorf("VerifyEmail(expired) error = %v, want CodeTokenExpired", err)
+	}
+
+	// A token issued for another purpose is not a verification token
+	other, _, err := s.jwtUtils.GeneratePurposeToken("user-1", table.TokenPurposeLogin2FA, time.Hour)
+	if err != nil {
+		t.Fatalf("GeneratePurposeToken() error = %v", err)
+	}
+	if _, _, err := s.VerifyEmail(other); errorNo(err) != pke.CodeInvalidToken {
+		t.Errorf("VerifyEmail(other purpose) error = %v, want CodeInvalidToken", err)
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// DomainError is an expected service failure the client can act on, such as
+// a missing record or a taken username. It carries the pke code it is
+// reported with; the router derives the HTTP status from the code.
+type DomainError struct {
+	Code int
+	// Detail is an optional explanation appended to the code's message,
+	// either a key of the message catalog or literal text
+	Detail string
+}
+
+func (e *DomainError) Error() string {
+	if e.Detail == "" {
+		return pke.GetErrorMessage(e.Code)
+	}
+	return pke.GetErrorMessage(e.Code) + ": " + i18n.T(i18n.Default, e.Detail)
+}
+
+// ErrorNo returns the pke code of the error
+func (e *DomainError) ErrorNo() int {
+	return e.Code
+}
+
+// Domain errors returned by services. Wrap them with %w to add context.
+var (
+	ErrUserNotFound      = &DomainError{Code: pke.CodeUserNotFound}
+	ErrWordNotFound      = &DomainError{Code: pke.CodeWordNotFound}
+	ErrUsernameTaken     = &DomainError{Code: pke.CodeUserAlreadyExists, Detail: "user.username_taken"}
+	ErrEmailTaken        = &DomainError{Code: pke.CodeUserAlreadyExists, Detail: "user.email_taken"}
+	ErrUserDisabled      = &DomainError{Code: pke.CodeUserDisabled}
+	ErrInvalidToken      = &DomainError{Code: pke.CodeInvalidToken}
+	ErrIncorrectPassword = &DomainError{Code: pke.CodeInvalidPassword, Detail: "user.password_incorrect"}
+)
+
+// invalidInput reports input that failed validation; detail is a message key
+func invalidInput(detail string) error {
+	return &DomainError{Code: pke.CodeValidationError, Detail: detail}
+}
+
+// notFound reports err as domainErr when the record does not exist and
+// returns other errors unchanged
+func notFound(domainErr *DomainError, err error) error {
+	if errors.Is(err, dao.ErrNotFound) {
+		return fmt.Errorf("%w: %w", domainErr, err)
+	}
+	return err
+}
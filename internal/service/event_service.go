@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pubsub"
+)
+
+const (
+	// eventPublishTimeout bounds publishing an event, which runs in the
+	// request that made the change
+	eventPublishTimeout = 2 * time.Second
+	// eventRetryMin and eventRetryMax bound the backoff between attempts to
+	// receive events again after the broker stopped delivering them
+	eventRetryMin = time.Second
+	eventRetryMax = time.Minute
+)
+
+// EventService pushes changes of a user's data to all of the user's
+// connected clients, on every instance sharing the broker
+type EventService struct {
+	hub *pubsub.Hub
+
+	retryMin time.Duration
+	retryMax time.Duration
+}
+
+// NewEventService creates a new EventService instance
+func NewEventService(broker pubsub.Broker, bufferSize int) *EventService {
+	log.Info().Msg("Creating event service")
+
+	return &EventService{
+		hub:      pubsub.NewHub(broker, bufferSize),
+		retryMin: eventRetryMin,
+		retryMax: eventRetryMax,
+	}
+}
+
+// Start receives the events published by all instances in the background
+func (s *EventService) Start() {
+	go s.run(context.Background())
+}
+
+// run receives events until ctx is done. Whenever the broker stops, for
+// example because the Redis subscription failed, it subscribes again with
+// exponential backoff; events published in between are lost.
+func (s *EventService) run(ctx context.Context) {
+	backoff := s.retryMin
+	for {
+		started := time.Now()
+		err := s.hub.Run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A subscription that held for a while starts the backoff over
+		if time.Since(started) > s.retryMax {
+			backoff = s.retryMin
+		}
+		log.Warn().Err(err).Dur("retry_in", backoff).Msg("Stopped receiving events")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.retryMax)
+	}
+}
+
+// Publish sends an event to the clients of userID. Failures are only logged:
+// the change itself has been made, and clients catch up when they reconnect.
+func (s *EventService) Publish(userID, eventType string, data interface{}) {
+	payload, err := json.Marshal(&dto.Event{
+		Type:      eventType,
+		Data:      data,
+		Timestamp: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Error(err).Str("type", eventType).Msg("Failed to encode event")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), eventPublishTimeout)
+	defer cancel()
+	if err := s.hub.Publish(ctx, userTopic(userID), payload); err != nil {
+		log.Error(err).Str("user_id", userID).Str("type", eventType).Msg("Failed to publish event")
+	}
+}
+
+// Subscribe subscribes to the events of userID. Each message is an encoded
+// dto.Event.
+func (s *EventService) Subscribe(userID string) *pubsub.Subscription {
+	return s.hub.Subscribe(userTopic(userID))
+}
+
+// userTopic returns the topic of the events of userID
+func userTopic(userID string) string {
+	return "user:" + userID
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sanmu2018/word-hero/pkg/pubsub"
+)
+
+// flakyBroker fails to receive a number of times before receiving normally,
+// like a Redis broker whose subscription is lost
+type flakyBroker struct {
+	*pubsub.MemoryBroker
+	failures atomic.Int32
+}
+
+func (b *flakyBroker) Receive(ctx context.Context, deliver func(topic string, payload []byte)) error {
+	if b.failures.Add(-1) >= 0 {
+		return errors.New("subscription lost")
+	}
+	return b.MemoryBroker.Receive(ctx, deliver)
+}
+
+func TestEventServiceResubscribesAfterBrokerStops(t *testing.T) {
+	broker := &flakyBroker{MemoryBroker: pubsub.NewMemoryBroker()}
+	broker.failures.Store(3)
+	s := NewEventService(broker, 8)
+	s.retryMin, s.retryMax = time.Millisecond, 4*time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.run(ctx)
+
+	sub := s.Subscribe("user-1")
+	defer sub.Close()
+
+	// Events published before the hub receives again are lost, so keep
+	// publishing until one arrives
+	deadline := time.After(2 * time.Second)
+	for {
+		s.Publish("user-1", "progress", nil)
+		select {
+		case <-sub.Messages():
+			if broker.failures.Load() >= 0 {
+				t.Fatalf("received an event before the broker recovered")
+			}
+			return
+		case <-deadline:
+			t.Fatal("Timed out waiting for events to be received again")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
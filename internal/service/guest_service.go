@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+const (
+	// GuestCookieName is the cookie that carries the signed guest token
+	GuestCookieName = "wh_guest"
+	// guestTokenPurpose is the JWT purpose of guest tokens, so they can never
+	// be used as a user session
+	guestTokenPurpose = "guest"
+)
+
+// GuestService lets anonymous visitors keep progress under a guest identity
+// and hands it over to their account when they register or log in
+type GuestService struct {
+	guestDAO   *dao.GuestDAO
+	wordTagDAO *dao.WordTagDAO
+	jwtUtils   *utils.JWTUtils
+	ttl        time.Duration
+}
+
+// NewGuestService creates a new GuestService instance
+func NewGuestService(guestDAO *dao.GuestDAO, wordTagDAO *dao.WordTagDAO, jwtUtils *utils.JWTUtils, config *conf.AuthConfig) (*GuestService, error) {
+	ttl, err := time.ParseDuration(config.GuestTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid guest ttl: %w", err)
+	}
+
+	return &GuestService{
+		guestDAO:   guestDAO,
+		wordTagDAO: wordTagDAO,
+		jwtUtils:   jwtUtils,
+		ttl:        ttl,
+	}, nil
+}
+
+// TTL returns how long a guest token is valid
+func (s *GuestService) TTL() time.Duration {
+	return s.ttl
+}
+
+// Start returns a fresh token for the guest identified by existingToken, or
+// creates a new guest when the token is missing or no longer valid
+func (s *GuestService) Start(existingToken string) (string, string, error) {
+	guestID, err := s.Authenticate(existingToken)
+	if err != nil {
+		guest := &table.Guest{}
+		if err := s.guestDAO.Create(guest); err != nil {
+			return "", "", err
+		}
+		guestID = guest.ID
+		log.Info().Str("guest_id", guestID).Msg("Guest session started")
+	}
+
+	token, _, err := s.jwtUtils.GeneratePurposeToken(guestID, guestTokenPurpose, s.ttl)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate guest token: %w", err)
+	}
+	return guestID, token, nil
+}
+
+// Authenticate returns the guest ID of a valid guest token
+func (s *GuestService) Authenticate(token string) (string, error) {
+	if token == "" {
+		return "", fmt.Errorf("missing guest token")
+	}
+	claims, err := s.jwtUtils.ValidatePurposeToken(token, guestTokenPurpose)
+	if err != nil {
+		return "", err
+	}
+	guest, err := s.guestDAO.FindByID(claims.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.guestDAO.Touch(guest.ID); err != nil {
+		log.Warn().Err(err).Str("guest_id", guest.ID).Msg("Failed to record guest activity")
+	}
+	return guest.ID, nil
+}
+
+// MergeInto moves the guest's progress to a user and removes the guest.
+// On conflicting words the earliest known timestamp wins.
+func (s *GuestService) MergeInto(token, userID string) (int, error) {
+	guestID, err := s.Authenticate(token)
+	if err != nil {
+		return 0, err
+	}
+
+	merged, err := s.wordTagDAO.MergeInto(guestID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.guestDAO.Delete(guestID); err != nil {
+		return 0, err
+	}
+
+	log.Info().Str("guest_id", guestID).Str("user_id", userID).Int("word_tags", merged).Msg("Guest progress merged into account")
+	return merged, nil
+}
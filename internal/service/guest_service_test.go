@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/utils"
+)
+
+const guestID = "9d7e6f5a-4b3c-4d2e-8f1a-0b9c8d7e6f5a"
+
+func newTestGuestService(t *testing.T) (*GuestService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := daotest.Mock(t)
+	jwtUtils, err := utils.NewJWTUtils(&conf.JWTConfig{Secret: "test-secret", ExpiresIn: "1h"})
+	if err != nil {
+		t.Fatalf("NewJWTUtils() error = %v", err)
+	}
+	s, err := NewGuestService(dao.NewGuestDAO(), dao.NewWordTagDAO(), jwtUtils, &conf.AuthConfig{GuestTTL: "720h"})
+	if err != nil {
+		t.Fatalf("NewGuestService() error = %v", err)
+	}
+	return s, mock
+}
+
+func TestMergeGuestKeepsEarliestKnownOnConflict(t *testing.T) {
+	s, mock := newTestGuestService(t)
+	token, _, err := s.jwtUtils.GeneratePurposeToken(guestID, guestTokenPurpose, time.Hour)
+	if err != nil {
+		t.Fatalf("GeneratePurposeToken() error = %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "guests" WHERE id = `).WithArgs(guestID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(guestID))
+	mock.ExpectExec(`UPDATE "guests" SET "last_seen_at"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	// Words both sides marked keep the earlier known time; a NULL loses to a mark
+	mock.ExpectExec(`UPDATE word_tags AS u SET known = LEAST\(u.known, g.known\)`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), guestID, syncUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM word_tags AS g\s+WHERE g.user_id = .* AND EXISTS`).
+		WithArgs(guestID, syncUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "word_tags" SET "user_id"=.* WHERE user_id = `).
+		WithArgs(syncUserID, sqlmock.AnyArg(), guestID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectExec(`DELETE FROM "guests" WHERE id = `).WithArgs(guestID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	merged, err := s.MergeInto(token, syncUserID)
+	if err != nil {
+		t.Fatalf("MergeInto() error = %v", err)
+	}
+	if merged != 2 {
+		t.Errorf("MergeInto() = %d, want the 2 words only the guest had", merged)
+	}
+}
+
+func TestMergeGuestRejectsSessionToken(t *testing.T) {
+	s, _ := newTestGuestService(t)
+
+	// A user session is not a guest token, so nothing is merged
+	session, err := s.jwtUtils.GenerateToken(guestID, "alice", "alice@example.com", "user", 0)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	if _, err := s.MergeInto(session, syncUserID); err == nil {
+		t.Error("MergeInto(session token) succeeded, want an error")
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+// ldapProvider is the provider name of identities linked through LDAP
+const ldapProvider = "ldap"
+
+// LDAPAuthenticator checks passwords by binding to an LDAP directory as the
+// user. Users are created locally on their first login and their role is kept
+// in sync with their directory groups.
+type LDAPAuthenticator struct {
+	config      *conf.LDAPConfig
+	directory   ldapDirectory
+	authService *AuthService
+	userDAO     *dao.UserDAO
+	identityDAO *dao.UserIdentityDAO
+	settingDAO  *dao.SettingDAO
+}
+
+// ldapDirectory reads a user's entry after checking their password
+type ldapDirectory interface {
+	lookup(username, password string) (*ldapEntry, error)
+}
+
+// ldapClient is the ldapDirectory backed by an LDAP server
+type ldapClient struct {
+	config  *conf.LDAPConfig
+	timeout time.Duration
+}
+
+// ldapEntry holds the directory attributes used to provision a user
+type ldapEntry struct {
+	dn       string
+	email    string
+	fullName string
+	groups   []string
+}
+
+// NewLDAPAuthenticator creates a new LDAPAuthenticator instance
+func NewLDAPAuthenticator(config *conf.LDAPConfig, authService *AuthService, userDAO *dao.UserDAO, identityDAO *dao.UserIdentityDAO, settingDAO *dao.SettingDAO) (*LDAPAuthenticator, error) {
+	if config.URL == "" || !strings.Contains(config.BindDNTemplate, "%s") {
+		return nil, fmt.Errorf("ldap requires url and a bind_dn_template containing %%s")
+	}
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap timeout: %w", err)
+	}
+
+	return &LDAPAuthenticator{
+		config:      config,
+		directory:   &ldapClient{config: config, timeout: timeout},
+		authService: authService,
+		userDAO:     userDAO,
+		identityDAO: identityDAO,
+		settingDAO:  settingDAO,
+	}, nil
+}
+
+// Name returns the authenticator name
+func (a *LDAPAuthenticator) Name() string {
+	return ldapProvider
+}
+
+// Authenticate binds as the user and returns the linked local user,
+// provisioning it on first login
+func (a *LDAPAuthenticator) Authenticate(username, password string) (*table.User, error) {
+	// An empty password would be an unauthenticated bind, which most servers accept
+	if username == "" || password == "" || strings.Contains(username, "@") {
+		return nil, ErrInvalidCredentials
+	}
+
+	entry, err := a.directory.lookup(username, password)
+	if err != nil {
+		return nil, err
+	}
+	role := a.roleFor(entry.groups)
+
+	if identity, err := a.identityDAO.FindByProviderSubject(ldapProvider, entry.dn); err == nil {
+		user, err := a.userDAO.FindByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.identityDAO.UpdateLastLogin(identity.ID); err != nil {
+			log.Warn().Err(err).Str("identity_id", identity.ID).Msg("Failed to update identity last login")
+		}
+		return user, a.syncRole(user, role, identity.Provisioned)
+	}
+
+	user, err := a.linkableUser(entry)
+	if err != nil {
+		return nil, err
+	}
+	provisioned := user == nil
+	if provisioned {
+		// The directory address goes to the new user unless a local account has it
+		email := entry.email
+		if exists, err := a.userDAO.ExistsByEmail(email); err != nil {
+			return nil, err
+		} else if exists {
+			email = ""
+		}
+		user, err = a.authService.provisionExternalUser(username, email, entry.fullName, role, email != "")
+		if err != nil {
+			return nil, err
+		}
+	} else if err := a.syncRole(user, role, false); err != nil {
+		return nil, err
+	}
+	if err := a.identityDAO.Create(&table.UserIdentity{
+		UserID:      user.ID,
+		Provider:    ldapProvider,
+		Subject:     entry.dn,
+		Email:       entry.email,
+		Provisioned: provisioned,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkableUser returns the local account a first LDAP login may take over:
+// the one with the directory address, when that address is verified and
+// administrators have enabled linking. It returns nil when there is none.
+func (a *LDAPAuthenticator) linkableUser(entry *ldapEntry) (*table.User, error) {
+	if entry.email == "" {
+		return nil, nil
+	}
+	user, err := a.userDAO.FindByEmail(entry.email)
+	if errors.Is(err, dao.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := a.settingDAO.GetBool(table.SettingLDAPLinkByEmail, false)
+	if err != nil {
+		log.Error(err).Msg("Failed to load LDAP linking setting")
+		enabled = false
+	}
+	if !enabled || user.EmailVerifiedAt == nil {
+		log.Warn().Str("dn", entry.dn).Str("user_id", user.ID).Bool("linking_enabled", enabled).
+			Msg("Not linking LDAP identity to local account with the same email")
+		return nil, nil
+	}
+	return user, nil
+}
+
+// syncRole updates the user's role to the one derived from their groups. The
+// directory may raise the role of an account it did not provision, but never
+// lowers a role that was assigned locally.
+func (a *LDAPAuthenticator) syncRole(user *table.User, role string, managed bool) error {
+	if user.Role == role {
+		return nil
+	}
+	if !managed && role != "admin" {
+		return nil
+	}
+	if err := a.userDAO.UpdateRole(user.ID, role); err != nil {
+		return err
+	}
+	user.Role = role
+	return nil
+}
+
+// lookup binds as the user and reads their attributes and groups
+func (c *ldapClient) lookup(username, password string) (*ldapEntry, error) {
+	conn, err := ldap.DialURL(c.config.URL, ldap.DialWithDialer(&net.Dialer{Timeout: c.timeout}),
+		ldap.DialWithTLSConfig(&tls.Config{InsecureSkipVerify: c.config.InsecureSkipVerify}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ldap: %w", err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(c.timeout)
+
+	if c.config.StartTLS {
+		if err := conn.StartTLS(&tls.Config{InsecureSkipVerify: c.config.InsecureSkipVerify}); err != nil {
+			return nil, fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	dn := fmt.Sprintf(c.config.BindDNTemplate, ldap.EscapeDN(username))
+	if err := conn.Bind(dn, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ldap bind failed: %w", err)
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, 0, false,
+		"(objectClass=*)",
+		[]string{c.config.EmailAttribute, c.config.NameAttribute, "memberOf"},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ldap entry: %w", err)
+	}
+	if len(result.Entries) == 0 {
+		return nil, fmt.Errorf("ldap entry not found: %s", dn)
+	}
+	record := result.Entries[0]
+
+	entry := &ldapEntry{
+		dn:       record.DN,
+		email:    record.GetAttributeValue(c.config.EmailAttribute),
+		fullName: record.GetAttributeValue(c.config.NameAttribute),
+		groups:   record.GetAttributeValues("memberOf"),
+	}
+
+	if c.config.GroupBaseDN != "" {
+		groups, err := conn.Search(ldap.NewSearchRequest(
+			c.config.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+			fmt.Sprintf(c.config.GroupFilter, ldap.EscapeFilter(record.DN)),
+			[]string{"cn"},
+			nil,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("failed to search ldap groups: %w", err)
+		}
+		entry.groups = entry.groups[:0]
+		for _, group := range groups.Entries {
+			entry.groups = append(entry.groups, group.DN)
+		}
+	}
+
+	return entry, nil
+}
+
+// roleFor maps group memberships to a role. A group may be configured by its
+// full DN or by its cn; admin takes precedence when several groups match.
+func (a *LDAPAuthenticator) roleFor(groups []string) string {
+	role := ""
+	for _, group := range groups {
+		mapped, ok := a.groupRole(group)
+		if !ok {
+			continue
+		}
+		if mapped == "admin" {
+			return mapped
+		}
+		if role == "" {
+			role = mapped
+		}
+	}
+	if role == "" {
+		role = a.config.DefaultRole
+	}
+	return role
+}
+
+// groupRole looks up the role configured for a group DN
+func (a *LDAPAuthenticator) groupRole(groupDN string) (string, bool) {
+	cn := ""
+	if parsed, err := ldap.ParseDN(groupDN); err == nil && len(parsed.RDNs) > 0 {
+		for _, attr := range parsed.RDNs[0].Attributes {
+			if strings.EqualFold(attr.Type, "cn") {
+				cn = attr.Value
+			}
+		}
+	}
+
+	for key, role := range a.config.GroupRoles {
+		if strings.EqualFold(key, groupDN) || (cn != "" && strings.EqualFold(key, cn)) {
+			return role, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/utils"
+)
+
+// fakeDirectory is an in-memory LDAP directory keyed by username
+type fakeDirectory struct {
+	passwords map[string]string
+	entries   map[string]*ldapEntry
+}
+
+func (d *fakeDirectory) lookup(username, password string) (*ldapEntry, error) {
+	if d.passwords[username] != password {
+		return nil, ErrInvalidCredentials
+	}
+	return d.entries[username], nil
+}
+
+var userColumns = []string{"id", "username", "email", "role", "is_active", "email_verified_at"}
+
+func newTestLDAPAuthenticator(t *testing.T, groups ...string) (*LDAPAuthenticator, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := daotest.Mock(t)
+	userDAO := dao.NewUserDAO()
+
+	authenticator := &LDAPAuthenticator{
+		config: &conf.LDAPConfig{
+			GroupRoles:  map[string]string{"teachers": "admin"},
+			DefaultRole: "user",
+		},
+		directory: &fakeDirectory{
+			passwords: map[string]string{"alice": "secret"},
+			entries: map[string]*ldapEntry{"alice": {
+				dn:       "uid=alice,ou=people,dc=school",
+				email:    "alice@school.example",
+				fullName: "Alice",
+				groups:   groups,
+			}},
+		},
+		authService: &AuthService{userDAO: userDAO},
+		userDAO:     userDAO,
+		identityDAO: dao.NewUserIdentityDAO(),
+		settingDAO:  dao.NewSettingDAO(),
+	}
+	return authenticator, mock
+}
+
+func expectNoIdentity(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT \* FROM "user_identities" WHERE provider = .* AND subject = `).
+		WithArgs("ldap", "uid=alice,ou=people,dc=school", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+func expectLocalUser(mock sqlmock.Sqlmock, role string, verified bool) {
+	rows := sqlmock.NewRows(userColumns)
+	if verified {
+		rows.AddRow("local-1", "alice", "alice@school.example", role, true, 1700000000000)
+	} else {
+		rows.AddRow("local-1", "alice", "alice@school.example", role, true, nil)
+	}
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE email = `).
+		WithArgs("alice@school.example", 1).
+		WillReturnRows(rows)
+}
+
+func expectLinkSetting(mock sqlmock.Sqlmock, enabled bool) {
+	rows := sqlmock.NewRows([]string{"key", "value"})
+	if enabled {
+		rows.AddRow("security.ldap_link_by_email", "true")
+	}
+	mock.ExpectQuery(`SELECT \* FROM "app_settings" WHERE key = `).WillReturnRows(rows)
+}
+
+// expectProvision expects a new user and an identity recording that it was
+// provisioned. Tests check the email the user was created with on the
+// returned user.
+func expectProvision(mock sqlmock.Sqlmock, emailTaken bool) {
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "users" WHERE email = `).
+		WithArgs("alice@school.example").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(map[bool]int{true: 1}[emailTaken]))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "users" WHERE username = `).
+		WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO "users" .*`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("new-1"))
+	mock.ExpectQuery(`INSERT INTO "user_identities" .*`).
+		WithArgs("new-1", "ldap", "uid=alice,ou=people,dc=school", "alice@school.example", true, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("identity-1"))
+}
+
+func TestLDAPAuthenticatorRejectsWrongPassword(t *testing.T) {
+	authenticator, _ := newTestLDAPAuthenticator(t)
+
+	for _, password := range []string{"wrong", ""} {
+		if _, err := authenticator.Authenticate("alice", password); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Authenticate(alice, %q) error = %v, want ErrInvalidCredentials", password, err)
+		}
+	}
+	if _, err := authenticator.Authenticate("alice@school.example", "secret"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate with an email error = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestLDAPAuthenticatorProvisionsNewUser(t *testing.T) {
+	authenticator, mock := newTestLDAPAuthenticator(t)
+	expectNoIdentity(mock)
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE email = `).WillReturnRows(sqlmock.NewRows(userColumns))
+	expectProvision(mock, false)
+
+	user, err := authenticator.Authenticate("alice", "secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if user.Email != "alice@school.example" || user.EmailVerifiedAt == nil || user.Role != "user" {
+		t.Errorf("provisioned user = %+v, want verified directory email and role user", user)
+	}
+}
+
+func TestLDAPAuthenticatorDoesNotLinkWhenDisabled(t *testing.T) {
+	authenticator, mock := newTestLDAPAuthenticator(t)
+	expectNoIdentity(mock)
+	expectLocalUser(mock, "admin", true)
+	expectLinkSetting(mock, false)
+	expectProvision(mock, true)
+
+	user, err := authenticator.Authenticate("alice", "secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if user.ID == "local-1" || user.Email != "" || user.EmailVerifiedAt != nil {
+		t.Errorf("user = %+v, want a separate user without the taken email", user)
+	}
+}
+
+func TestLDAPAuthenticatorDoesNotLinkUnverifiedEmail(t *testing.T) {
+	authenticator, mock := newTestLDAPAuthenticator(t)
+	expectNoIdentity(mock)
+	expectLocalUser(mock, "user", false)
+	expectLinkSetting(mock, true)
+	expectProvision(mock, true)
+
+	user, err := authenticator.Authenticate("alice", "secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if user.ID == "local-1" || user.Email != "" {
+		t.Errorf("user = %+v, want a separate user without the taken email", user)
+	}
+}
+
+func TestLDAPAuthenticatorLinksVerifiedEmailWithoutDowngrade(t *testing.T) {
+	authenticator, mock := newTestLDAPAuthenticator(t)
+	expectNoIdentity(mock)
+	expectLocalUser(mock, "admin", true)
+	expectLinkSetting(mock, true)
+	mock.ExpectQuery(`INSERT INTO "user_identities" .*`).
+		WithArgs("local-1", "ldap", "uid=alice,ou=people,dc=school", "alice@school.example", false, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("identity-1"))
+
+	user, err := authenticator.Authenticate("alice", "secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if user.ID != "local-1" || user.Role != "admin" {
+		t.Errorf("user = %+v, want the linked local admin", user)
+	}
+}
+
+func TestLDAPAuthenticatorLinkedAccountKeepsLocalRole(t *testing.T) {
+	authenticator, mock := newTestLDAPAuthenticator(t)
+	mock.ExpectQuery(`SELECT \* FROM "user_identities"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "subject", "provisioned"}).
+			AddRow("identity-1", "local-1", "ldap", "uid=alice,ou=people,dc=school", false))
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow("local-1", "alice", "alice@school.example", "admin", true, 1700000000000))
+	mock.ExpectExec(`UPDATE "user_identities" SET "last_login_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	user, err := authenticator.Authenticate("alice", "secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if user.Role != "admin" {
+		t.Errorf("role = %q, want the locally assigned admin role", user.Role)
+	}
+}
+
+func TestLDAPAuthenticatorSyncsProvisionedRole(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		groups   []string
+		current  string
+		wantRole string
+	}{
+		{"promoted", []string{"cn=teachers,ou=groups,dc=school"}, "user", "admin"},
+		{"demoted", nil, "admin", "user"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			authenticator, mock := newTestLDAPAuthenticator(t, test.groups...)
+			mock.ExpectQuery(`SELECT \* FROM "user_identities"`).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "subject", "provisioned"}).
+					AddRow("identity-1", "new-1", "ldap", "uid=alice,ou=people,dc=school", true))
+			mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).
+				WillReturnRows(sqlmock.NewRows(userColumns).AddRow("new-1", "alice", "alice@school.example", test.current, true, 1700000000000))
+			mock.ExpectExec(`UPDATE "user_identities" SET "last_login_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`UPDATE "users" SET "role"=.*,"updated_at"=.* WHERE id = .* AND "users"."deleted_at" IS NULL`).
+				WithArgs(test.wantRole, sqlmock.AnyArg(), "new-1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			user, err := authenticator.Authenticate("alice", "secret")
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if user.Role != test.wantRole {
+				t.Errorf("role = %q, want %q", user.Role, test.wantRole)
+			}
+		})
+	}
+}
+
+func TestLoginAcceptsDirectoryUserWithoutEmail(t *testing.T) {
+	authenticator, mock := newTestLDAPAuthenticator(t)
+	jwtUtils, err := utils.NewJWTUtils(&conf.JWTConfig{Secret: "test-secret", ExpiresIn: "1h"})
+	if err != nil {
+		t.Fatalf("NewJWTUtils() error = %v", err)
+	}
+	s := authenticator.authService
+	s.jwtUtils = jwtUtils
+	s.settingDAO = authenticator.settingDAO
+	s.loginGuard = newTestLoginGuard(t)
+	s.authConfig = &conf.AuthConfig{RequireEmailVerification: true}
+	s.SetAuthenticators(authenticator)
+
+	// The directory address belongs to a local account that may not be linked
+	expectNoIdentity(mock)
+	expectLocalUser(mock, "user", true)
+	expectLinkSetting(mock, false)
+	expectProvision(mock, true)
+
+	result, err := s.Login(&dto.UserLoginRequest{Username: "alice", Password: "secret", ClientIP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("Login() error = %v, want a session for the directory user", err)
+	}
+	if result.Token == "" || result.User.ID != "new-1" || result.User.Email != "" {
+		t.Errorf("Login() = %+v, want a session for the provisioned user", result)
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+	"github.com/sanmu2018/word-hero/pkg/ratelimit"
+)
+
+// LoginGuard throttles login attempts per client IP and per username, and
+// locks accounts out progressively after repeated failed attempts
+type LoginGuard struct {
+	store     ratelimit.Store
+	ipLimit   ratelimit.Limit
+	userLimit ratelimit.Limit
+	lockout   *ratelimit.Lockout
+}
+
+// NewLoginGuard creates a new LoginGuard instance
+func NewLoginGuard(store ratelimit.Store, config *conf.LoginLimitConfig) (*LoginGuard, error) {
+	window, err := time.ParseDuration(config.FailureWindow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid failure window: %w", err)
+	}
+	base, err := time.ParseDuration(config.LockoutBase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockout base duration: %w", err)
+	}
+	max, err := time.ParseDuration(config.LockoutMax)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockout max duration: %w", err)
+	}
+
+	return &LoginGuard{
+		store:     store,
+		ipLimit:   ratelimit.PerMinute(config.IPPerMinute, config.IPBurst),
+		userLimit: ratelimit.PerMinute(config.UsernamePerMinute, config.UsernameBurst),
+		lockout: ratelimit.NewLockout(store, ratelimit.LockoutPolicy{
+			MaxFailures:  config.MaxFailures,
+			Window:       window,
+			BaseDuration: base,
+			MaxDuration:  max,
+		}),
+	}, nil
+}
+
+// Allow checks whether a login attempt may proceed
+func (g *LoginGuard) Allow(ip, username string) error {
+	ctx := context.Background()
+	username = normalizeLoginName(username)
+
+	if err := g.checkLockout(ctx, username); err != nil {
+		return err
+	}
+	if err := g.takeIP(ctx, ip); err != nil {
+		return err
+	}
+	return g.takeUser(ctx, username)
+}
+
+// AllowIP checks only the client IP, for logins that must be throttled
+// before the username is known
+func (g *LoginGuard) AllowIP(ip string) error {
+	return g.takeIP(context.Background(), ip)
+}
+
+// AllowUser checks the lockout and throttling of a username whose client IP
+// has already been checked with AllowIP
+func (g *LoginGuard) AllowUser(username string) error {
+	ctx := context.Background()
+	username = normalizeLoginName(username)
+
+	if err := g.checkLockout(ctx, username); err != nil {
+		return err
+	}
+	return g.takeUser(ctx, username)
+}
+
+// Fail records a failed login attempt
+func (g *LoginGuard) Fail(ip, username string) error {
+	username = normalizeLoginName(username)
+
+	locked, err := g.lockout.Fail(context.Background(), username)
+	if err != nil {
+		log.Error(err).Str("username", username).Msg("Failed to record login failure")
+		return nil
+	}
+	if locked > 0 {
+		log.Warn().Str("username", username).Str("ip", ip).Dur("lockout", locked).Msg("Account locked after repeated login failures")
+		return pke.NewRetryAfterError(pke.CodeAccountLocked, locked)
+	}
+	return nil
+}
+
+// Succeed clears the failure history after a successful login
+func (g *LoginGuard) Succeed(username string) {
+	username = normalizeLoginName(username)
+	if err := g.lockout.Reset(context.Background(), username); err != nil {
+		log.Error(err).Str("username", username).Msg("Failed to reset login failures")
+	}
+}
+
+// checkLockout rejects usernames locked out after repeated failures
+func (g *LoginGuard) checkLockout(ctx context.Context, username string) error {
+	if locked, err := g.lockout.Check(ctx, username); err != nil {
+		log.Error(err).Str("username", username).Msg("Failed to check account lockout")
+	} else if locked > 0 {
+		log.Warn().Str("username", username).Dur("retry_after", locked).Msg("Login rejected, account locked")
+		return pke.NewRetryAfterError(pke.CodeAccountLocked, locked)
+	}
+	return nil
+}
+
+func (g *LoginGuard) takeIP(ctx context.Context, ip string) error {
+	if err := g.take(ctx, "login:ip:"+ip, g.ipLimit); err != nil {
+		log.Warn().Str("ip", ip).Msg("Login rate limit exceeded for IP")
+		return err
+	}
+	return nil
+}
+
+func (g *LoginGuard) takeUser(ctx context.Context, username string) error {
+	if err := g.take(ctx, "login:user:"+username, g.userLimit); err != nil {
+		log.Warn().Str("username", username).Msg("Login rate limit exceeded for username")
+		return err
+	}
+	return nil
+}
+
+// take consumes a token, failing open if the backend is unavailable
+func (g *LoginGuard) take(ctx context.Context, key string, limit ratelimit.Limit) error {
+	result, err := g.store.Take(ctx, key, limit)
+	if err != nil {
+		log.Error(err).Str("key", key).Msg("Rate limiter unavailable")
+		return nil
+	}
+	if !result.Allowed {
+		return pke.NewRetryAfterError(pke.CodeTooManyRequests, result.RetryAfter)
+	}
+	return nil
+}
+
+// normalizeLoginName makes "Alice" and "alice" share the same counters
+func normalizeLoginName(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// oidcHTTPTimeout bounds each request to an identity provider
+const oidcHTTPTimeout = 10 * time.Second
+
+// OIDCService handles sign-in through external OpenID Connect providers
+type OIDCService struct {
+	authService *AuthService
+	userDAO     *dao.UserDAO
+	identityDAO *dao.UserIdentityDAO
+	providers   map[string]*oidcProvider
+	order       []string
+}
+
+// oidcProvider wraps a configured provider. Discovery happens on first use so
+// the server still starts while an identity provider is unreachable.
+type oidcProvider struct {
+	config   conf.OIDCProviderConfig
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// oidcClaims are the ID token claims used to find or create a local user
+type oidcClaims struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Nonce             string `json:"nonce"`
+}
+
+// NewOIDCService creates a new OIDCService instance
+func NewOIDCService(configs []conf.OIDCProviderConfig, baseURL string, authService *AuthService, userDAO *dao.UserDAO, identityDAO *dao.UserIdentityDAO) (*OIDCService, error) {
+	s := &OIDCService{
+		authService: authService,
+		userDAO:     userDAO,
+		identityDAO: identityDAO,
+		providers:   make(map[string]*oidcProvider),
+	}
+
+	for _, config := range configs {
+		if config.Name == "" || config.Issuer == "" || config.ClientID == "" {
+			return nil, fmt.Errorf("oidc provider requires name, issuer and client_id")
+		}
+		if _, exists := s.providers[config.Name]; exists {
+			return nil, fmt.Errorf("duplicate oidc provider: %s", config.Name)
+		}
+		if len(config.Scopes) == 0 {
+			config.Scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+		}
+		if config.RedirectURL == "" {
+			config.RedirectURL = strings.TrimRight(baseURL, "/") + "/api/auth/oidc/" + config.Name + "/callback"
+		}
+		if config.DisplayName == "" {
+			config.DisplayName = config.Name
+		}
+
+		s.providers[config.Name] = &oidcProvider{config: config}
+		s.order = append(s.order, config.Name)
+		log.Info().Str("provider", config.Name).Str("issuer", config.Issuer).Msg("OIDC provider configured")
+	}
+
+	return s, nil
+}
+
+// Providers lists the configured providers for the login page
+func (s *OIDCService) Providers() []dto.OIDCProviderInfo {
+	providers := make([]dto.OIDCProviderInfo, 0, len(s.order))
+	for _, name := range s.order {
+		providers = append(providers, dto.OIDCProviderInfo{
+			Name:        name,
+			DisplayName: s.providers[name].config.DisplayName,
+		})
+	}
+	return providers
+}
+
+// BeginLogin returns the provider's authorization URL and the flow state the
+// caller must keep (in a cookie) until the callback
+func (s *OIDCService) BeginLogin(name string) (string, *dto.OIDCFlowState, error) {
+	provider, err := s.provider(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	flow := &dto.OIDCFlowState{
+		Provider: name,
+		State:    utils.GenerateRandomString(32),
+		Nonce:    utils.GenerateRandomString(32),
+		Verifier: oauth2.GenerateVerifier(),
+	}
+	url := provider.oauth2.AuthCodeURL(flow.State,
+		oidc.Nonce(flow.Nonce),
+		oauth2.S256ChallengeOption(flow.Verifier),
+	)
+	return url, flow, nil
+}
+
+// CompleteLogin exchanges the authorization code, verifies the ID token and
+// logs in the linked local user, linking or creating one on first login. The
+// login is subject to the same lockout, throttling and email verification
+// policy as a password login.
+func (s *OIDCService) CompleteLogin(ctx context.Context, name, code, state, clientIP string, flow *dto.OIDCFlowState) (*dto.LoginResult, error) {
+	if flow == nil || flow.Provider != name || flow.State == "" || flow.State != state {
+		return nil, pke.NewApiError(pke.CodeInvalidToken)
+	}
+
+	provider, err := s.provider(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = oidc.ClientContext(ctx, &http.Client{Timeout: oidcHTTPTimeout})
+	token, err := provider.oauth2.Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+	if err != nil {
+		log.Warn().Err(err).Str("provider", name).Msg("OIDC code exchange failed")
+		return nil, pke.NewApiError(pke.CodeAuthFailed)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, pke.NewApiError(pke.CodeAuthFailed)
+	}
+	idToken, err := provider.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		log.Warn().Err(err).Str("provider", name).Msg("OIDC ID token verification failed")
+		return nil, pke.NewApiError(pke.CodeAuthFailed)
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse id token claims: %w", err)
+	}
+	if claims.Nonce != flow.Nonce {
+		return nil, pke.NewApiError(pke.CodeAuthFailed)
+	}
+
+	// Throttle by IP before a first login links or creates anything
+	if err := s.authService.loginGuard.AllowIP(clientIP); err != nil {
+		return nil, err
+	}
+	user, err := s.resolveUser(name, &claims)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, pke.NewApiError(pke.CodeUserDisabled)
+	}
+	if err := s.authService.loginGuard.AllowUser(user.Username); err != nil {
+		return nil, err
+	}
+	if err := s.authService.checkEmailVerified(user); err != nil {
+		return nil, err
+	}
+
+	// The provider replaces the password, not the second factor
+	if user.TOTPEnabledAt != nil {
+		challenge, err := s.authService.issueLoginChallenge(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue login challenge: %w", err)
+		}
+		return &dto.LoginResult{User: user, ChallengeToken: challenge}, nil
+	}
+	s.authService.loginGuard.Succeed(user.Username)
+	return s.authService.completeLogin(user)
+}
+
+// resolveUser finds the user linked to the identity. On first login the
+// identity is linked to the user with the same email when both the provider
+// and the local account have verified it, or a new user is created.
+func (s *OIDCService) resolveUser(provider string, claims *oidcClaims) (*table.User, error) {
+	if identity, err := s.identityDAO.FindByProviderSubject(provider, claims.Subject); err == nil {
+		if err := s.identityDAO.UpdateLastLogin(identity.ID); err != nil {
+			log.Warn().Err(err).Str("identity_id", identity.ID).Msg("Failed to update identity last login")
+		}
+		return s.userDAO.FindByID(identity.UserID)
+	}
+
+	if claims.Email == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidCredentials)
+	}
+
+	user, err := s.userDAO.FindByEmail(claims.Email)
+	provisioned := errors.Is(err, dao.ErrNotFound)
+	if provisioned {
+		// An address the provider has not verified is not given to the new user
+		email := claims.Email
+		if !claims.EmailVerified {
+			email = ""
+		}
+		user, err = s.authService.provisionExternalUser(claims.PreferredUsername, email, claims.Name, "user", claims.EmailVerified)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if !claims.EmailVerified || user.EmailVerifiedAt == nil {
+		// Only an address verified on both sides may claim an existing account
+		log.Warn().Str("provider", provider).Str("email", claims.Email).Str("user_id", user.ID).
+			Bool("provider_verified", claims.EmailVerified).Msg("Refusing to link OIDC identity to unverified email")
+		return nil, pke.NewApiError(pke.CodeUserAlreadyExists)
+	}
+
+	if err := s.identityDAO.Create(&table.UserIdentity{
+		UserID:      user.ID,
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		Provisioned: provisioned,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// provider returns a configured provider, running discovery if needed
+func (s *OIDCService) provider(name string) (*oidcProvider, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, pke.NewApiError(pke.CodeNotFound)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauth2 != nil {
+		return p, nil
+	}
+
+	// Discovery also sets up the key set used for later verifications, so it
+	// must not be bound to the lifetime of the current request
+	client := &http.Client{Timeout: oidcHTTPTimeout}
+	provider, err := oidc.NewProvider(oidc.ClientContext(context.Background(), client), p.config.Issuer)
+	if err != nil {
+		log.Error(err).Str("provider", name).Str("issuer", p.config.Issuer).Msg("OIDC discovery failed")
+		return nil, pke.NewApiError(pke.CodeExternalServiceDown)
+	}
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.config.Scopes,
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.config.ClientID})
+	return p, nil
+}
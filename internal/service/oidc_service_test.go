@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+	"github.com/sanmu2018/word-hero/pkg/ratelimit"
+)
+
+const testClientID = "word-hero"
+
+// fakeIssuer is an OpenID Connect provider that issues ID tokens for one
+// authorization request at a time
+type fakeIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu        sync.Mutex
+	claims    jwt.MapClaims
+	codes     map[string]authorization
+	exchanges int
+}
+
+// authorization is what the provider remembers between the redirect and the
+// code exchange
+type authorization struct {
+	challenge string
+	nonce     string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	issuer := &fakeIssuer{key: key, codes: make(map[string]authorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                issuer.URL,
+			"authorization_endpoint":                issuer.URL + "/authorize",
+			"token_endpoint":                        issuer.URL + "/token",
+			"jwks_uri":                              issuer.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", issuer.token)
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// authorize follows the authorization URL as a signed-in user would and
+// returns the code the provider redirects back with
+func (i *fakeIssuer) authorize(t *testing.T, authURL string, claims jwt.MapClaims) string {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid authorization URL: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("client_id") != testClientID || query.Get("code_challenge_method") != "S256" || query.Get("state") == "" {
+		t.Fatalf("unexpected authorization request: %s", authURL)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	code := utils.GenerateRandomString(16)
+	i.codes[code] = authorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	i.claims = claims
+	return code
+}
+
+// token exchanges a code for an ID token, checking the PKCE verifier
+func (i *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.exchanges++
+
+	auth, ok := i.codes[r.PostFormValue("code")]
+	delete(i.codes, r.PostFormValue("code"))
+	digest := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(digest[:]) != auth.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   i.URL,
+		"aud":   testClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": auth.nonce,
+	}
+	for name, value := range i.claims {
+		claims[name] = value
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = "test"
+	signed, err := idToken.SignedString(i.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     signed,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// errorNo returns the pke code carried by err, or 0
+func errorNo(err error) int {
+	var coded interface{ ErrorNo() int }
+	if errors.As(err, &coded) {
+		return coded.ErrorNo()
+	}
+	return 0
+}
+
+func newTestOIDCService(t *testing.T, issuerURL string) (*OIDCService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := daotest.Mock(t)
+	jwtUtils, err := utils.NewJWTUtils(&conf.JWTConfig{Secret: "test-secret", ExpiresIn: "1h"})
+	if err != nil {
+		t.Fatalf("NewJWTUtils() error = %v", err)
+	}
+	userDAO := dao.NewUserDAO()
+	authService := &AuthService{
+		userDAO:    userDAO,
+		jwtUtils:   jwtUtils,
+		loginGuard: newTestLoginGuard(t),
+		authConfig: &conf.AuthConfig{RequireEmailVerification: true},
+	}
+
+	s, err := NewOIDCService([]conf.OIDCProviderConfig{{
+		Name:         "school",
+		Issuer:       issuerURL,
+		ClientID:     testClientID,
+		ClientSecret: "client-secret",
+	}}, "http://localhost:8080", authService, userDAO, dao.NewUserIdentityDAO())
+	if err != nil {
+		t.Fatalf("NewOIDCService() error = %v", err)
+	}
+	return s, mock
+}
+
+func bobClaims(emailVerified bool) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":                "bob-subject",
+		"email":              "bob@school.example",
+		"email_verified":     emailVerified,
+		"name":               "Bob",
+		"preferred_username": "bob",
+	}
+}
+
+func expectNoOIDCIdentity(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT \* FROM "user_identities" WHERE provider = .* AND subject = `).
+		WithArgs("school", "bob-subject", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+func TestOIDCDiscoveryFailure(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	s, _ := newTestOIDCService(t, issuer.URL+"/unknown")
+
+	if _, _, err := s.BeginLogin("school"); errorNo(err) != pke.CodeExternalServiceDown {
+		t.Errorf("BeginLogin() error = %v, want CodeExternalServiceDown", err)
+	}
+	if _, _, err := s.BeginLogin("other"); errorNo(err) != pke.CodeNotFound {
+		t.Errorf("BeginLogin(other) error = %v, want CodeNotFound", err)
+	}
+}
+
+func TestOIDCLoginProvisionsUser(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	s, mock := newTestOIDCService(t, issuer.URL)
+
+	authURL, flow, err := s.BeginLogin("school")
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	code := issuer.authorize(t, authURL, bobClaims(true))
+
+	expectNoOIDCIdentity(mock)
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE email = `).WillReturnRows(sqlmock.NewRows(userColumns))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "users" WHERE username = `).WithArgs("bob").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO "users" .*`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("new-1"))
+	mock.ExpectQuery(`INSERT INTO "user_identities" .*`).
+		WithArgs("new-1", "school", "bob-subject", "bob@school.example", true, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("identity-1"))
+
+	result, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", flow)
+	if err != nil {
+		t.Fatalf("CompleteLogin() error = %v", err)
+	}
+	if result.Token == "" || result.User.Username != "bob" || result.User.EmailVerifiedAt == nil {
+		t.Errorf("CompleteLogin() = %+v, want a session for the verified new user bob", result)
+	}
+}
+
+func TestOIDCLoginRejectsStateMismatch(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	s, _ := newTestOIDCService(t, issuer.URL)
+
+	authURL, flow, err := s.BeginLogin("school")
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	code := issuer.authorize(t, authURL, bobClaims(true))
+
+	for name, state := range map[string]string{"other state": "forged", "empty state": ""} {
+		if _, err := s.CompleteLogin(context.Background(), "school", code, state, "10.0.0.1", flow); errorNo(err) != pke.CodeInvalidToken {
+			t.Errorf("%s: CompleteLogin() error = %v, want CodeInvalidToken", name, err)
+		}
+	}
+	if _, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", nil); errorNo(err) != pke.CodeInvalidToken {
+		t.Errorf("missing flow: CompleteLogin() error = %v, want CodeInvalidToken", err)
+	}
+	if issuer.exchanges != 0 {
+		t.Errorf("code exchanged %d times after a state mismatch", issuer.exchanges)
+	}
+}
+
+func TestOIDCLoginRejectsPKCEMismatch(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	s, _ := newTestOIDCService(t, issuer.URL)
+
+	authURL, flow, err := s.BeginLogin("school")
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	code := issuer.authorize(t, authURL, bobClaims(true))
+
+	// A code intercepted from another browser comes without its verifier
+	_, other, err := s.BeginLogin("school")
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	other.State = flow.State
+	if _, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", other); errorNo(err) != pke.CodeAuthFailed {
+		t.Errorf("CompleteLogin() error = %v, want CodeAuthFailed", err)
+	}
+}
+
+func TestOIDCLoginRejectsNonceMismatch(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	s, _ := newTestOIDCService(t, issuer.URL)
+
+	authURL, flow, err := s.BeginLogin("school")
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	code := issuer.authorize(t, authURL, bobClaims(true))
+
+	flow.Nonce = "replayed"
+	if _, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", flow); errorNo(err) != pke.CodeAuthFailed {
+		t.Errorf("CompleteLogin() error = %v, want CodeAuthFailed", err)
+	}
+}
+
+func TestOIDCLoginLinkRules(t *testing.T) {
+	tests := []struct {
+		name             string
+		providerVerified bool
+		localVerified    bool
+		wantLinked       bool
+	}{
+		{"both verified", true, true, true},
+		{"provider unverified", false, true, false},
+		{"local unverified", true, false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			s, mock := newTestOIDCService(t, issuer.URL)
+
+			authURL, flow, err := s.BeginLogin("school")
+			if err != nil {
+				t.Fatalf("BeginLogin() error = %v", err)
+			}
+			code := issuer.authorize(t, authURL, bobClaims(tc.providerVerified))
+
+			var verifiedAt interface{}
+			if tc.localVerified {
+				verifiedAt = int64(1700000000000)
+			}
+			expectNoOIDCIdentity(mock)
+			mock.ExpectQuery(`SELECT \* FROM "users" WHERE email = `).WithArgs("bob@school.example", 1).
+				WillReturnRows(sqlmock.NewRows(userColumns).AddRow("local-1", "bobby", "bob@school.example", "user", true, verifiedAt))
+			if tc.wantLinked {
+				mock.ExpectQuery(`INSERT INTO "user_identities" .*`).
+					WithArgs("local-1", "school", "bob-subject", "bob@school.example", false, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("identity-1"))
+			}
+
+			result, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", flow)
+			if tc.wantLinked {
+				if err != nil || result.User.ID != "local-1" {
+					t.Fatalf("CompleteLogin() = %+v, %v, want the linked local user", result, err)
+				}
+				return
+			}
+			if errorNo(err) != pke.CodeUserAlreadyExists {
+				t.Errorf("CompleteLogin() error = %v, want CodeUserAlreadyExists", err)
+			}
+		})
+	}
+}
+
+func TestOIDCLoginReturningIdentity(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	s, mock := newTestOIDCService(t, issuer.URL)
+
+	authURL, flow, err := s.BeginLogin("school")
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	// The email changed at the provider; the subject still identifies the user
+	claims := bobClaims(false)
+	claims["email"] = "robert@school.example"
+	code := issuer.authorize(t, authURL, claims)
+
+	mock.ExpectQuery(`SELECT \* FROM "user_identities"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "subject"}).AddRow("identity-1", "local-1", "school", "bob-subject"))
+	mock.ExpectExec(`UPDATE "user_identities" SET "last_login_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).WithArgs("local-1", 1).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow("local-1", "bobby", "bob@school.example", "user", true, int64(1700000000000)))
+
+	result, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", flow)
+	if err != nil || result.User.ID != "local-1" {
+		t.Fatalf("CompleteLogin() = %+v, %v, want the linked user", result, err)
+	}
+}
+
+func TestOIDCLoginProvisionsWithoutUnverifiedEmail(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	s, mock := newTestOIDCService(t, issuer.URL)
+
+	authURL, flow, err := s.BeginLogin("school")
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	code := issuer.authorize(t, authURL, bobClaims(false))
+
+	expectNoOIDCIdentity(mock)
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE email = `).WillReturnRows(sqlmock.NewRows(userColumns))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "users" WHERE username = `).WithArgs("bob").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO "users" .*`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("new-1"))
+	mock.ExpectQuery(`INSERT INTO "user_identities" .*`).
+		WithArgs("new-1", "school", "bob-subject", "bob@school.example", true, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("identity-1"))
+
+	result, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", flow)
+	if err != nil {
+		t.Fatalf("CompleteLogin() error = %v", err)
+	}
+	if result.Token == "" || result.User.Email != "" || result.User.EmailVerifiedAt != nil {
+		t.Errorf("CompleteLogin() = %+v, want a session for a new user without the unverified email", result)
+	}
+}
+
+func TestOIDCLoginAppliesLoginPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		verified interface{}
+		locked   bool
+		wantCode int
+	}{
+		{"unverified local email", nil, false, pke.CodeUserNotVerified},
+		{"locked account", int64(1700000000000), true, pke.CodeAccountLocked},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			s, mock := newTestOIDCService(t, issuer.URL)
+			if tc.locked {
+				for i := 0; i < 3; i++ {
+					s.authService.loginGuard.Fail("10.0.0.9", "bobby")
+				}
+			}
+
+			authURL, flow, err := s.BeginLogin("school")
+			if err != nil {
+				t.Fatalf("BeginLogin() error = %v", err)
+			}
+			code := issuer.authorize(t, authURL, bobClaims(true))
+
+			mock.ExpectQuery(`SELECT \* FROM "user_identities"`).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "subject"}).AddRow("identity-1", "local-1", "school", "bob-subject"))
+			mock.ExpectExec(`UPDATE "user_identities" SET "last_login_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).WithArgs("local-1", 1).
+				WillReturnRows(sqlmock.NewRows(userColumns).AddRow("local-1", "bobby", "bob@school.example", "user", true, tc.verified))
+
+			if _, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", flow); errorNo(err) != tc.wantCode {
+				t.Errorf("CompleteLogin() error = %v, want code %d", err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestOIDCLoginThrottlesIPBeforeProvisioning(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	s, _ := newTestOIDCService(t, issuer.URL)
+	guard, err := NewLoginGuard(ratelimit.NewMemoryStore(), &conf.LoginLimitConfig{
+		IPPerMinute:       1,
+		IPBurst:           1,
+		UsernamePerMinute: 1000,
+		UsernameBurst:     1000,
+		MaxFailures:       3,
+		FailureWindow:     "15m",
+		LockoutBase:       "1m",
+		LockoutMax:        "1h",
+	})
+	if err != nil {
+		t.Fatalf("NewLoginGuard() error = %v", err)
+	}
+	s.authService.loginGuard = guard
+	if err := guard.AllowIP("10.0.0.1"); err != nil {
+		t.Fatalf("AllowIP() error = %v", err)
+	}
+
+	authURL, flow, err := s.BeginLogin("school")
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	code := issuer.authorize(t, authURL, bobClaims(true))
+
+	// No query is expected: a throttled client neither links nor creates a user
+	if _, err := s.CompleteLogin(context.Background(), "school", code, flow.State, "10.0.0.1", flow); errorNo(err) != pke.CodeTooManyRequests {
+		t.Errorf("CompleteLogin() error = %v, want CodeTooManyRequests", err)
+	}
+}
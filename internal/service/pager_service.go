@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"math"
 
@@ -42,18 +43,21 @@ func (ps *PagerService) GetTotalPages(pageSize int) int {
 	return int(math.Ceil(float64(totalWords) / float64(pageSize)))
 }
 
-// GetPage returns a specific page of words with metadata
-func (ps *PagerService) GetPage(list dto.BaseList) (*dto.Page, error) {
+// GetPage returns a specific page of the words passing filter, which may be nil
+func (ps *PagerService) GetPage(list dto.BaseList, filter *dao.WordFilter) (*dto.Page, error) {
 	if ps.vocabularyService == nil {
 		return nil, fmt.Errorf("vocabulary service not initialized")
 	}
 
 	baseList := &dao.BaseList{
-		PageNum:  list.PageNum,
-		PageSize: list.PageSize,
+		PageNum:   list.PageNum,
+		PageSize:  list.PageSize,
+		Sort:      list.Sort,
+		Cursor:    list.Cursor,
+		SkipTotal: list.SkipTotal,
 	}
 
-	vocabPage, err := ps.vocabularyService.GetWordsByPage(baseList)
+	vocabPage, err := ps.vocabularyService.ListWords(baseList, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get page: %w", err)
 	}
@@ -61,19 +65,21 @@ func (ps *PagerService) GetPage(list dto.BaseList) (*dto.Page, error) {
 	return &dto.Page{
 		Words:      vocabPage.Words,
 		TotalCount: vocabPage.TotalCount,
+		NextCursor: vocabPage.NextCursor,
 	}, nil
 }
 
 // GetPageData returns page data with additional metadata for API responses
-func (ps *PagerService) GetPageData(list dto.BaseList) (*pke.BaseListResp, error) {
-	page, err := ps.GetPage(list)
+func (ps *PagerService) GetPageData(list dto.BaseList, filter *dao.WordFilter) (*pke.BaseListResp, error) {
+	page, err := ps.GetPage(list, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	data := &pke.BaseListResp{
-		Items: page.Words,
-		Total: page.TotalCount,
+		Items:      page.Words,
+		Total:      page.TotalCount,
+		NextCursor: page.NextCursor,
 	}
 	return data, nil
 }
@@ -117,3 +123,20 @@ func (ps *PagerService) GetPageRange(pageNumber, pageSize int) (start, end int,
 
 	return start, end, nil
 }
+
+// pagingError turns errors about unusable cursors or sorts into an API error
+func pagingError(err error) error {
+	if errors.Is(err, dao.ErrInvalidCursor) {
+		log.Warn().Err(err).Msg("Rejected pagination parameters")
+		return pke.NewApiError(pke.CodeInvalidPagination)
+	}
+	var sortErr *dao.SortError
+	if errors.As(err, &sortErr) {
+		log.Warn().Err(err).Msg("Rejected sort parameter")
+		return pke.NewApiError(pke.CodeInvalidRequest).WithDetail(sortErr.Key, sortErr.DetailArgs()...)
+	}
+	if errors.Is(err, dao.ErrSortNeedsUser) {
+		return pke.NewApiError(pke.CodeLoginRequired)
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/mailer"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+const (
+	// resetTokenLength is the length of the random password reset token
+	resetTokenLength = 43
+	// forgotPasswordMinDuration pads forgot-password responses so that known
+	// and unknown addresses take the same time to answer
+	forgotPasswordMinDuration = 500 * time.Millisecond
+)
+
+// ForgotPassword mails a password reset link to the account registered with
+// email. It reports success whether or not the address is known, and always
+// takes at least forgotPasswordMinDuration so timing does not reveal it either.
+func (s *AuthService) ForgotPassword(email string) error {
+	deadline := time.Now().Add(forgotPasswordMinDuration)
+	defer func() {
+		time.Sleep(time.Until(deadline))
+	}()
+
+	user, err := s.userDAO.FindByEmail(strings.TrimSpace(email))
+	if err != nil || !user.IsActive {
+		log.Debug().Str("email", email).Msg("Password reset skipped")
+		return nil
+	}
+
+	if latest, err := s.userTokenDAO.FindLatest(user.ID, table.TokenPurposePasswordReset); err == nil {
+		if time.Since(time.UnixMilli(latest.CreatedAt)) < resendInterval {
+			log.Debug().Str("user_id", user.ID).Msg("Password reset throttled")
+			return nil
+		}
+	}
+
+	// Only the most recent link stays valid
+	if err := s.userTokenDAO.InvalidateAll(user.ID, table.TokenPurposePasswordReset); err != nil {
+		log.Error(err).Str("user_id", user.ID).Msg("Failed to invalidate password reset tokens")
+		return nil
+	}
+	if err := s.sendPasswordResetEmail(user); err != nil {
+		log.Error(err).Str("user_id", user.ID).Msg("Failed to send password reset email")
+	}
+	return nil
+}
+
+// ResetPassword consumes a password reset token and sets a new password.
+// All existing sessions of the user are revoked.
+func (s *AuthService) ResetPassword(token, newPassword string) error {
+	record, err := s.userTokenDAO.FindByHash(table.TokenPurposePasswordReset, utils.HashToken(token))
+	if err != nil {
+		return pke.NewApiError(pke.CodeInvalidToken)
+	}
+	if time.Now().UnixMilli() > record.ExpiresAt {
+		return pke.NewApiError(pke.CodeTokenExpired)
+	}
+
+	if !s.isValidPassword(newPassword) {
+		return pke.NewApiError(pke.CodeInvalidPassword)
+	}
+
+	user, err := s.userDAO.FindByID(record.UserID)
+	if err != nil {
+		return pke.NewApiError(pke.CodeInvalidToken)
+	}
+
+	consumed, err := s.userTokenDAO.Consume(record.ID)
+	if err != nil {
+		return fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+	if !consumed {
+		return pke.NewApiError(pke.CodeInvalidToken)
+	}
+
+	if err := s.hashPassword(user, newPassword); err != nil {
+		return fmt.Errorf("failed to hash new password: %w", err)
+	}
+	if err := s.userDAO.ResetPassword(user.ID, user.PasswordHash); err != nil {
+		return err
+	}
+
+	if err := s.userTokenDAO.InvalidateAll(user.ID, table.TokenPurposePasswordReset); err != nil {
+		log.Warn().Err(err).Str("user_id", user.ID).Msg("Failed to invalidate remaining password reset tokens")
+	}
+	// The reset proves control of the mailbox, so also lift any lockout and
+	// treat the email as verified
+	s.loginGuard.Succeed(user.Username)
+	if user.EmailVerifiedAt == nil {
+		if err := s.userDAO.MarkEmailVerified(user.ID, time.Now().UnixMilli()); err != nil {
+			log.Warn().Err(err).Str("user_id", user.ID).Msg("Failed to mark email as verified after password reset")
+		}
+	}
+
+	log.Info().Str("user_id", user.ID).Msg("User password reset via email")
+	return nil
+}
+
+// sendPasswordResetEmail issues a password reset token and mails a link to the user
+func (s *AuthService) sendPasswordResetEmail(user *table.User) error {
+	ttl, err := time.ParseDuration(s.authConfig.PasswordResetTTL)
+	if err != nil {
+		return fmt.Errorf("invalid password reset ttl: %w", err)
+	}
+
+	token := utils.GenerateRandomString(resetTokenLength)
+	if err := s.userTokenDAO.Create(&table.UserToken{
+		UserID:    user.ID,
+		Purpose:   table.TokenPurposePasswordReset,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl).UnixMilli(),
+	}); err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/?resetToken=" + url.QueryEscape(token)
+	s.sendMail(&mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(mailLanguage(user), "mail.reset_subject"),
+		Body:    i18n.T(mailLanguage(user), "mail.reset_body", displayName(user), ttl, link),
+	})
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// expectResetUser returns alice at the given session version
+func expectResetUser(mock sqlmock.Sqlmock, sessionVersion int) {
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).WithArgs("user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "role", "is_active", "email_verified_at", "session_version"}).
+			AddRow("user-1", "alice", "alice@example.com", "user", true, int64(1700000000000), sessionVersion))
+}
+
+func TestResetPasswordIsSingleUseAndRevokesSessions(t *testing.T) {
+	s, mock := newTestTokenAuthService(t)
+	session, err := s.jwtUtils.GenerateToken("user-1", "alice", "alice@example.com", "user", 0)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+
+	expectTokenRecord(mock, table.TokenPurposePasswordReset, "reset-1", expiresAt)
+	expectResetUser(mock, 0)
+	expectConsume(mock, "reset-1", true)
+	mock.ExpectExec(`UPDATE "users" SET "password_hash"=.*,"session_version"=session_version \+ 1`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "user_tokens" SET "used_at"=.* WHERE user_id = .* AND purpose = .* AND used_at IS NULL`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := s.ResetPassword("reset-token", "new-secret"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+
+	// The session issued before the reset carries the old version
+	expectResetUser(mock, 1)
+	if _, err := s.ValidateToken(session); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("ValidateToken(old session) error = %v, want ErrInvalidToken", err)
+	}
+
+	expectTokenRecord(mock, table.TokenPurposePasswordReset, "reset-1", expiresAt)
+	expectResetUser(mock, 1)
+	expectConsume(mock, "reset-1", false)
+	if err := s.ResetPassword("reset-token", "other-secret"); errorNo(err) != pke.CodeInvalidToken {
+		t.Errorf("second ResetPassword() error = %v, want CodeInvalidToken", err)
+	}
+}
+
+func TestResetPasswordRejectsExpiredToken(t *testing.T) {
+	s, mock := newTestTokenAuthService(t)
+
+	expectTokenRecord(mock, table.TokenPurposePasswordReset, "reset-1", time.Now().Add(-time.Minute).UnixMilli())
+	if err := s.ResetPassword("reset-token", "new-secret"); errorNo(err) != pke.CodeTokenExpired {
+		t.Errorf("ResetPassword() error = %v, want CodeTokenExpired", err)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// Pattern search modes
+const (
+	PatternModeRegex    = "regex"
+	PatternModeWildcard = "wildcard"
+)
+
+const (
+	// maxPatternLength bounds the length of a user supplied pattern
+	maxPatternLength = 64
+	// maxPatternNodes bounds the size of the parsed pattern
+	maxPatternNodes = 40
+	// maxPatternRepeat bounds counted repetitions such as a{1,n}
+	maxPatternRepeat = 20
+	// patternSearchTimeout bounds how long the database may spend on a pattern
+	patternSearchTimeout = 3 * time.Second
+)
+
+// unsupportedEscapes are escapes RE2 accepts but Postgres regexes treat differently
+var unsupportedEscapes = []string{`\p`, `\P`, `\Q`, `\E`, `\z`, `\C`}
+
+// SearchWordsByPattern finds words matching a regular expression or a
+// wildcard pattern where ? matches one character and * any number
+func (vs *VocabularyService) SearchWordsByPattern(ctx context.Context, req dto.WordPatternSearchRequest) (int64, []table.Word, string, error) {
+	pattern, err := buildSearchPattern(req.Mode, req.Q)
+	if err != nil {
+		log.Warn().Err(err).Str("pattern", req.Q).Msg("Rejected search pattern")
+		return 0, nil, "", pke.NewApiError(pke.CodeInvalidSearchQuery)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, patternSearchTimeout)
+	defer cancel()
+
+	total, words, next, err := vs.wordDAO.SearchWordsByPattern(ctx, pattern, req.BaseList)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			log.Warn().Str("pattern", pattern).Msg("Pattern search timed out")
+			return 0, nil, "", pke.NewApiError(pke.CodeSearchTimeout)
+		}
+		return 0, nil, "", pagingError(err)
+	}
+	return total, words, next, nil
+}
+
+// buildSearchPattern turns user input into a validated regular expression
+func buildSearchPattern(mode, query string) (string, error) {
+	if len(query) == 0 || len(query) > maxPatternLength {
+		return "", errors.New("pattern length out of range")
+	}
+
+	switch mode {
+	case "", PatternModeRegex:
+		if err := validateRegex(query); err != nil {
+			return "", err
+		}
+		return query, nil
+	case PatternModeWildcard:
+		var b strings.Builder
+		b.WriteString("^")
+		previous := rune(0)
+		for _, r := range query {
+			switch {
+			case r == '*' && previous == '*':
+				// ** means the same as *
+			case r == '*':
+				b.WriteString(".*")
+			case r == '?':
+				b.WriteString(".")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+			previous = r
+		}
+		b.WriteString("$")
+
+		// Wildcards are regexes too, and many of them are as costly
+		pattern := b.String()
+		if err := checkRegexComplexity(pattern); err != nil {
+			return "", err
+		}
+		return pattern, nil
+	default:
+		return "", errors.New("unknown pattern mode")
+	}
+}
+
+// validateRegex accepts only the small, well-behaved subset of regular
+// expressions that means the same in RE2 and Postgres, and rejects patterns
+// whose nested repetition could make matching expensive
+func validateRegex(pattern string) error {
+	if strings.Contains(pattern, "(?") {
+		return errors.New("flags, lookarounds and named groups are not supported")
+	}
+	for _, escape := range unsupportedEscapes {
+		if strings.Contains(pattern, escape) {
+			return errors.New("unsupported escape " + escape)
+		}
+	}
+
+	return checkRegexComplexity(pattern)
+}
+
+// checkRegexComplexity rejects patterns that are too large or nest
+// repetitions
+func checkRegexComplexity(pattern string) error {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return err
+	}
+	nodes := 0
+	return walkRegex(re, false, &nodes)
+}
+
+// walkRegex checks the size and repetition structure of a parsed regex
+func walkRegex(re *syntax.Regexp, inRepeat bool, nodes *int) error {
+	*nodes++
+	if *nodes > maxPatternNodes {
+		return errors.New("pattern is too complex")
+	}
+
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		if re.Op == syntax.OpRepeat && re.Max > maxPatternRepeat {
+			return errors.New("repetition count is too large")
+		}
+		if inRepeat && re.Op != syntax.OpQuest {
+			return errors.New("nested repetition is not allowed")
+		}
+		inRepeat = true
+	}
+	for _, sub := range re.Sub {
+		if err := walkRegex(sub, inRepeat, nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildSearchPatternRegex(t *testing.T) {
+	accepted := []string{
+		"^ab.c$",
+		"colou?r",
+		"^(un|re)do",
+		"[a-z]+ing$",
+		"a{2,20}",
+		"(ab)?c+",
+		"选择",
+		strings.Repeat("(a)", 19),
+	}
+	for _, pattern := range accepted {
+		if got, err := buildSearchPattern(PatternModeRegex, pattern); err != nil || got != pattern {
+			t.Errorf("buildSearchPattern(%q) = %q, %v, want it accepted unchanged", pattern, got, err)
+		}
+	}
+
+	rejected := map[string]string{
+		"empty":                 "",
+		"too long":              strings.Repeat("a", maxPatternLength+1),
+		"nested plus":           "(a+)+",
+		"nested star":           "(a|b*)*",
+		"nested counted":        "(ab{2})+",
+		"repeat bound":          "a{1,21}",
+		"node limit":            strings.Repeat("(a)", 20),
+		"unicode class":         `\pL+`,
+		"negated class":         `\PL`,
+		"quoted literal":        `\Qa*\E`,
+		"end of text":           `a\z`,
+		"any byte":              `\C`,
+		"flags":                 "(?i)abc",
+		"named group":           "(?P<x>a)",
+		"syntax error":          "(ab",
+		"unbalanced class":      "[a-",
+		"missing repeat target": "*a",
+	}
+	for name, pattern := range rejected {
+		if got, err := buildSearchPattern(PatternModeRegex, pattern); err == nil {
+			t.Errorf("%s: buildSearchPattern(%q) = %q, want an error", name, pattern, got)
+		}
+	}
+
+	if _, err := buildSearchPattern("glob", "a*"); err == nil {
+		t.Error("buildSearchPattern() with an unknown mode succeeded, want an error")
+	}
+}
+
+func TestBuildSearchPatternWildcard(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"c?t", "^c.t$"},
+		{"un*able", "^un.*able$"},
+		{"**ing", "^.*ing$"},
+		{"a.b*", `^a\.b.*$`},
+		{"(a)+[b]{2}", `^\(a\)\+\[b\]\{2\}$`},
+		{`$^|\`, `^\$\^\|\\$`},
+		{"选?", "^选.$"},
+	}
+	for _, test := range tests {
+		if got, err := buildSearchPattern(PatternModeWildcard, test.query); err != nil || got != test.want {
+			t.Errorf("buildSearchPattern(wildcard, %q) = %q, %v, want %q", test.query, got, err, test.want)
+		}
+	}
+
+	// Wildcards get the same complexity checks as regexes
+	for _, query := range []string{strings.Repeat("a*", 20), strings.Repeat("?a", 21)} {
+		if got, err := buildSearchPattern(PatternModeWildcard, query); err == nil {
+			t.Errorf("buildSearchPattern(wildcard, %q) = %q, want an error", query, got)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/log"
+)
+
+const (
+	// DefaultSuggestLimit is the number of suggestions returned by default
+	DefaultSuggestLimit = 10
+	// MaxSuggestLimit caps the number of suggestions per request
+	MaxSuggestLimit = 20
+	// suggestRefreshInterval is how often the index checks the words table for
+	// changes made outside this process, such as the migrate tool
+	suggestRefreshInterval = time.Minute
+)
+
+// suggestEntry is one indexed key pointing at a word
+type suggestEntry struct {
+	key     string
+	match   string
+	field   string
+	wordID  string
+	english string
+	chinese string
+}
+
+// SuggestService answers prefix lookups from an in-memory index of English
+// headwords and Chinese glosses, sorted by key for binary search
+type SuggestService struct {
+	wordDAO *dao.WordDAO
+
+	mu        sync.RWMutex
+	entries   []suggestEntry
+	count     int64
+	updatedAt int64
+
+	invalidate chan struct{}
+}
+
+// NewSuggestService creates a new SuggestService instance
+func NewSuggestService(wordDAO *dao.WordDAO) *SuggestService {
+	return &SuggestService{
+		wordDAO:    wordDAO,
+		invalidate: make(chan struct{}, 1),
+	}
+}
+
+// Start builds the index and keeps it up to date in the background
+func (s *SuggestService) Start() {
+	if err := s.rebuild(); err != nil {
+		log.Error(err).Msg("Failed to build suggest index")
+	}
+
+	go func() {
+		ticker := time.NewTicker(suggestRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.refreshIfChanged()
+			case <-s.invalidate:
+				if err := s.rebuild(); err != nil {
+					log.Error(err).Msg("Failed to rebuild suggest index")
+				}
+			}
+		}
+	}()
+}
+
+// Invalidate schedules a rebuild after words were changed in this process
+func (s *SuggestService) Invalidate() {
+	select {
+	case s.invalidate <- struct{}{}:
+	default:
+	}
+}
+
+// Suggest returns up to limit words whose headword or gloss starts with prefix
+func (s *SuggestService) Suggest(prefix string, limit int) []dto.WordSuggestion {
+	key := strings.ToLower(strings.TrimSpace(prefix))
+	if key == "" {
+		return []dto.WordSuggestion{}
+	}
+	if limit <= 0 {
+		limit = DefaultSuggestLimit
+	}
+	if limit > MaxSuggestLimit {
+		limit = MaxSuggestLimit
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	suggestions := make([]dto.WordSuggestion, 0, limit)
+	seen := make(map[string]bool, limit)
+	start := sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].key >= key
+	})
+	for i := start; i < len(s.entries) && len(suggestions) < limit; i++ {
+		entry := s.entries[i]
+		if !strings.HasPrefix(entry.key, key) {
+			break
+		}
+		if seen[entry.wordID] {
+			continue
+		}
+		seen[entry.wordID] = true
+		suggestions = append(suggestions, dto.WordSuggestion{
+			WordID:  entry.wordID,
+			English: entry.english,
+			Chinese: entry.chinese,
+			Field:   entry.field,
+			Match:   entry.match,
+		})
+	}
+	return suggestions
+}
+
+// refreshIfChanged rebuilds the index when the words table has changed
+func (s *SuggestService) refreshIfChanged() {
+	count, updatedAt, err := s.wordDAO.GetVersion()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to check words for suggest index")
+		return
+	}
+
+	s.mu.RLock()
+	unchanged := count == s.count && updatedAt == s.updatedAt
+	s.mu.RUnlock()
+	if unchanged {
+		return
+	}
+	if err := s.rebuild(); err != nil {
+		log.Error(err).Msg("Failed to rebuild suggest index")
+	}
+}
+
+// rebuild loads all words and swaps in a freshly sorted index
+func (s *SuggestService) rebuild() error {
+	count, updatedAt, err := s.wordDAO.GetVersion()
+	if err != nil {
+		return err
+	}
+	words, err := s.wordDAO.GetAllWords()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]suggestEntry, 0, len(words)*3)
+	for _, word := range words {
+		english := strings.TrimSpace(word.English)
+		if english != "" {
+			entries = append(entries, suggestEntry{
+				key:     strings.ToLower(english),
+				match:   english,
+				field:   "english",
+				wordID:  word.ID,
+				english: word.English,
+				chinese: word.Chinese,
+			})
+		}
+		for _, gloss := range utils.SplitGlosses(word.Chinese) {
+			entries = append(entries, suggestEntry{
+				key:     gloss,
+				match:   gloss,
+				field:   "chinese",
+				wordID:  word.ID,
+				english: word.English,
+				chinese: word.Chinese,
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	s.mu.Lock()
+	s.entries, s.count, s.updatedAt = entries, count, updatedAt
+	s.mu.Unlock()
+
+	log.Info().Int("words", len(words)).Int("keys", len(entries)).Msg("Suggest index built")
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/dto"
+)
+
+// newTestSuggestService builds the index from a few words plus zz00 to zz24
+func newTestSuggestService(t *testing.T) *SuggestService {
+	t.Helper()
+	mock := daotest.Mock(t)
+	rows := sqlmock.NewRows([]string{"id", "english", "chinese"}).
+		AddRow("w5", "app", "n. 应用程序").
+		AddRow("w1", "apple", "n. 苹果；苹果树").
+		AddRow("w2", "Application", "n. 应用；申请").
+		AddRow("w3", "apply", "v. 申请，应用").
+		AddRow("w4", "banana", "n. 香蕉")
+	for i := 24; i >= 0; i-- {
+		rows.AddRow(fmt.Sprintf("z%02d", i), fmt.Sprintf("zz%02d", i), "")
+	}
+	mock.ExpectQuery(`SELECT COUNT\(\*\) AS count`).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "updated_at"}).AddRow(30, 1700000000000))
+	mock.ExpectQuery(`SELECT \* FROM "words" ORDER BY english ASC`).WillReturnRows(rows)
+
+	s := NewSuggestService(dao.NewWordDAO())
+	if err := s.rebuild(); err != nil {
+		t.Fatalf("rebuild() error = %v", err)
+	}
+	return s
+}
+
+func suggestionIDs(suggestions []dto.WordSuggestion) []string {
+	ids := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		ids[i] = suggestion.WordID
+	}
+	return ids
+}
+
+func TestSuggestFindsPrefixInOrder(t *testing.T) {
+	s := newTestSuggestService(t)
+
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"app", 10, []string{"w5", "w1", "w2", "w3"}},
+		{" APPL ", 10, []string{"w1", "w2", "w3"}},
+		{"app", 2, []string{"w5", "w1"}},
+		{"b", 10, []string{"w4"}},
+		// Sorts between apply and banana, so the search stops at once
+		{"apq", 10, []string{}},
+		{"", 10, []string{}},
+		// Both glosses of apple start with 苹果, but it is suggested once
+		{"苹果", 10, []string{"w1"}},
+		{"香", 10, []string{"w4"}},
+	}
+	for _, test := range tests {
+		if got := suggestionIDs(s.Suggest(test.prefix, test.limit)); !slices.Equal(got, test.want) {
+			t.Errorf("Suggest(%q, %d) = %v, want %v", test.prefix, test.limit, got, test.want)
+		}
+	}
+
+	suggestions := s.Suggest("应用", 10)
+	if ids := suggestionIDs(suggestions); len(ids) != 3 || ids[2] != "w5" {
+		t.Errorf("Suggest(应用) = %v, want w2 and w3 before the longer gloss of w5", ids)
+	}
+	if suggestions[0].Field != "chinese" || suggestions[0].Match != "应用" {
+		t.Errorf("Suggest(应用)[0] = %+v, want a chinese match on 应用", suggestions[0])
+	}
+	if first := s.Suggest("APPLI", 1)[0]; first.Field != "english" || first.Match != "Application" {
+		t.Errorf("Suggest(APPLI)[0] = %+v, want the headword as written", first)
+	}
+}
+
+func TestSuggestLimits(t *testing.T) {
+	s := newTestSuggestService(t)
+
+	if got := s.Suggest("zz", 0); len(got) != DefaultSuggestLimit || got[0].English != "zz00" || got[9].English != "zz09" {
+		t.Errorf("Suggest(zz, 0) = %v, want the first %d in order", suggestionIDs(got), DefaultSuggestLimit)
+	}
+	if got := s.Suggest("zz", 100); len(got) != MaxSuggestLimit {
+		t.Errorf("Suggest(zz, 100) returned %d, want at most %d", len(got), MaxSuggestLimit)
+	}
+}
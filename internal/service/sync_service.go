@@ -0,0 +1,196 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+const (
+	// syncChangeLimit is the number of changes returned by one sync
+	syncChangeLimit = 500
+	// syncSettleWindow holds back the latest changes until the next sync. A
+	// change stamped just before another but committed after it would
+	// otherwise be skipped by a token already past it.
+	syncSettleWindow = 2 * time.Second
+	// syncOperationRetention is how long applied operations are remembered.
+	// An older operation synced again is applied again, which the latest
+	// change winning makes harmless unless its word changed since.
+	syncOperationRetention = 30 * 24 * time.Hour
+	syncPurgeInterval      = time.Hour
+)
+
+// SyncService reconciles the word marks of offline clients with the server
+type SyncService struct {
+	syncOperationDAO *dao.SyncOperationDAO
+	wordDAO          *dao.WordDAO
+	wordTagService   *WordTagService
+}
+
+// NewSyncService creates a new SyncService instance
+func NewSyncService(syncOperationDAO *dao.SyncOperationDAO, wordDAO *dao.WordDAO, wordTagService *WordTagService) *SyncService {
+	log.Info().Msg("Creating sync service")
+
+	return &SyncService{
+		syncOperationDAO: syncOperationDAO,
+		wordDAO:          wordDAO,
+		wordTagService:   wordTagService,
+	}
+}
+
+// StartPurger periodically forgets operations applied longer than the
+// retention ago
+func (s *SyncService) StartPurger() {
+	go func() {
+		ticker := time.NewTicker(syncPurgeInterval)
+		defer ticker.Stop()
+		for {
+			purged, err := s.syncOperationDAO.PurgeBefore(time.Now().Add(-syncOperationRetention).UnixMilli())
+			if err == nil && purged > 0 {
+				log.Info().Int("operations", purged).Msg("Purged old sync operations")
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Sync applies the operations of an offline client of userID, then returns
+// the changes of the user's word tags since the client's last sync,
+// including those made by other clients. Operations are applied in order
+// and each word keeps its latest change by timestamp.
+func (s *SyncService) Sync(userID string, req *dto.SyncRequest) (*dto.SyncResponse, error) {
+	var after *dao.SyncKey
+	if req.SyncToken != "" {
+		var key dao.SyncKey
+		ok, err := dao.DecodeCursor(req.SyncToken, &key)
+		if err == nil && ok && uuid.Validate(key.ID) != nil {
+			err = dao.ErrInvalidCursor
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Msg("Rejected sync token")
+			return nil, invalidInput("sync.invalid_token")
+		}
+		if ok {
+			after = &key
+		}
+	}
+
+	results, marked, unmarked, err := s.apply(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.wordTagService.NotifyWordsChanged(userID, marked, unmarked)
+
+	response := &dto.SyncResponse{
+		Results:   results,
+		SyncToken: req.SyncToken,
+	}
+
+	cutoff := time.Now().Add(-syncSettleWindow).UnixMilli()
+	wordTags, err := s.syncOperationDAO.ListChanges(userID, after, cutoff, syncChangeLimit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(wordTags) > syncChangeLimit {
+		wordTags = wordTags[:syncChangeLimit]
+		response.HasMore = true
+	}
+	response.Changes = make([]dto.SyncChange, 0, len(wordTags))
+	for _, wordTag := range wordTags {
+		response.Changes = append(response.Changes, dto.SyncChange{
+			WordID:    wordTag.WordID,
+			IsMarked:  wordTag.IsKnown(),
+			MarkedAt:  wordTag.GetKnownTimestamp(),
+			ChangedAt: wordTag.LastChangedAt(),
+		})
+	}
+	if n := len(wordTags); n > 0 {
+		response.SyncToken = dao.EncodeCursor(dao.SyncKey{UpdatedAt: wordTags[n-1].UpdatedAt, ID: wordTags[n-1].ID})
+	}
+
+	log.Info().
+		Str("user_id", userID).
+		Int("operations", len(req.Operations)).
+		Int("marked", len(marked)).
+		Int("unmarked", len(unmarked)).
+		Int("changes", len(response.Changes)).
+		Msg("Synced client")
+
+	return response, nil
+}
+
+// apply applies the operations of req and returns their results and the
+// words they marked and unmarked
+func (s *SyncService) apply(userID string, req *dto.SyncRequest) ([]dto.SyncOperationResult, []string, []string, error) {
+	wordIDs := make([]string, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		wordIDs = append(wordIDs, op.WordID)
+	}
+	words, err := s.wordDAO.GetByIDs(wordIDs)
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Msg("Failed to get synced words")
+		return nil, nil, nil, err
+	}
+	exists := make(map[string]bool, len(words))
+	for _, word := range words {
+		exists[word.ID] = true
+	}
+
+	now := time.Now().UnixMilli()
+	results := make([]dto.SyncOperationResult, 0, len(req.Operations))
+	var marked, unmarked []string
+	for _, op := range req.Operations {
+		result := dto.SyncOperationResult{ID: op.ID}
+
+		known, valid := operationKnown(&op)
+		rejectCode := 0
+		if !valid {
+			rejectCode = pke.CodeInvalidMarkRequest
+		} else if !exists[op.WordID] {
+			rejectCode = pke.CodeWordNotFound
+		}
+		if rejectCode != 0 {
+			result.Status = dto.SyncStatusRejected
+			result.Error = i18n.ErrorMessage(req.Lang, rejectCode)
+			results = append(results, result)
+			continue
+		}
+
+		// A client clock running ahead must not win over later changes
+		var err error
+		result.Status, err = s.syncOperationDAO.Apply(userID, op.ID, op.WordID, known, min(op.Timestamp, now))
+		if err != nil {
+			result.Status = dto.SyncStatusFailed
+			result.Error = i18n.ErrorMessage(req.Lang, pke.CodeMarkFailed)
+		} else if result.Status == dto.SyncStatusApplied && known {
+			marked = append(marked, op.WordID)
+		} else if result.Status == dto.SyncStatusApplied {
+			unmarked = append(unmarked, op.WordID)
+		}
+		results = append(results, result)
+	}
+	return results, marked, unmarked, nil
+}
+
+// operationKnown returns whether op leaves its word known, and false for
+// invalid operations such as a review without its answer
+func operationKnown(op *dto.SyncOperation) (known bool, valid bool) {
+	switch op.Type {
+	case dto.SyncOpMark:
+		return true, true
+	case dto.SyncOpUnmark:
+		return false, true
+	case dto.SyncOpReview:
+		if op.Correct == nil {
+			return false, false
+		}
+		return *op.Correct, true
+	}
+	return false, false
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/dto"
+)
+
+const (
+	syncUserID = "5b0c7c1e-8a47-4c57-9a3e-2f1f6d0b9a11"
+	syncWordID = "0f6a2d3c-1b4e-4f5a-8c7d-9e0f1a2b3c4d"
+	syncTagID  = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+// notAfterNow matches unix millisecond timestamps that are not in the future
+type notAfterNow struct{}
+
+func (notAfterNow) Match(v driver.Value) bool {
+	ts, ok := v.(int64)
+	return ok && ts <= time.Now().UnixMilli()
+}
+
+var wordTagColumns = []string{"id", "word_id", "user_id", "known", "changed_at", "created_at", "updated_at"}
+
+func newTestSyncService(t *testing.T) (*SyncService, sqlmock.Sqlmock) {
+	t.Helper()
+	mock := daotest.Mock(t)
+	return NewSyncService(dao.NewSyncOperationDAO(), dao.NewWordDAO(), &WordTagService{}), mock
+}
+
+// expectSyncedWord expects the lookup of the synced word, which exists
+func expectSyncedWord(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT \* FROM "words" WHERE id IN `).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(syncWordID))
+}
+
+// expectOperation expects the operation to be recorded, or found recorded before
+func expectOperation(mock sqlmock.Sqlmock, opID string, duplicate bool) {
+	mock.ExpectBegin()
+	rows := int64(1)
+	if duplicate {
+		rows = 0
+	}
+	mock.ExpectExec(`INSERT INTO "sync_operations" .* ON CONFLICT DO NOTHING`).
+		WithArgs(syncUserID, opID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, rows))
+}
+
+// expectCurrentTag expects the word tag to be locked and returns it
+func expectCurrentTag(mock sqlmock.Sqlmock, known interface{}, changedAt int64) {
+	mock.ExpectQuery(`SELECT \* FROM "word_tags" WHERE word_id = .* AND user_id = .* FOR UPDATE`).
+		WithArgs(syncWordID, syncUserID, 1).
+		WillReturnRows(sqlmock.NewRows(wordTagColumns).AddRow(syncTagID, syncWordID, syncUserID, known, changedAt, changedAt, changedAt))
+}
+
+// expectNoChanges expects the listing of changes to find none
+func expectNoChanges(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT \* FROM "word_tags" WHERE user_id = .* AND updated_at < `).
+		WillReturnRows(sqlmock.NewRows(wordTagColumns))
+}
+
+func syncOp(id, opType string, timestamp int64) dto.SyncOperation {
+	return dto.SyncOperation{ID: id, Type: opType, WordID: syncWordID, Timestamp: timestamp}
+}
+
+func TestSyncDuplicateOperation(t *testing.T) {
+	s, mock := newTestSyncService(t)
+	expectSyncedWord(mock)
+	expectOperation(mock, "op-1", true)
+	mock.ExpectCommit()
+	expectNoChanges(mock)
+
+	resp, err := s.Sync(syncUserID, &dto.SyncRequest{Operations: []dto.SyncOperation{syncOp("op-1", dto.SyncOpMark, 1700000000000)}})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if resp.Results[0].Status != dto.SyncStatusDuplicate {
+		t.Errorf("status = %q, want %q", resp.Results[0].Status, dto.SyncStatusDuplicate)
+	}
+}
+
+func TestSyncOlderOperationIsStale(t *testing.T) {
+	s, mock := newTestSyncService(t)
+	expectSyncedWord(mock)
+	expectOperation(mock, "op-1", false)
+	expectCurrentTag(mock, int64(1700000002000), 1700000002000)
+	mock.ExpectCommit()
+	expectNoChanges(mock)
+
+	resp, err := s.Sync(syncUserID, &dto.SyncRequest{Operations: []dto.SyncOperation{syncOp("op-1", dto.SyncOpUnmark, 1700000001000)}})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if resp.Results[0].Status != dto.SyncStatusStale {
+		t.Errorf("status = %q, want %q", resp.Results[0].Status, dto.SyncStatusStale)
+	}
+}
+
+func TestSyncClampsFutureTimestamp(t *testing.T) {
+	s, mock := newTestSyncService(t)
+	expectSyncedWord(mock)
+	expectOperation(mock, "op-1", false)
+	expectCurrentTag(mock, nil, 1700000000000)
+	mock.ExpectExec(`UPDATE "word_tags" SET`).
+		WithArgs(syncWordID, syncUserID, notAfterNow{}, notAfterNow{}, sqlmock.AnyArg(), sqlmock.AnyArg(), syncTagID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectNoChanges(mock)
+
+	future := time.Now().Add(time.Hour).UnixMilli()
+	resp, err := s.Sync(syncUserID, &dto.SyncRequest{Operations: []dto.SyncOperation{syncOp("op-1", dto.SyncOpMark, future)}})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if resp.Results[0].Status != dto.SyncStatusApplied {
+		t.Errorf("status = %q, want %q", resp.Results[0].Status, dto.SyncStatusApplied)
+	}
+}
+
+func TestSyncMarkKeepsFirstKnownTime(t *testing.T) {
+	s, mock := newTestSyncService(t)
+	expectSyncedWord(mock)
+	expectOperation(mock, "op-1", false)
+	expectCurrentTag(mock, int64(1700000000000), 1700000000000)
+	mock.ExpectExec(`UPDATE "word_tags" SET`).
+		WithArgs(syncWordID, syncUserID, int64(1700000000000), int64(1700000005000), sqlmock.AnyArg(), sqlmock.AnyArg(), syncTagID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectNoChanges(mock)
+
+	resp, err := s.Sync(syncUserID, &dto.SyncRequest{Operations: []dto.SyncOperation{syncOp("op-1", dto.SyncOpMark, 1700000005000)}})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if resp.Results[0].Status != dto.SyncStatusApplied {
+		t.Errorf("status = %q, want %q", resp.Results[0].Status, dto.SyncStatusApplied)
+	}
+}
+
+func TestSyncPagesChangesWithToken(t *testing.T) {
+	s, mock := newTestSyncService(t)
+	after := dao.SyncKey{UpdatedAt: 1700000000000, ID: syncTagID}
+
+	rows := sqlmock.NewRows(wordTagColumns)
+	for i := 0; i <= syncChangeLimit; i++ {
+		updatedAt := int64(1700000001000 + i)
+		rows.AddRow(syncTagID, syncWordID, syncUserID, updatedAt, updatedAt, updatedAt, updatedAt)
+	}
+	mock.ExpectQuery(`SELECT \* FROM "word_tags" WHERE \(user_id = .* AND updated_at < .*\) AND \(updated_at, id\) > \(.*\) ORDER BY updated_at, id LIMIT`).
+		WithArgs(syncUserID, notAfterNow{}, after.UpdatedAt, after.ID, syncChangeLimit+1).
+		WillReturnRows(rows)
+
+	resp, err := s.Sync(syncUserID, &dto.SyncRequest{SyncToken: dao.EncodeCursor(after)})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(resp.Changes) != syncChangeLimit || !resp.HasMore {
+		t.Fatalf("Sync() returned %d changes, hasMore %v, want %d and more", len(resp.Changes), resp.HasMore, syncChangeLimit)
+	}
+
+	var next dao.SyncKey
+	if ok, err := dao.DecodeCursor(resp.SyncToken, &next); err != nil || !ok {
+		t.Fatalf("DecodeCursor(%q) = %v, %v", resp.SyncToken, ok, err)
+	}
+	if last := resp.Changes[len(resp.Changes)-1]; next.UpdatedAt != last.ChangedAt || next.ID != syncTagID {
+		t.Errorf("next token = %+v, want the position of the last returned change", next)
+	}
+}
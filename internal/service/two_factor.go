@@ -0,0 +1,313 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+const (
+	// totpIssuer is the account issuer shown in authenticator apps
+	totpIssuer = "Word Hero"
+	// loginChallengeTTL is how long a user has to enter their 2FA code after the password
+	loginChallengeTTL = 5 * time.Minute
+	// recoveryCodeCount is the number of recovery codes issued at a time
+	recoveryCodeCount = 10
+	// qrCodeSize is the width and height of the enrollment QR code in pixels
+	qrCodeSize = 256
+)
+
+// Login2FA completes a login started with a password by checking a TOTP or
+// recovery code against the challenge token issued by Login
+func (s *AuthService) Login2FA(req *dto.Login2FARequest) (*dto.LoginResult, error) {
+	record, err := s.userTokenDAO.FindByHash(table.TokenPurposeLogin2FA, utils.HashToken(req.ChallengeToken))
+	if err != nil || record.UsedAt != nil {
+		return nil, pke.NewApiError(pke.CodeInvalidToken)
+	}
+	if time.Now().UnixMilli() > record.ExpiresAt {
+		return nil, pke.NewApiError(pke.CodeTokenExpired)
+	}
+
+	user, err := s.userDAO.FindByID(record.UserID)
+	if err != nil || !user.IsActive || user.TOTPEnabledAt == nil {
+		return nil, pke.NewApiError(pke.CodeInvalidToken)
+	}
+
+	// Code guesses count against the same limits and lockout as passwords
+	if err := s.loginGuard.Allow(req.ClientIP, user.Username); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.verifySecondFactor(user, req.Code, true)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.loginGuard.Fail(req.ClientIP, user.Username); err != nil {
+			return nil, err
+		}
+		return nil, pke.NewApiError(pke.CodeInvalidTwoFactorCode)
+	}
+
+	consumed, err := s.userTokenDAO.Consume(record.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume login challenge: %w", err)
+	}
+	if !consumed {
+		return nil, pke.NewApiError(pke.CodeInvalidToken)
+	}
+	s.loginGuard.Succeed(user.Username)
+
+	return s.completeLogin(user)
+}
+
+// Setup2FA starts TOTP enrollment by generating a new secret. 2FA is not
+// active until the user confirms a first code with Enable2FA.
+func (s *AuthService) Setup2FA(userID string) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.userDAO.FindByID(userID)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUserNotFound)
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, pke.NewApiError(pke.CodeTwoFactorAlreadyEnabled)
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userDAO.SetTOTPSecret(user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	uri := utils.TOTPURI(totpIssuer, user.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate qr code: %w", err)
+	}
+
+	log.Info().Str("user_id", user.ID).Msg("TOTP enrollment started")
+	return &dto.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// Enable2FA confirms enrollment with a first code and returns the recovery codes
+func (s *AuthService) Enable2FA(userID, code string) ([]string, error) {
+	user, err := s.userDAO.FindByID(userID)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUserNotFound)
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, pke.NewApiError(pke.CodeTwoFactorAlreadyEnabled)
+	}
+	if user.TOTPSecret == "" {
+		return nil, pke.NewApiError(pke.CodeInvalidOperation)
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, pke.NewApiError(pke.CodeInvalidTwoFactorCode)
+	}
+
+	codes, err := s.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userDAO.EnableTOTP(user.ID, time.Now().UnixMilli(), step); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable2FA turns 2FA off after checking the password and a current code
+func (s *AuthService) Disable2FA(userID string, req *dto.TwoFactorDisableRequest) error {
+	user, err := s.userDAO.FindByID(userID)
+	if err != nil {
+		return pke.NewApiError(pke.CodeUserNotFound)
+	}
+	if user.TOTPEnabledAt == nil {
+		return pke.NewApiError(pke.CodeTwoFactorNotEnabled)
+	}
+	if s.twoFactorRequired(user) {
+		return pke.NewApiError(pke.CodeTwoFactorSetupRequired)
+	}
+
+	if !s.verifyPassword(user, req.Password) {
+		return pke.NewApiError(pke.CodeInvalidCredentials)
+	}
+	ok, err := s.verifySecondFactor(user, req.Code, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return pke.NewApiError(pke.CodeInvalidTwoFactorCode)
+	}
+
+	if err := s.userDAO.DisableTOTP(user.ID); err != nil {
+		return err
+	}
+	return s.recoveryCodeDAO.DeleteAll(user.ID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a TOTP code
+func (s *AuthService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	user, err := s.userDAO.FindByID(userID)
+	if err != nil {
+		return nil, pke.NewApiError(pke.CodeUserNotFound)
+	}
+	if user.TOTPEnabledAt == nil {
+		return nil, pke.NewApiError(pke.CodeTwoFactorNotEnabled)
+	}
+
+	ok, err := s.verifySecondFactor(user, code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, pke.NewApiError(pke.CodeInvalidTwoFactorCode)
+	}
+	return s.replaceRecoveryCodes(user.ID)
+}
+
+// Get2FAStatus returns the 2FA state of a user
+func (s *AuthService) Get2FAStatus(user *table.User) (*dto.TwoFactorStatusResponse, error) {
+	status := &dto.TwoFactorStatusResponse{
+		Enabled:  user.TOTPEnabledAt != nil,
+		Required: s.twoFactorRequired(user),
+	}
+	if status.Enabled {
+		remaining, err := s.recoveryCodeDAO.CountUnused(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		status.RecoveryCodesRemaining = remaining
+	}
+	return status, nil
+}
+
+// TwoFactorSetupRequired reports whether a user must enroll in 2FA before
+// using the rest of the API
+func (s *AuthService) TwoFactorSetupRequired(user *table.User) bool {
+	return user.TOTPEnabledAt == nil && s.twoFactorRequired(user)
+}
+
+// GetSecuritySettings returns the security settings administrators can change
+func (s *AuthService) GetSecuritySettings() (*dto.SecuritySettings, error) {
+	required, err := s.settingDAO.GetBool(table.SettingRequire2FAForAdmin, false)
+	if err != nil {
+		return nil, err
+	}
+	linkLDAP, err := s.settingDAO.GetBool(table.SettingLDAPLinkByEmail, false)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SecuritySettings{Require2FAForAdmin: &required, LDAPLinkByEmail: &linkLDAP}, nil
+}
+
+// UpdateSecuritySettings saves the security settings included in the request
+// and leaves the others unchanged
+func (s *AuthService) UpdateSecuritySettings(settings *dto.SecuritySettings) error {
+	updates := []struct {
+		key   string
+		value *bool
+	}{
+		{table.SettingRequire2FAForAdmin, settings.Require2FAForAdmin},
+		{table.SettingLDAPLinkByEmail, settings.LDAPLinkByEmail},
+	}
+	for _, update := range updates {
+		if update.value == nil {
+			continue
+		}
+		if err := s.settingDAO.Set(update.key, strconv.FormatBool(*update.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// twoFactorRequired reports whether policy requires 2FA for the user's role.
+// It fails closed so a settings lookup error never relaxes the policy.
+func (s *AuthService) twoFactorRequired(user *table.User) bool {
+	if user.Role != "admin" {
+		return false
+	}
+	required, err := s.settingDAO.GetBool(table.SettingRequire2FAForAdmin, false)
+	if err != nil {
+		log.Error(err).Str("user_id", user.ID).Msg("Failed to load 2FA policy")
+		return true
+	}
+	return required
+}
+
+// issueLoginChallenge stores a short-lived single-use token that lets the
+// user finish logging in with a second factor
+func (s *AuthService) issueLoginChallenge(user *table.User) (string, error) {
+	token := utils.GenerateRandomString(resetTokenLength)
+	if err := s.userTokenDAO.Create(&table.UserToken{
+		UserID:    user.ID,
+		Purpose:   table.TokenPurposeLogin2FA,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(loginChallengeTTL).UnixMilli(),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// verifySecondFactor checks a TOTP code, or a recovery code when allowed.
+// Each TOTP code and recovery code is accepted only once.
+func (s *AuthService) verifySecondFactor(user *table.User, code string, allowRecovery bool) (bool, error) {
+	code = strings.TrimSpace(code)
+	if step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		return s.userDAO.UseTOTPStep(user.ID, step)
+	}
+	if !allowRecovery {
+		return false, nil
+	}
+
+	used, err := s.recoveryCodeDAO.Consume(user.ID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, err
+	}
+	if used {
+		log.Warn().Str("user_id", user.ID).Msg("Recovery code used")
+	}
+	return used, nil
+}
+
+// replaceRecoveryCodes generates a new set of recovery codes for a user,
+// stores their hashes and returns the plain codes
+func (s *AuthService) replaceRecoveryCodes(userID string) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := strings.ToLower(utils.GenerateRandomString(10))
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = utils.HashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	if err := s.recoveryCodeDAO.Replace(userID, hashes); err != nil {
+		return nil, err
+	}
+	log.Info().Str("user_id", userID).Msg("Recovery codes generated")
+	return codes, nil
+}
+
+// normalizeRecoveryCode makes recovery codes case and separator insensitive
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dao/daotest"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+	"github.com/sanmu2018/word-hero/pkg/ratelimit"
+)
+
+// stubAuthenticator accepts one password for a fixed user
+type stubAuthenticator struct {
+	user     *table.User
+	password string
+}
+
+func (a *stubAuthenticator) Name() string {
+	return "stub"
+}
+
+func (a *stubAuthenticator) Authenticate(username, password string) (*table.User, error) {
+	if username != a.user.Username || password != a.password {
+		return nil, ErrInvalidCredentials
+	}
+	copied := *a.user
+	return &copied, nil
+}
+
+// newTestLoginGuard locks an account after three failures and never rate
+// limits, so tests only see the lockout
+func newTestLoginGuard(t *testing.T) *LoginGuard {
+	t.Helper()
+	guard, err := NewLoginGuard(ratelimit.NewMemoryStore(), &conf.LoginLimitConfig{
+		IPPerMinute:       1000,
+		IPBurst:           1000,
+		UsernamePerMinute: 1000,
+		UsernameBurst:     1000,
+		MaxFailures:       3,
+		FailureWindow:     "15m",
+		LockoutBase:       "1m",
+		LockoutMax:        "1h",
+	})
+	if err != nil {
+		t.Fatalf("NewLoginGuard() error = %v", err)
+	}
+	return guard
+}
+
+func TestLogin2FAGuessesLockAccountDespitePasswordLogins(t *testing.T) {
+	mock := daotest.Mock(t)
+	enabledAt := time.Now().UnixMilli()
+	alice := &table.User{ID: "user-1", Username: "alice", Role: "user", IsActive: true, TOTPEnabledAt: &enabledAt, TOTPSecret: "JBSWY3DPEHPK3PXP"}
+
+	s := &AuthService{
+		userDAO:         dao.NewUserDAO(),
+		userTokenDAO:    dao.NewUserTokenDAO(),
+		recoveryCodeDAO: dao.NewRecoveryCodeDAO(),
+		loginGuard:      newTestLoginGuard(t),
+		authConfig:      &conf.AuthConfig{},
+	}
+	s.SetAuthenticators(&stubAuthenticator{user: alice, password: "secret"})
+
+	var err error
+	for round := 1; round <= 3; round++ {
+		mock.ExpectQuery(`INSERT INTO "user_tokens" .*`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("challenge"))
+		var result *dto.LoginResult
+		result, err = s.Login(&dto.UserLoginRequest{Username: "alice", Password: "secret", ClientIP: "10.0.0.1"})
+		if err != nil || result.ChallengeToken == "" {
+			t.Fatalf("round %d: Login() = %+v, %v, want a 2FA challenge", round, result, err)
+		}
+
+		mock.ExpectQuery(`SELECT \* FROM "user_tokens" WHERE purpose = .* AND token_hash = `).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "purpose", "expires_at"}).
+				AddRow("challenge", "user-1", table.TokenPurposeLogin2FA, time.Now().Add(time.Minute).UnixMilli()))
+		mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = `).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username", "is_active", "totp_secret", "totp_enabled_at"}).
+				AddRow("user-1", "alice", true, alice.TOTPSecret, enabledAt))
+		mock.ExpectExec(`UPDATE "user_recovery_codes" SET "used_at"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		_, err = s.Login2FA(&dto.Login2FARequest{ChallengeToken: result.ChallengeToken, Code: "wrong", ClientIP: "10.0.0.2"})
+		if round < 3 && errorNo(err) != pke.CodeInvalidTwoFactorCode {
+			t.Fatalf("round %d: Login2FA() error = %v, want CodeInvalidTwoFactorCode", round, err)
+		}
+	}
+	if errorNo(err) != pke.CodeAccountLocked {
+		t.Fatalf("third wrong code: Login2FA() error = %v, want CodeAccountLocked", err)
+	}
+
+	if _, err := s.Login(&dto.UserLoginRequest{Username: "alice", Password: "secret", ClientIP: "10.0.0.3"}); errorNo(err) != pke.CodeAccountLocked {
+		t.Errorf("Login() after lockout error = %v, want CodeAccountLocked", err)
+	}
+}
+
+func TestUpdateSecuritySettingsKeepsOmittedSettings(t *testing.T) {
+	mock := daotest.Mock(t)
+	s := &AuthService{settingDAO: dao.NewSettingDAO()}
+
+	required := true
+	mock.ExpectExec(`INSERT INTO "app_settings" .* ON CONFLICT \("key"\) DO UPDATE`).
+		WithArgs(table.SettingRequire2FAForAdmin, "true", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := s.UpdateSecuritySettings(&dto.SecuritySettings{Require2FAForAdmin: &required}); err != nil {
+		t.Fatalf("UpdateSecuritySettings() error = %v", err)
+	}
+}
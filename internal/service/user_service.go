@@ -25,7 +25,7 @@ func NewUserService(userDAO *dao.UserDAO) *UserService {
 func (s *UserService) GetUserProfile(userID string) (*dto.UserResponse, error) {
 	user, err := s.userDAO.FindByID(userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user profile: %w", err)
+		return nil, fmt.Errorf("failed to get user profile: %w", notFound(ErrUserNotFound, err))
 	}
 
 	businessUser := models.NewUserBusiness(user)
@@ -37,7 +37,7 @@ func (s *UserService) GetUserProfile(userID string) (*dto.UserResponse, error) {
 func (s *UserService) UpdateUserProfile(userID string, req *dto.UserUpdateRequest) (*dto.UserResponse, error) {
 	user, err := s.userDAO.UpdateProfile(userID, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user profile: %w", err)
+		return nil, fmt.Errorf("failed to update user profile: %w", notFound(ErrUserNotFound, err))
 	}
 
 	businessUser := models.NewUserBusiness(user)
@@ -86,7 +86,7 @@ func (s *UserService) DeleteUser(userID string) error {
 func (s *UserService) ActivateUser(userID string) error {
 	user, err := s.userDAO.FindByID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
+		return fmt.Errorf("failed to find user: %w", notFound(ErrUserNotFound, err))
 	}
 
 	user.IsActive = true
@@ -97,7 +97,7 @@ func (s *UserService) ActivateUser(userID string) error {
 func (s *UserService) DeactivateUser(userID string) error {
 	user, err := s.userDAO.FindByID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
+		return fmt.Errorf("failed to find user: %w", notFound(ErrUserNotFound, err))
 	}
 
 	user.IsActive = false
@@ -108,7 +108,7 @@ func (s *UserService) DeactivateUser(userID string) error {
 func (s *UserService) PromoteToAdmin(userID string) error {
 	user, err := s.userDAO.FindByID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
+		return fmt.Errorf("failed to find user: %w", notFound(ErrUserNotFound, err))
 	}
 
 	user.Role = "admin"
@@ -119,7 +119,7 @@ func (s *UserService) PromoteToAdmin(userID string) error {
 func (s *UserService) DemoteFromUser(userID string) error {
 	user, err := s.userDAO.FindByID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
+		return fmt.Errorf("failed to find user: %w", notFound(ErrUserNotFound, err))
 	}
 
 	user.Role = "user"
@@ -2,17 +2,23 @@ package service
 
 import (
 	"fmt"
+	"html"
+	"strings"
 
 	"github.com/sanmu2018/word-hero/internal/dao"
 	"github.com/sanmu2018/word-hero/internal/dto"
 	"github.com/sanmu2018/word-hero/internal/table"
+	"github.com/sanmu2018/word-hero/internal/utils"
 	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/filterql"
+	"github.com/sanmu2018/word-hero/pkg/lemma"
 )
 
 // VocabularyService handles vocabulary-related business logic
 type VocabularyService struct {
-	wordDAO    *dao.WordDAO
-	wordTagDAO *dao.WordTagDAO
+	wordDAO        *dao.WordDAO
+	wordTagDAO     *dao.WordTagDAO
+	suggestService *SuggestService
 }
 
 // NewVocabularyService creates a new vocabulary service instance
@@ -25,6 +31,18 @@ func NewVocabularyService(wordDAO *dao.WordDAO, wordTagDAO *dao.WordTagDAO) *Voc
 	}
 }
 
+// SetSuggestService sets the suggest index to refresh when words change
+func (vs *VocabularyService) SetSuggestService(suggestService *SuggestService) {
+	vs.suggestService = suggestService
+}
+
+// wordsChanged refreshes derived indexes after a word was changed
+func (vs *VocabularyService) wordsChanged() {
+	if vs.suggestService != nil {
+		vs.suggestService.Invalidate()
+	}
+}
+
 // GetWordsByPage returns words for a specific page using BaseList
 func (vs *VocabularyService) GetWordsByPage(baseList *dao.BaseList) (*dto.VocabularyPage, error) {
 	// baseList can be nil, meaning no pagination (return all data)
@@ -42,6 +60,20 @@ func (vs *VocabularyService) GetWordsByPage(baseList *dao.BaseList) (*dto.Vocabu
 	}, nil
 }
 
+// ListWords returns the words passing filter for a specific page
+func (vs *VocabularyService) ListWords(baseList *dao.BaseList, filter *dao.WordFilter) (*dto.VocabularyPage, error) {
+	words, totalCount, next, err := vs.wordDAO.ListWords(baseList, filter)
+	if err != nil {
+		return nil, pagingError(err)
+	}
+
+	return &dto.VocabularyPage{
+		Words:      words,
+		TotalCount: totalCount,
+		NextCursor: next,
+	}, nil
+}
+
 // GetWordsByPageLegacy 保持向后兼容的旧版本方法
 func (vs *VocabularyService) GetWordsByPageLegacy(pageNumber, pageSize int) (*dto.VocabularyPage, error) {
 	baseList := &dao.BaseList{
@@ -51,35 +83,79 @@ func (vs *VocabularyService) GetWordsByPageLegacy(pageNumber, pageSize int) (*dt
 	return vs.GetWordsByPage(baseList)
 }
 
-// SearchWords searches for words matching the query in English and Chinese
-func (vs *VocabularyService) SearchWords(param dto.WordSearchRequest) (int64, []table.Word, error) {
+// SearchWords searches for words matching the query in English and Chinese.
+// Filter terms such as "cat:noun" may be part of the query; userID is the
+// owner of the progress that known and learned filters refer to. It returns
+// the total, one page of results and the cursor of the next page.
+func (vs *VocabularyService) SearchWords(param dto.WordSearchRequest, userID string) (int64, []dto.WordSearchResult, string, error) {
+	var terms []filterql.Term
+	if parsed, err := filterql.Parse(param.Q); err == nil && len(parsed.Terms) > 0 {
+		terms, param.Q = parsed.Terms, strings.Join(parsed.Text, " ")
+	}
+	filter, err := vs.BuildWordFilter(param.WordFilterParams, terms, userID)
+	if err != nil {
+		return 0, nil, "", err
+	}
+
 	query := param.Q
 	if len(query) < 2 {
-		return 0, []table.Word{}, nil
+		return 0, []dto.WordSearchResult{}, "", nil
 	}
 
-	total, words, err := vs.wordDAO.SearchWords(param)
+	irregular, derived := lemma.Lemmas(query)
+	total, results, next, err := vs.wordDAO.SearchWords(param, irregular, derived, filter)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed to search words: %w", err)
+		return 0, nil, "", pagingError(err)
+	}
+	for i := range results {
+		annotateSearchResult(&results[i], query)
 	}
 
-	log.Info().Str("query", query).Int("results", len(words)).Msg("Database search completed")
-	return total, words, nil
+	log.Info().Str("query", query).Int("results", len(results)).Msg("Database search completed")
+	return total, results, next, nil
 }
 
-// SearchWordsWithRegex searches for words using regex patterns
-func (vs *VocabularyService) SearchWordsWithRegex(query string) ([]table.Word, error) {
-	if len(query) < 2 {
-		return []table.Word{}, nil
-	}
-
-	words, err := vs.wordDAO.SearchWordsWithRegex(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search words with regex: %w", err)
+// annotateSearchResult fills in the match type and highlight of a search hit
+func annotateSearchResult(result *dto.WordSearchResult, query string) {
+	result.MatchType = dto.MatchFuzzy
+	if result.MatchRank >= 0 && result.MatchRank < len(dto.MatchTypes) {
+		result.MatchType = dto.MatchTypes[result.MatchRank]
+	}
+	if result.MatchType == dto.MatchLemma {
+		// The headword itself is the match for the inflected form searched
+		result.MatchedForm = strings.ToLower(query)
+		result.Highlight, result.HighlightField = "<mark>"+html.EscapeString(result.English)+"</mark>", "english"
+		return
+	}
+
+	if highlight, ok := highlightMatch(result.English, query); ok {
+		result.Highlight, result.HighlightField = highlight, "english"
+	} else if highlight, ok := highlightMatch(result.Chinese, query); ok {
+		result.Highlight, result.HighlightField = highlight, "chinese"
+	} else if gloss, ok := utils.MatchPinyinGloss(result.Chinese, query); ok {
+		highlight, _ := highlightMatch(result.Chinese, gloss)
+		result.Highlight, result.HighlightField = highlight, "chinese"
+	} else {
+		// Fuzzy hits have no literal fragment; the whole word is the match
+		result.Highlight, result.HighlightField = "<mark>"+html.EscapeString(result.English)+"</mark>", "english"
 	}
+}
 
-	log.Info().Str("query", query).Int("results", len(words)).Msg("Regex search completed")
-	return words, nil
+// highlightMatch returns text HTML-escaped with the first case-insensitive
+// occurrence of query wrapped in <mark>
+func highlightMatch(text, query string) (string, bool) {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Lowercasing changed byte offsets; only an exact-case match is safe
+		lower = text
+	}
+	needle := strings.ToLower(query)
+	start := strings.Index(lower, needle)
+	if start < 0 {
+		return "", false
+	}
+	end := start + len(needle)
+	return html.EscapeString(text[:start]) + "<mark>" + html.EscapeString(text[start:end]) + "</mark>" + html.EscapeString(text[end:]), true
 }
 
 // GetWordByEnglish finds a word by its English text
@@ -246,22 +322,54 @@ func (vs *VocabularyService) GetAllDifficultyLevels() ([]string, error) {
 
 // CreateWord adds a new word to the database
 func (vs *VocabularyService) CreateWord(word *table.Word) error {
-	return vs.wordDAO.Create(word)
+	if err := vs.wordDAO.Create(word); err != nil {
+		return err
+	}
+	vs.wordsChanged()
+	return nil
 }
 
 // UpdateWord updates an existing word in the database
 func (vs *VocabularyService) UpdateWord(word *table.Word) error {
-	return vs.wordDAO.Update(word)
+	if err := vs.wordDAO.Update(word); err != nil {
+		return err
+	}
+	vs.wordsChanged()
+	return nil
 }
 
 // DeleteWord deletes a word from the database
 func (vs *VocabularyService) DeleteWord(id string) error {
-	return vs.wordDAO.Delete(id)
+	if err := vs.wordDAO.Delete(id); err != nil {
+		return err
+	}
+	vs.wordsChanged()
+	return nil
 }
 
 // GetWordByID retrieves a word by ID
 func (vs *VocabularyService) GetWordByID(id string) (*table.Word, error) {
-	return vs.wordDAO.GetByID(id)
+	word, err := vs.wordDAO.GetByID(id)
+	if err != nil {
+		return nil, notFound(ErrWordNotFound, err)
+	}
+	return word, nil
+}
+
+// GetWordsByIDs retrieves several words by ID in a single query. Words that
+// do not exist are left out.
+func (vs *VocabularyService) GetWordsByIDs(ids []string) ([]table.Word, error) {
+	words, err := vs.wordDAO.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// CountWordsBy returns the number of words by category or by difficulty,
+// most frequent first
+func (vs *VocabularyService) CountWordsBy(field string) ([]dto.FacetCount, error) {
+	return vs.wordDAO.CountBy(field)
 }
 
 // GetWordsByPageWithMarks returns words with mark status for a specific page
@@ -381,9 +489,9 @@ func (vs *VocabularyService) GetRandomWordsWithMarks(count int, userID string) (
 // GetKnownWordsByUser returns known words for a user with full word details
 func (vs *VocabularyService) GetKnownWordsByUser(userID string, baseList *dao.BaseList) (*dto.VocabularyPageWithMarks, error) {
 	// Get known word IDs
-	wordIDs, totalCount, err := vs.wordTagDAO.GetKnownWords(userID, baseList)
+	wordIDs, totalCount, next, err := vs.wordTagDAO.GetKnownWords(userID, baseList)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get known words: %w", err)
+		return nil, pagingError(err)
 	}
 
 	// Get full word details in a single query
@@ -398,6 +506,16 @@ func (vs *VocabularyService) GetKnownWordsByUser(userID string, baseList *dao.Ba
 		wordMap[word.ID] = word
 	}
 
+	// Get the marked timestamps in a single query
+	wordTags, err := vs.wordTagDAO.GetKnownByWordIDs(userID, wordIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word tags: %w", err)
+	}
+	markedAt := make(map[string]int64, len(wordTags))
+	for _, wordTag := range wordTags {
+		markedAt[wordTag.WordID] = wordTag.GetKnownTimestamp()
+	}
+
 	// Convert to words with mark status, maintaining the original order from wordIDs
 	wordsWithMarks := make([]dto.WordWithMarkStatus, 0, len(wordIDs))
 	for _, wordID := range wordIDs {
@@ -406,14 +524,8 @@ func (vs *VocabularyService) GetKnownWordsByUser(userID string, baseList *dao.Ba
 				Word:      word,
 				IsMarked:  true, // These are known words, so they should be marked
 				MarkCount: 1,    // At least the current user marked it
+				MarkedAt:  markedAt[word.ID],
 			}
-
-			// Get marked timestamp from word tag
-			wordTag, err := vs.wordTagDAO.GetByWordIDAndUserID(word.ID, userID)
-			if err == nil && wordTag.IsKnown() {
-				wordWithMark.MarkedAt = wordTag.GetKnownTimestamp()
-			}
-
 			wordsWithMarks = append(wordsWithMarks, wordWithMark)
 		}
 	}
@@ -434,6 +546,7 @@ func (vs *VocabularyService) GetKnownWordsByUser(userID string, baseList *dao.Ba
 		PageNumber: pageNumber,
 		PageSize:   pageSize,
 		TotalPages: totalPages,
+		NextCursor: next,
 	}, nil
 }
 
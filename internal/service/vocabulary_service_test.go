@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/internal/table"
+)
+
+func TestHighlightMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+		ok    bool
+	}{
+		{"plain", "apple", "pp", "a<mark>pp</mark>le", true},
+		{"case folding", "Apple Pie", "apple", "<mark>Apple</mark> Pie", true},
+		{"upper case query", "apple", "APP", "<mark>app</mark>le", true},
+		{"escapes around the match", `<b>&"bold"`, "bold", `&lt;b&gt;&amp;&#34;<mark>bold</mark>&#34;`, true},
+		{"escapes inside the match", "a<b>c", "<b>", "a<mark>&lt;b&gt;</mark>c", true},
+		{"query is not markup", "<mark>x</mark>", "x", "&lt;mark&gt;<mark>x</mark>&lt;/mark&gt;", true},
+		{"multi-byte text", "选择，挑选", "挑选", "选择，<mark>挑选</mark>", true},
+		{"mixed scripts", "café 咖啡", "咖", "café <mark>咖</mark>啡", true},
+		// Lowercasing İ changes its length, so only exact-case matches are safe
+		{"length-changing case", "İstanbul", "stan", "İ<mark>stan</mark>bul", true},
+		{"length-changing case miss", "İstanbul", "istan", "", false},
+		{"no match", "apple", "pear", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := highlightMatch(test.text, test.query)
+			if got != test.want || ok != test.ok {
+				t.Errorf("highlightMatch(%q, %q) = %q, %v, want %q, %v", test.text, test.query, got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestAnnotateSearchResult(t *testing.T) {
+	word := func(english, chinese string) table.Word {
+		return table.Word{English: english, Chinese: chinese}
+	}
+	tests := []struct {
+		name      string
+		result    dto.WordSearchResult
+		query     string
+		matchType string
+		highlight string
+		field     string
+		form      string
+	}{
+		{"exact", dto.WordSearchResult{Word: word("Run", "跑"), MatchRank: 0}, "run", dto.MatchExact, "<mark>Run</mark>", "english", ""},
+		{"irregular lemma", dto.WordSearchResult{Word: word("go", "去"), MatchRank: 1}, "Went", dto.MatchLemma, "<mark>go</mark>", "english", "went"},
+		{"prefix", dto.WordSearchResult{Word: word("running", "跑步"), MatchRank: 2}, "run", dto.MatchPrefix, "<mark>run</mark>ning", "english", ""},
+		{"derived lemma", dto.WordSearchResult{Word: word("new", "新的"), MatchRank: 3}, "news", dto.MatchLemma, "<mark>new</mark>", "english", "news"},
+		{"contains in chinese", dto.WordSearchResult{Word: word("choose", "选择"), MatchRank: 4}, "选择", dto.MatchContains, "<mark>选择</mark>", "chinese", ""},
+		{"fuzzy escapes the word", dto.WordSearchResult{Word: word("a<b", "小于"), MatchRank: 6}, "axb", dto.MatchFuzzy, "<mark>a&lt;b</mark>", "english", ""},
+		{"unknown rank", dto.WordSearchResult{Word: word("apple", "苹果"), MatchRank: 42}, "aple", dto.MatchFuzzy, "<mark>apple</mark>", "english", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.result
+			annotateSearchResult(&result, test.query)
+			if result.MatchType != test.matchType || result.Highlight != test.highlight || result.HighlightField != test.field || result.MatchedForm != test.form {
+				t.Errorf("annotateSearchResult() = %q %q %q %q, want %q %q %q %q",
+					result.MatchType, result.Highlight, result.HighlightField, result.MatchedForm,
+					test.matchType, test.highlight, test.field, test.form)
+			}
+		})
+	}
+}
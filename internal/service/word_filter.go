@@ -0,0 +1,223 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sanmu2018/word-hero/internal/dao"
+	"github.com/sanmu2018/word-hero/internal/dto"
+	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/filterql"
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// filterDateLayout is the date format of learned filters
+const filterDateLayout = "2006-01-02"
+
+// filterFields maps the accepted filter field names and aliases to the
+// canonical field; anything else is rejected
+var filterFields = map[string]string{
+	"cat":        "category",
+	"category":   "category",
+	"diff":       "difficulty",
+	"difficulty": "difficulty",
+	"known":      "known",
+	"learned":    "learned",
+	"len":        "length",
+	"length":     "length",
+}
+
+// BuildWordFilter turns filter query parameters and compact filter terms into
+// a word filter for userID, who may be empty for anonymous requests. Without
+// terms or a user the filter is nil.
+func (vs *VocabularyService) BuildWordFilter(params dto.WordFilterParams, extra []filterql.Term, userID string) (*dao.WordFilter, error) {
+	terms, err := paramTerms(params)
+	if err != nil {
+		return nil, invalidFilter(err)
+	}
+	terms = append(terms, extra...)
+	if len(terms) == 0 && userID == "" {
+		return nil, nil
+	}
+
+	filter := &dao.WordFilter{UserID: userID}
+	for _, term := range terms {
+		if err := applyFilterTerm(filter, term); err != nil {
+			return nil, invalidFilter(err)
+		}
+	}
+	if filter.NeedsUser() && userID == "" {
+		return nil, pke.NewApiError(pke.CodeLoginRequired)
+	}
+	return filter, nil
+}
+
+// paramTerms converts the separate filter query parameters to terms
+func paramTerms(params dto.WordFilterParams) ([]filterql.Term, error) {
+	var terms []filterql.Term
+	if params.Filter != "" {
+		query, err := filterql.Parse(params.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(query.Text) > 0 {
+			return nil, fmt.Errorf("unexpected text %q in filter", strings.Join(query.Text, " "))
+		}
+		terms = append(terms, query.Terms...)
+	}
+
+	add := func(field string, op filterql.Op, value string) {
+		if value != "" {
+			terms = append(terms, filterql.Term{Field: field, Op: op, Value: value})
+		}
+	}
+	add("category", filterql.OpEq, params.Category)
+	add("difficulty", filterql.OpEq, params.Difficulty)
+	add("known", filterql.OpEq, params.Known)
+	add("learned", filterql.OpGe, params.LearnedFrom)
+	add("learned", filterql.OpLe, params.LearnedTo)
+	if params.MinLength > 0 {
+		add("length", filterql.OpGe, strconv.Itoa(params.MinLength))
+	}
+	if params.MaxLength > 0 {
+		add("length", filterql.OpLe, strconv.Itoa(params.MaxLength))
+	}
+	return terms, nil
+}
+
+// applyFilterTerm adds one whitelisted term to filter
+func applyFilterTerm(filter *dao.WordFilter, term filterql.Term) error {
+	field, ok := filterFields[term.Field]
+	if !ok {
+		return fmt.Errorf("unknown filter field %q", term.Field)
+	}
+
+	switch field {
+	case "category":
+		return applyListTerm(&filter.Categories, &filter.ExcludedCategories, term)
+	case "difficulty":
+		return applyListTerm(&filter.Difficulties, &filter.ExcludedDifficulties, term)
+	case "known":
+		known, err := strconv.ParseBool(term.Value)
+		if err != nil || (term.Op != filterql.OpEq && term.Op != filterql.OpNe) {
+			return fmt.Errorf("known filter must be true or false")
+		}
+		if term.Op == filterql.OpNe {
+			known = !known
+		}
+		filter.Known = &known
+		return nil
+	case "learned":
+		return applyLearnedTerm(filter, term)
+	default:
+		return applyLengthTerm(filter, term)
+	}
+}
+
+// applyListTerm adds comma separated values to the included or excluded list
+func applyListTerm(include, exclude *[]string, term filterql.Term) error {
+	target := include
+	switch term.Op {
+	case filterql.OpEq:
+	case filterql.OpNe:
+		target = exclude
+	default:
+		return fmt.Errorf("%s filter only supports equality", term.Field)
+	}
+	for _, value := range strings.Split(term.Value, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			*target = append(*target, value)
+		}
+	}
+	return nil
+}
+
+// applyLearnedTerm narrows the learned date range; dates are whole days in
+// server local time
+func applyLearnedTerm(filter *dao.WordFilter, term filterql.Term) error {
+	day, err := time.ParseInLocation(filterDateLayout, term.Value, time.Local)
+	if err != nil {
+		return fmt.Errorf("learned filter needs a YYYY-MM-DD date")
+	}
+	start, end := day.UnixMilli(), day.AddDate(0, 0, 1).UnixMilli()
+
+	from, to := int64(0), int64(0)
+	switch term.Op {
+	case filterql.OpEq:
+		from, to = start, end
+	case filterql.OpGt:
+		from = end
+	case filterql.OpGe:
+		from = start
+	case filterql.OpLt:
+		to = start
+	case filterql.OpLe:
+		to = end
+	case filterql.OpRange:
+		last, err := time.ParseInLocation(filterDateLayout, term.To, time.Local)
+		if err != nil {
+			return fmt.Errorf("learned filter needs a YYYY-MM-DD date")
+		}
+		from, to = start, last.AddDate(0, 0, 1).UnixMilli()
+	default:
+		return fmt.Errorf("learned filter does not support %s", term.Op)
+	}
+
+	if from > filter.LearnedFrom {
+		filter.LearnedFrom = from
+	}
+	if to > 0 && (filter.LearnedTo == 0 || to < filter.LearnedTo) {
+		filter.LearnedTo = to
+	}
+	return nil
+}
+
+// applyLengthTerm narrows the headword length range
+func applyLengthTerm(filter *dao.WordFilter, term filterql.Term) error {
+	n, err := strconv.Atoi(term.Value)
+	if err != nil || n < 0 {
+		return fmt.Errorf("length filter needs a number")
+	}
+
+	min, max, hasMax := 0, 0, false
+	switch term.Op {
+	case filterql.OpEq:
+		min, max, hasMax = n, n, true
+	case filterql.OpGt:
+		min = n + 1
+	case filterql.OpGe:
+		min = n
+	case filterql.OpLt:
+		max, hasMax = n-1, true
+	case filterql.OpLe:
+		max, hasMax = n, true
+	case filterql.OpRange:
+		to, err := strconv.Atoi(term.To)
+		if err != nil || to < n {
+			return fmt.Errorf("length range needs two ascending numbers")
+		}
+		min, max, hasMax = n, to, true
+	default:
+		return fmt.Errorf("length filter does not support %s", term.Op)
+	}
+
+	if hasMax && max < 1 {
+		// No headword is that short; keep an empty but valid range
+		min, max = 2, 1
+	}
+	if min > filter.MinLength {
+		filter.MinLength = min
+	}
+	if hasMax && (filter.MaxLength == 0 || max < filter.MaxLength) {
+		filter.MaxLength = max
+	}
+	return nil
+}
+
+// invalidFilter logs why a filter was rejected and returns the API error
+func invalidFilter(err error) error {
+	log.Warn().Err(err).Msg("Rejected word filter")
+	return pke.NewApiError(pke.CodeInvalidRequest)
+}
@@ -2,11 +2,14 @@ package service
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/sanmu2018/word-hero/internal/dao"
 	"github.com/sanmu2018/word-hero/internal/dto"
 	"github.com/sanmu2018/word-hero/internal/table"
 	"github.com/sanmu2018/word-hero/log"
+	"github.com/sanmu2018/word-hero/pkg/i18n"
+	"github.com/sanmu2018/word-hero/pkg/pke"
 )
 
 // WordTagService handles word tagging business logic
@@ -14,35 +17,39 @@ type WordTagService struct {
 	wordTagDAO        *dao.WordTagDAO
 	wordDAO           *dao.WordDAO
 	userDAO           *dao.UserDAO
+	guestDAO          *dao.GuestDAO
 	vocabularyService *VocabularyService
+	events            *EventService
 }
 
 // NewWordTagService creates a new WordTagService instance
-func NewWordTagService(wordTagDAO *dao.WordTagDAO, wordDAO *dao.WordDAO, userDAO *dao.UserDAO, vocabularyService *VocabularyService) *WordTagService {
+func NewWordTagService(wordTagDAO *dao.WordTagDAO, wordDAO *dao.WordDAO, userDAO *dao.UserDAO, guestDAO *dao.GuestDAO, vocabularyService *VocabularyService, events *EventService) *WordTagService {
 	log.Info().Msg("Creating word tag service")
 
 	return &WordTagService{
 		wordTagDAO:        wordTagDAO,
 		wordDAO:           wordDAO,
 		userDAO:           userDAO,
+		guestDAO:          guestDAO,
 		vocabularyService: vocabularyService,
+		events:            events,
 	}
 }
 
 // MarkWordAsKnown marks a word as known
 func (s *WordTagService) MarkWordAsKnown(req *dto.WordMarkRequest) (*dto.WordMarkResponse, error) {
-	// Validate user exists
-	user, err := s.userDAO.FindByID(req.UserID)
+	// Validate user or guest exists
+	username, err := s.ownerName(req.UserID)
 	if err != nil {
 		log.Error(err).Str("user_id", req.UserID).Msg("Failed to find user")
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, notFound(ErrUserNotFound, err)
 	}
 
 	// Validate word exists
 	word, err := s.wordDAO.GetByID(req.WordID)
 	if err != nil {
 		log.Error(err).Str("word_id", req.WordID).Msg("Failed to find word")
-		return nil, fmt.Errorf("word not found: %w", err)
+		return nil, notFound(ErrWordNotFound, err)
 	}
 
 	// Mark word as known
@@ -53,33 +60,40 @@ func (s *WordTagService) MarkWordAsKnown(req *dto.WordMarkRequest) (*dto.WordMar
 
 	log.Info().
 		Str("user_id", req.UserID).
-		Str("username", user.Username).
+		Str("username", username).
 		Str("word_id", req.WordID).
 		Str("english", word.English).
 		Msg("Word marked as known")
 
+	s.publishChange(req.UserID, dto.EventWordMarked, &dto.WordMarkStatus{
+		WordID:    req.WordID,
+		IsMarked:  true,
+		MarkCount: 1,
+		MarkedAt:  time.Now().UnixMilli(),
+	})
+
 	return &dto.WordMarkResponse{
 		WordID:    req.WordID,
 		IsMarked:  true,
 		MarkCount: 1, // In new design, always 1 if known
-		Message:   "单词已标记为认识",
+		Message:   i18n.T(req.Lang, "word.marked"),
 	}, nil
 }
 
 // RemoveWordMark removes a word's mark (marks as unknown)
 func (s *WordTagService) RemoveWordMark(req *dto.WordMarkRequest) (*dto.WordMarkResponse, error) {
-	// Validate user exists
-	user, err := s.userDAO.FindByID(req.UserID)
+	// Validate user or guest exists
+	username, err := s.ownerName(req.UserID)
 	if err != nil {
 		log.Error(err).Str("user_id", req.UserID).Msg("Failed to find user")
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, notFound(ErrUserNotFound, err)
 	}
 
 	// Validate word exists
 	word, err := s.wordDAO.GetByID(req.WordID)
 	if err != nil {
 		log.Error(err).Str("word_id", req.WordID).Msg("Failed to find word")
-		return nil, fmt.Errorf("word not found: %w", err)
+		return nil, notFound(ErrWordNotFound, err)
 	}
 
 	// Remove word mark
@@ -90,31 +104,33 @@ func (s *WordTagService) RemoveWordMark(req *dto.WordMarkRequest) (*dto.WordMark
 
 	log.Info().
 		Str("user_id", req.UserID).
-		Str("username", user.Username).
+		Str("username", username).
 		Str("word_id", req.WordID).
 		Str("english", word.English).
 		Msg("Word mark removed")
 
+	s.publishChange(req.UserID, dto.EventWordUnmarked, &dto.WordMarkStatus{WordID: req.WordID})
+
 	return &dto.WordMarkResponse{
 		WordID:    req.WordID,
 		IsMarked:  false,
 		MarkCount: 0, // In new design, always 0 if unknown
-		Message:   "单词标记已移除",
+		Message:   i18n.T(req.Lang, "word.unmarked"),
 	}, nil
 }
 
 // GetWordMarkStatus checks if a word is marked as known
 func (s *WordTagService) GetWordMarkStatus(wordID, userID string) (*dto.WordMarkStatus, error) {
-	// Check if user exists
-	if _, err := s.userDAO.FindByID(userID); err != nil {
+	// Check if user or guest exists
+	if _, err := s.ownerName(userID); err != nil {
 		log.Error(err).Str("user_id", userID).Msg("Failed to find user")
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, notFound(ErrUserNotFound, err)
 	}
 
 	// Check if word exists
 	if _, err := s.wordDAO.GetByID(wordID); err != nil {
 		log.Error(err).Str("word_id", wordID).Msg("Failed to find word")
-		return nil, fmt.Errorf("word not found: %w", err)
+		return nil, notFound(ErrWordNotFound, err)
 	}
 
 	// Check mark status
@@ -149,10 +165,10 @@ func (s *WordTagService) GetWordMarkStatus(wordID, userID string) (*dto.WordMark
 
 // GetBatchWordMarkStatus gets mark status for multiple words
 func (s *WordTagService) GetBatchWordMarkStatus(userID string, wordIDs []string) (*dto.WordMarkStatusResponse, error) {
-	// Check if user exists
-	if _, err := s.userDAO.FindByID(userID); err != nil {
+	// Check if user or guest exists
+	if _, err := s.ownerName(userID); err != nil {
 		log.Error(err).Str("user_id", userID).Msg("Failed to find user")
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, notFound(ErrUserNotFound, err)
 	}
 
 	// Get mark status for all words
@@ -200,15 +216,107 @@ func (s *WordTagService) GetBatchWordMarkStatus(userID string, wordIDs []string)
 	}, nil
 }
 
+// GetMarkStatuses returns the mark status of words for a user by word ID,
+// in a single query. Unlike GetBatchWordMarkStatus it does not check that the
+// user and the words exist: unknown words are reported as not marked.
+func (s *WordTagService) GetMarkStatuses(userID string, wordIDs []string) (map[string]dto.WordMarkStatus, error) {
+	wordTags, err := s.wordTagDAO.GetKnownByWordIDs(userID, wordIDs)
+	if err != nil {
+		log.Error(err).Str("user_id", userID).Int("words", len(wordIDs)).Msg("Failed to get word mark statuses")
+		return nil, err
+	}
+
+	statuses := make(map[string]dto.WordMarkStatus, len(wordIDs))
+	for _, wordID := range wordIDs {
+		statuses[wordID] = dto.WordMarkStatus{WordID: wordID}
+	}
+	for _, wordTag := range wordTags {
+		statuses[wordTag.WordID] = dto.WordMarkStatus{
+			WordID:    wordTag.WordID,
+			IsMarked:  true,
+			MarkCount: 1,
+			MarkedAt:  wordTag.GetKnownTimestamp(),
+		}
+	}
+	return statuses, nil
+}
+
+// BatchMarkWords marks several words as known. A word that does not exist or
+// fails to be marked is reported in Errors and does not stop the others.
+func (s *WordTagService) BatchMarkWords(req *dto.BatchWordMarkRequest) (*dto.BatchWordMarkResponse, error) {
+	// Validate user or guest exists
+	if _, err := s.ownerName(req.UserID); err != nil {
+		log.Error(err).Str("user_id", req.UserID).Msg("Failed to find user")
+		return nil, notFound(ErrUserNotFound, err)
+	}
+
+	response := &dto.BatchWordMarkResponse{
+		Results: make([]dto.WordMarkResponse, 0, len(req.WordIDs)),
+		Errors:  make(map[string]string),
+	}
+	for _, wordID := range req.WordIDs {
+		if _, err := s.wordDAO.GetByID(wordID); err != nil {
+			log.Warn().Str("word_id", wordID).Msg("Word not found, skipping")
+			response.FailedCount++
+			response.Errors[wordID] = i18n.ErrorMessage(req.Lang, pke.CodeWordNotFound)
+			continue
+		}
+		if err := s.wordTagDAO.MarkWordAsKnown(wordID, req.UserID); err != nil {
+			log.Error(err).Str("user_id", req.UserID).Str("word_id", wordID).Msg("Failed to mark word as known")
+			response.FailedCount++
+			response.Errors[wordID] = i18n.ErrorMessage(req.Lang, pke.CodeMarkFailed)
+			continue
+		}
+
+		response.SuccessCount++
+		response.Results = append(response.Results, dto.WordMarkResponse{
+			WordID:    wordID,
+			IsMarked:  true,
+			MarkCount: 1,
+			Message:   i18n.T(req.Lang, "word.marked"),
+		})
+	}
+
+	log.Info().Str("user_id", req.UserID).Int("marked", response.SuccessCount).Int("failed", response.FailedCount).Msg("Words marked as known")
+
+	if response.SuccessCount > 0 {
+		markedIDs := make([]string, 0, len(response.Results))
+		for _, result := range response.Results {
+			markedIDs = append(markedIDs, result.WordID)
+		}
+		s.publishChange(req.UserID, dto.EventWordsMarked, &dto.WordsEvent{WordIDs: markedIDs})
+	}
+
+	return response, nil
+}
+
 // GetUserProgress returns user's learning progress
 func (s *WordTagService) GetUserProgress(userID string) (*dto.UserProgressResponse, error) {
-	// Validate user exists
-	user, err := s.userDAO.FindByID(userID)
+	// Validate user or guest exists
+	username, err := s.ownerName(userID)
 	if err != nil {
 		log.Error(err).Str("user_id", userID).Msg("Failed to find user")
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, notFound(ErrUserNotFound, err)
+	}
+
+	progress, err := s.progress(userID)
+	if err != nil {
+		return nil, err
 	}
 
+	log.Info().
+		Str("user_id", userID).
+		Str("username", username).
+		Int64("known_words", progress.KnownWords).
+		Int64("total_words", progress.TotalWords).
+		Float64("progress_rate", progress.ProgressRate).
+		Msg("Retrieved user progress")
+
+	return progress, nil
+}
+
+// progress counts the known words of userID against the whole vocabulary
+func (s *WordTagService) progress(userID string) (*dto.UserProgressResponse, error) {
 	// Get known words count
 	knownWords, err := s.wordTagDAO.GetKnownWordsCount(userID)
 	if err != nil {
@@ -232,14 +340,6 @@ func (s *WordTagService) GetUserProgress(userID string) (*dto.UserProgressRespon
 	// Get recent activity (for simplicity, using known words count as recent activity)
 	recentActivity := int(knownWords)
 
-	log.Info().
-		Str("user_id", userID).
-		Str("username", user.Username).
-		Int64("known_words", knownWords).
-		Int64("total_words", totalWords).
-		Float64("progress_rate", progressRate).
-		Msg("Retrieved user progress")
-
 	return &dto.UserProgressResponse{
 		UserID:         userID,
 		KnownWords:     knownWords,
@@ -249,16 +349,50 @@ func (s *WordTagService) GetUserProgress(userID string) (*dto.UserProgressRespon
 	}, nil
 }
 
+// NotifyWordsChanged pushes words marked and unmarked outside of this
+// service, such as by a sync, to the user's clients
+func (s *WordTagService) NotifyWordsChanged(userID string, marked, unmarked []string) {
+	if s.events == nil || len(marked)+len(unmarked) == 0 {
+		return
+	}
+	if len(marked) > 0 {
+		s.events.Publish(userID, dto.EventWordsMarked, &dto.WordsEvent{WordIDs: marked})
+	}
+	if len(unmarked) > 0 {
+		s.events.Publish(userID, dto.EventWordsForgotten, &dto.WordsEvent{WordIDs: unmarked})
+	}
+	s.publishProgress(userID)
+}
+
+// publishChange pushes a change of userID's known words to the user's
+// clients, followed by the resulting progress
+func (s *WordTagService) publishChange(userID, eventType string, data interface{}) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(userID, eventType, data)
+	s.publishProgress(userID)
+}
+
+// publishProgress pushes the progress of userID to the user's clients
+func (s *WordTagService) publishProgress(userID string) {
+	progress, err := s.progress(userID)
+	if err != nil {
+		return
+	}
+	s.events.Publish(userID, dto.EventProgress, progress)
+}
+
 // GetKnownWords returns paginated known words
 func (s *WordTagService) GetKnownWords(req *dto.KnownWordsRequest) (*dto.KnownWordsResponse, error) {
-	// Validate user exists
-	if _, err := s.userDAO.FindByID(req.UserID); err != nil {
+	// Validate user or guest exists
+	if _, err := s.ownerName(req.UserID); err != nil {
 		log.Error(err).Str("user_id", req.UserID).Msg("Failed to find user")
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, notFound(ErrUserNotFound, err)
 	}
 
 	// Get known words
-	wordIDs, totalCount, err := s.wordTagDAO.GetKnownWords(req.UserID, nil)
+	wordIDs, totalCount, _, err := s.wordTagDAO.GetKnownWords(req.UserID, nil)
 	if err != nil {
 		log.Error(err).Str("user_id", req.UserID).Msg("Failed to get known words")
 		return nil, fmt.Errorf("failed to get known words: %w", err)
@@ -280,10 +414,14 @@ func (s *WordTagService) ForgetWords(userID string, req *dto.ForgetWordsRequest)
 		return nil, fmt.Errorf("failed to forget words: %w", err)
 	}
 
+	if forgottenCount > 0 {
+		s.publishChange(userID, dto.EventWordsForgotten, &dto.WordsEvent{WordIDs: req.WordIDs})
+	}
+
 	return &dto.ForgetWordsResponse{
 		WordIDs:        req.WordIDs,
 		ForgottenCount: forgottenCount,
-		Message:        fmt.Sprintf("已忘光 %d 个已认识单词", forgottenCount),
+		Message:        i18n.T(req.Lang, "word.forgotten", forgottenCount),
 	}, nil
 }
 
@@ -302,9 +440,13 @@ func (s *WordTagService) ForgetAllWords(userID string, req *dto.ForgetAllRequest
 		return nil, fmt.Errorf("failed to forget all words: %w", err)
 	}
 
+	if forgottenCount > 0 {
+		s.publishChange(userID, dto.EventWordsForgotten, &dto.WordsEvent{All: true})
+	}
+
 	return &dto.ForgetAllResponse{
 		ForgottenCount: forgottenCount,
-		Message:        fmt.Sprintf("已忘光全部 %d 个已认识单词", forgottenCount),
+		Message:        i18n.T(req.Lang, "word.forgotten_all", forgottenCount),
 	}, nil
 }
 
@@ -331,11 +473,11 @@ func (s *WordTagService) GetWordTagStats() (*dto.WordTagStats, error) {
 
 // GetUserWordStats returns detailed word statistics for a user
 func (s *WordTagService) GetUserWordStats(userID string) (*dto.UserWordStats, error) {
-	// Validate user exists
-	user, err := s.userDAO.FindByID(userID)
+	// Validate user or guest exists
+	username, err := s.ownerName(userID)
 	if err != nil {
 		log.Error(err).Str("user_id", userID).Msg("Failed to find user")
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, notFound(ErrUserNotFound, err)
 	}
 
 	// Get basic counts
@@ -359,7 +501,7 @@ func (s *WordTagService) GetUserWordStats(userID string) (*dto.UserWordStats, er
 
 	// Get recent marks (last 10)
 	baseList := &dao.BaseList{PageNum: 1, PageSize: 10}
-	wordIDs, _, err := s.wordTagDAO.GetKnownWords(userID, baseList)
+	wordIDs, _, _, err := s.wordTagDAO.GetKnownWords(userID, baseList)
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to get recent marks")
 	}
@@ -378,7 +520,7 @@ func (s *WordTagService) GetUserWordStats(userID string) (*dto.UserWordStats, er
 
 	log.Info().
 		Str("user_id", userID).
-		Str("username", user.Username).
+		Str("username", username).
 		Int64("known_words", knownWords).
 		Float64("progress_rate", progressRate).
 		Msg("Retrieved user word statistics")
@@ -393,3 +535,15 @@ func (s *WordTagService) GetUserWordStats(userID string) (*dto.UserWordStats, er
 		TopCategories:    make(map[string]int),
 	}, nil
 }
+
+// ownerName returns the username of the owner of word tags, which is either
+// a user or a guest
+func (s *WordTagService) ownerName(ownerID string) (string, error) {
+	if user, err := s.userDAO.FindByID(ownerID); err == nil {
+		return user.Username, nil
+	}
+	if _, err := s.guestDAO.FindByID(ownerID); err != nil {
+		return "", notFound(ErrUserNotFound, err)
+	}
+	return "guest", nil
+}
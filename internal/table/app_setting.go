@@ -0,0 +1,23 @@
+package table
+
+// Keys of runtime settings stored in app_settings
+const (
+	// SettingRequire2FAForAdmin requires accounts with the admin role to enroll in TOTP 2FA
+	SettingRequire2FAForAdmin = "security.require_2fa_admin"
+	// SettingLDAPLinkByEmail lets a first LDAP login take over the local
+	// account with the same verified email
+	SettingLDAPLinkByEmail = "security.ldap_link_by_email"
+)
+
+// AppSetting represents the app_settings table in database.
+// It holds settings that administrators can change at runtime.
+type AppSetting struct {
+	Key       string `json:"key" gorm:"primary_key;size:100"`
+	Value     string `json:"value" gorm:"type:text;not null"`
+	UpdatedAt int64  `gorm:"autoUpdateTime:milli" json:"updatedAt"`
+}
+
+// TableName returns the table name for AppSetting model
+func (AppSetting) TableName() string {
+	return "app_settings"
+}
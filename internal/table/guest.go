@@ -0,0 +1,30 @@
+package table
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/utils"
+)
+
+// Guest represents the guests table in database.
+// A guest is an anonymous visitor whose word tags are stored under the guest
+// ID until they are merged into a real account on register or login.
+type Guest struct {
+	ID         string `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	LastSeenAt int64  `json:"lastSeenAt" gorm:"not null;index"`
+	CreatedAt  int64  `gorm:"autoCreateTime:milli" json:"createdAt"`
+}
+
+// TableName returns the table name for Guest model
+func (Guest) TableName() string {
+	return "guests"
+}
+
+// BeforeCreate GORM hook - called before creating a new guest
+func (g *Guest) BeforeCreate(tx *gorm.DB) error {
+	// Generate UUID for ID if not provided
+	if g.ID == "" {
+		g.ID = utils.GenerateUUID()
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package table
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/utils"
+)
+
+// Scopes that can be granted to personal access tokens
+const (
+	ScopeReadWords = "read:words" // word lists and the user's word status and progress
+	ScopeWriteTags = "write:tags" // mark, unmark and forget words
+	ScopeReadUser  = "read:user"  // the user's profile
+)
+
+// PersonalAccessTokenScopes lists every valid scope
+var PersonalAccessTokenScopes = []string{ScopeReadWords, ScopeWriteTags, ScopeReadUser}
+
+// PersonalAccessTokenPrefix starts every personal access token so they are
+// easy to recognise, both in requests and in leaked secrets scans
+const PersonalAccessTokenPrefix = "whp_"
+
+// PersonalAccessToken represents the personal_access_tokens table in database.
+// Only a hash of each token is stored; the token itself is shown once on creation.
+type PersonalAccessToken struct {
+	ID          string `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      string `json:"-" gorm:"type:uuid;not null;index"`
+	Name        string `json:"name" gorm:"size:100;not null"`
+	TokenPrefix string `json:"tokenPrefix" gorm:"size:16;not null"` // first characters, to tell tokens apart
+	TokenHash   string `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Scopes      string `json:"scopes" gorm:"size:255;not null"` // space separated
+	ExpiresAt   *int64 `json:"expiresAt,omitempty"`
+	LastUsedAt  *int64 `json:"lastUsedAt,omitempty"`
+	LastUsedIP  string `json:"lastUsedIp,omitempty" gorm:"size:64"`
+	RevokedAt   *int64 `json:"-"`
+	CreatedAt   int64  `gorm:"autoCreateTime:milli" json:"createdAt"`
+}
+
+// TableName returns the table name for PersonalAccessToken model
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// BeforeCreate GORM hook - called before creating a new personal access token
+func (t *PersonalAccessToken) BeforeCreate(tx *gorm.DB) error {
+	// Generate UUID for ID if not provided
+	if t.ID == "" {
+		t.ID = utils.GenerateUUID()
+	}
+	return nil
+}
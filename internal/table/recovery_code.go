@@ -0,0 +1,32 @@
+package table
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/utils"
+)
+
+// RecoveryCode represents the user_recovery_codes table in database.
+// Recovery codes let a user sign in when their authenticator is unavailable;
+// only a hash of each code is stored and every code can be used once.
+type RecoveryCode struct {
+	ID        string `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    string `json:"userId" gorm:"type:uuid;not null;index"`
+	CodeHash  string `json:"-" gorm:"size:64;not null"`
+	UsedAt    *int64 `json:"usedAt,omitempty"`
+	CreatedAt int64  `gorm:"autoCreateTime:milli" json:"createdAt"`
+}
+
+// TableName returns the table name for RecoveryCode model
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// BeforeCreate GORM hook - called before creating a new recovery code
+func (r *RecoveryCode) BeforeCreate(tx *gorm.DB) error {
+	// Generate UUID for ID if not provided
+	if r.ID == "" {
+		r.ID = utils.GenerateUUID()
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package table
+
+// SyncOperation represents the sync_operations table in database.
+// It records the client operations applied by a sync, so that an operation
+// sent again after its response was lost is not applied twice.
+type SyncOperation struct {
+	UserID string `json:"userId" gorm:"type:uuid;primaryKey"`
+	// ID is chosen by the client and unique among the user's operations
+	ID        string `json:"id" gorm:"type:varchar(64);primaryKey"`
+	AppliedAt int64  `json:"appliedAt" gorm:"not null;index"`
+}
+
+// TableName returns the table name for SyncOperation model
+func (SyncOperation) TableName() string {
+	return "sync_operations"
+}
@@ -8,19 +8,25 @@ import (
 
 // User represents the users table in database
 type User struct {
-	ID           string    `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Username     string    `json:"username" gorm:"uniqueIndex;size:50;not null"`
-	Email        string    `json:"email" gorm:"uniqueIndex;size:100;not null"`
-	PasswordHash string    `json:"-" gorm:"size:255;not null"`
-	FullName     string    `json:"full_name" gorm:"size:100"`
-	AvatarURL    string    `json:"avatar_url" gorm:"size:255"`
-	Bio          string    `json:"bio" gorm:"type:text"`
-	Role         string    `json:"role" gorm:"size:20;default:'user'"`
-	IsActive     bool      `json:"is_active" gorm:"default:true"`
-	LastLogin    *int64    `json:"last_login,omitempty"`
-	CreatedAt    int64     `gorm:"autoCreateTime:milli" json:"createdAt"`
-	UpdatedAt    int64     `gorm:"autoUpdateTime:milli" json:"updatedAt"`
-	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              string         `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Username        string         `json:"username" gorm:"uniqueIndex;size:50;not null"`
+	Email           string         `json:"email" gorm:"uniqueIndex:idx_users_email,where:email <> '';size:100;not null"` // empty for directory users whose address belongs to another account
+	PasswordHash    string         `json:"-" gorm:"size:255;not null"`
+	FullName        string         `json:"full_name" gorm:"size:100"`
+	AvatarURL       string         `json:"avatar_url" gorm:"size:255"`
+	Bio             string         `json:"bio" gorm:"type:text"`
+	Role            string         `json:"role" gorm:"size:20;default:'user'"`
+	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	LastLogin       *int64         `json:"last_login,omitempty"`
+	EmailVerifiedAt *int64         `json:"email_verified_at,omitempty"`
+	SessionVersion  int            `json:"-" gorm:"not null;default:0"`         // bumped to revoke all issued sessions
+	TOTPSecret      string         `json:"-" gorm:"column:totp_secret;size:64"` // set during enrollment, active once TOTPEnabledAt is set
+	TOTPEnabledAt   *int64         `json:"totp_enabled_at,omitempty" gorm:"column:totp_enabled_at"`
+	TOTPLastStep    int64          `json:"-" gorm:"column:totp_last_step;not null;default:0"` // last accepted time step, prevents code replay
+	Language        string         `json:"language" gorm:"size:10"`                           // preferred language of messages, empty to follow the browser
+	CreatedAt       int64          `gorm:"autoCreateTime:milli" json:"createdAt"`
+	UpdatedAt       int64          `gorm:"autoUpdateTime:milli" json:"updatedAt"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // TableName returns the table name for User model
@@ -42,4 +48,4 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 		u.IsActive = true
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,34 @@
+package table
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/utils"
+)
+
+// UserIdentity represents the user_identities table in database.
+// It links an account at an external identity provider to a local user.
+type UserIdentity struct {
+	ID          string `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      string `json:"userId" gorm:"type:uuid;not null;index"`
+	Provider    string `json:"provider" gorm:"size:50;not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject     string `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email       string `json:"email" gorm:"size:100"`
+	Provisioned bool   `json:"provisioned" gorm:"not null;default:false"` // the user was created from this identity, which then also manages its role
+	LastLoginAt *int64 `json:"lastLoginAt,omitempty"`
+	CreatedAt   int64  `gorm:"autoCreateTime:milli" json:"createdAt"`
+}
+
+// TableName returns the table name for UserIdentity model
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// BeforeCreate GORM hook - called before creating a new user identity
+func (i *UserIdentity) BeforeCreate(tx *gorm.DB) error {
+	// Generate UUID for ID if not provided
+	if i.ID == "" {
+		i.ID = utils.GenerateUUID()
+	}
+	return nil
+}
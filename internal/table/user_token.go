@@ -0,0 +1,40 @@
+package table
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/sanmu2018/word-hero/internal/utils"
+)
+
+// Purposes of single-use user tokens
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeLogin2FA          = "login_2fa"
+)
+
+// UserToken represents the user_tokens table in database.
+// It records single-use tokens sent to users; only a hash of each token is stored.
+type UserToken struct {
+	ID        string `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    string `json:"userId" gorm:"type:uuid;not null;index:idx_user_tokens_user_purpose"`
+	Purpose   string `json:"purpose" gorm:"size:50;not null;index:idx_user_tokens_user_purpose"`
+	TokenHash string `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt int64  `json:"expiresAt" gorm:"not null"`
+	UsedAt    *int64 `json:"usedAt,omitempty"`
+	CreatedAt int64  `gorm:"autoCreateTime:milli" json:"createdAt"`
+}
+
+// TableName returns the table name for UserToken model
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// BeforeCreate GORM hook - called before creating a new user token
+func (t *UserToken) BeforeCreate(tx *gorm.DB) error {
+	// Generate UUID for ID if not provided
+	if t.ID == "" {
+		t.ID = utils.GenerateUUID()
+	}
+	return nil
+}
@@ -8,16 +8,20 @@ import (
 
 // Word represents the words table in database
 type Word struct {
-	ID           string  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	English      string  `json:"english" gorm:"size:200;not null"`
-	Chinese      string  `json:"chinese" gorm:"size:500;not null"`
-	Phonetic     string  `json:"phonetic,omitempty" gorm:"size:100"`
-	Example      string  `json:"example,omitempty" gorm:"type:text"`
-	Definition   string  `json:"definition,omitempty" gorm:"type:text"`
-	Difficulty   string  `json:"difficulty,omitempty" gorm:"size:20"`
-	Category     string  `json:"category,omitempty" gorm:"size:50"`
-	CreatedAt    int64   `gorm:"autoCreateTime:milli" json:"createdAt"`
-	UpdatedAt    int64   `gorm:"autoUpdateTime:milli" json:"updatedAt"`
+	ID         string `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	English    string `json:"english" gorm:"size:200;not null"`
+	Chinese    string `json:"chinese" gorm:"size:500;not null"`
+	Phonetic   string `json:"phonetic,omitempty" gorm:"size:100"`
+	Example    string `json:"example,omitempty" gorm:"type:text"`
+	Definition string `json:"definition,omitempty" gorm:"type:text"`
+	Difficulty string `json:"difficulty,omitempty" gorm:"size:20"`
+	Category   string `json:"category,omitempty" gorm:"size:50"`
+	// Pinyin of the Chinese glosses, derived on save for pinyin search
+	Pinyin         string `json:"-" gorm:"type:text"`
+	PinyinTone     string `json:"-" gorm:"type:text"`
+	PinyinInitials string `json:"-" gorm:"type:text"`
+	CreatedAt      int64  `gorm:"autoCreateTime:milli" json:"createdAt"`
+	UpdatedAt      int64  `gorm:"autoUpdateTime:milli" json:"updatedAt"`
 }
 
 // TableName returns the table name for Word model
@@ -32,4 +36,16 @@ func (w *Word) BeforeCreate(tx *gorm.DB) error {
 		w.ID = utils.GenerateUUID()
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// BeforeSave GORM hook - keeps the pinyin columns in sync with Chinese
+func (w *Word) BeforeSave(tx *gorm.DB) error {
+	w.SetPinyin()
+	return nil
+}
+
+// SetPinyin derives the pinyin columns from the Chinese definition
+func (w *Word) SetPinyin() {
+	p := utils.ChinesePinyin(w.Chinese)
+	w.Pinyin, w.PinyinTone, w.PinyinInitials = p.Plain, p.Tone, p.Initials
+}
@@ -14,6 +14,9 @@ type WordTag struct {
 	WordID    string  `json:"wordId" gorm:"type:uuid;not null;index:idx_word_tags_word_id"`
 	UserID    string  `json:"userId" gorm:"type:uuid;not null;index:idx_word_tags_user_id"`
 	Known     *int64  `json:"known"` // NULL means not known, non-null means known with timestamp
+	// ChangedAt is when Known last changed, by the clock of the client that
+	// changed it; offline changes synced later are ordered by it
+	ChangedAt int64   `json:"changedAt" gorm:"not null;default:0"`
 	CreatedAt int64   `gorm:"autoCreateTime:milli" json:"createdAt"`
 	UpdatedAt int64   `gorm:"autoUpdateTime:milli" json:"updatedAt"`
 }
@@ -37,11 +40,22 @@ func (wt *WordTag) BeforeCreate(tx *gorm.DB) error {
 func (wt *WordTag) MarkAsKnown() {
 	timestamp := time.Now().UnixMilli()
 	wt.Known = &timestamp
+	wt.ChangedAt = timestamp
 }
 
 // MarkAsUnknown marks the word as unknown (sets Known to NULL)
 func (wt *WordTag) MarkAsUnknown() {
 	wt.Known = nil
+	wt.ChangedAt = time.Now().UnixMilli()
+}
+
+// LastChangedAt returns when Known last changed. Tags written before
+// ChangedAt existed fall back to their update time.
+func (wt *WordTag) LastChangedAt() int64 {
+	if wt.ChangedAt == 0 {
+		return wt.UpdatedAt
+	}
+	return wt.ChangedAt
 }
 
 // IsKnown checks if the word is marked as known
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"sync"
@@ -179,4 +181,11 @@ func GetDefaultIDGenerator() IDGenerator {
 // GetSnowflakeIDGenerator returns a snowflake-based ID generator
 func GetSnowflakeIDGenerator() IDGenerator {
 	return &SnowflakeStringGenerator{}
-}
\ No newline at end of file
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a token, for storing
+// secrets sent to users without keeping the secret itself
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sanmu2018/word-hero/internal/conf"
+)
+
+// jwtKey is a key that verifies tokens and, when signKey is set, signs them
+type jwtKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// loadJWTKey reads an asymmetric key from its PEM files
+func loadJWTKey(config conf.JWTKeyConfig) (*jwtKey, error) {
+	if config.ID == "" {
+		return nil, fmt.Errorf("jwt key requires an id")
+	}
+	if config.PrivateKeyFile == "" && config.PublicKeyFile == "" {
+		return nil, fmt.Errorf("jwt key %s requires private_key_file or public_key_file", config.ID)
+	}
+
+	key := &jwtKey{id: config.ID}
+	switch config.Algorithm {
+	case "RS256":
+		key.method = jwt.SigningMethodRS256
+	case "EdDSA":
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("jwt key %s has unsupported algorithm: %q", config.ID, config.Algorithm)
+	}
+
+	if config.PrivateKeyFile != "" {
+		data, err := os.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt key %s: %w", config.ID, err)
+		}
+		var signer crypto.Signer
+		if key.method == jwt.SigningMethodRS256 {
+			signer, err = jwt.ParseRSAPrivateKeyFromPEM(data)
+		} else {
+			var private crypto.PrivateKey
+			private, err = jwt.ParseEdPrivateKeyFromPEM(data)
+			if err == nil {
+				signer = private.(crypto.Signer)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwt key %s: %w", config.ID, err)
+		}
+		key.signKey = signer
+		key.verifyKey = signer.Public()
+		return key, nil
+	}
+
+	data, err := os.ReadFile(config.PublicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt key %s: %w", config.ID, err)
+	}
+	if key.method == jwt.SigningMethodRS256 {
+		key.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(data)
+	} else {
+		key.verifyKey, err = jwt.ParseEdPublicKeyFromPEM(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwt key %s: %w", config.ID, err)
+	}
+	return key, nil
+}
+
+// jwk returns the public part of an asymmetric key as a JWK
+func (k *jwtKey) jwk() (JWK, bool) {
+	b64 := base64.RawURLEncoding
+	switch public := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: k.id,
+			Use: "sig",
+			Alg: k.method.Alg(),
+			N:   b64.EncodeToString(public.N.Bytes()),
+			E:   b64.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: k.id,
+			Use: "sig",
+			Alg: k.method.Alg(),
+			Crv: "Ed25519",
+			X:   b64.EncodeToString(public),
+		}, true
+	default:
+		// Shared secrets are never published
+		return JWK{}, false
+	}
+}
@@ -15,23 +15,99 @@ type JWTClaims struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Role     string `json:"role"`
+	Purpose  string `json:"purpose,omitempty"` // empty for session tokens
+	// SessionVersion must match the user's current version; bumping it revokes the token
+	SessionVersion int `json:"sv,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // JWTUtils handles JWT token operations
 type JWTUtils struct {
 	config *conf.JWTConfig
+	// keys holds every key that verifies tokens, by kid. The legacy HS256
+	// secret has an empty kid because tokens signed with it carry none.
+	keys       map[string]*jwtKey
+	signingKey *jwtKey
 }
 
-// NewJWTUtils creates a new JWTUtils instance
-func NewJWTUtils(config *conf.JWTConfig) *JWTUtils {
-	return &JWTUtils{
+// NewJWTUtils creates a new JWTUtils instance, loading the configured keys
+func NewJWTUtils(config *conf.JWTConfig) (*JWTUtils, error) {
+	j := &JWTUtils{
 		config: config,
+		keys:   make(map[string]*jwtKey),
 	}
+
+	for _, keyConfig := range config.Keys {
+		key, err := loadJWTKey(keyConfig)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := j.keys[key.id]; exists {
+			return nil, fmt.Errorf("duplicate jwt key id: %s", key.id)
+		}
+		j.keys[key.id] = key
+		if j.signingKey == nil && key.signKey != nil {
+			j.signingKey = key
+		}
+	}
+	if config.Secret != "" {
+		secret := []byte(config.Secret)
+		j.keys[""] = &jwtKey{method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}
+	}
+
+	// An explicit signing key overrides the first private key
+	if config.SigningKey != "" {
+		key, ok := j.keys[config.SigningKey]
+		if !ok || key.signKey == nil {
+			return nil, fmt.Errorf("jwt signing_key %s is not a configured key with a private key", config.SigningKey)
+		}
+		j.signingKey = key
+	}
+	if j.signingKey == nil {
+		j.signingKey = j.keys[""]
+	}
+	if j.signingKey == nil {
+		return nil, fmt.Errorf("jwt requires a secret or a key with a private key")
+	}
+	return j, nil
+}
+
+// JWKS returns the public keys that verify tokens, for other services
+func (j *JWTUtils) JWKS() *JWKSet {
+	set := &JWKSet{Keys: []JWK{}}
+	for _, keyConfig := range j.config.Keys {
+		if jwk, ok := j.keys[keyConfig.ID].jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// sign signs claims with the current signing key
+func (j *JWTUtils) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(j.signingKey.method, claims)
+	if j.signingKey.id != "" {
+		token.Header["kid"] = j.signingKey.id
+	}
+	return token.SignedString(j.signingKey.signKey)
+}
+
+// keyFunc selects the verification key by the token's kid and rejects any
+// algorithm other than the one the key was configured with
+func (j *JWTUtils) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verifyKey, nil
 }
 
 // GenerateToken generates a JWT token for a user
-func (j *JWTUtils) GenerateToken(userID string, username, email, role string) (string, error) {
+func (j *JWTUtils) GenerateToken(userID string, username, email, role string, sessionVersion int) (string, error) {
 	// Parse expiration duration
 	duration, err := time.ParseDuration(j.config.ExpiresIn)
 	if err != nil {
@@ -40,10 +116,11 @@ func (j *JWTUtils) GenerateToken(userID string, username, email, role string) (s
 
 	// Create claims
 	claims := JWTClaims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
-		Role:     role,
+		UserID:         userID,
+		Username:       username,
+		Email:          email,
+		Role:           role,
+		SessionVersion: sessionVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
@@ -52,11 +129,8 @@ func (j *JWTUtils) GenerateToken(userID string, username, email, role string) (s
 		},
 	}
 
-	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
 	// Sign token
-	tokenString, err := token.SignedString([]byte(j.config.Secret))
+	tokenString, err := j.sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -67,13 +141,7 @@ func (j *JWTUtils) GenerateToken(userID string, username, email, role string) (s
 // ValidateToken validates a JWT token and returns the claims
 func (j *JWTUtils) ValidateToken(tokenString string) (*JWTClaims, error) {
 	// Parse token
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(j.config.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, j.keyFunc)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -81,12 +149,53 @@ func (j *JWTUtils) ValidateToken(tokenString string) (*JWTClaims, error) {
 
 	// Extract claims
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		// Single-purpose tokens (email verification etc.) must not be usable as sessions
+		if claims.Purpose != "" {
+			return nil, errors.New("invalid token purpose")
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
 
+// GeneratePurposeToken generates a short-lived token for a single purpose such
+// as email verification. The returned token ID can be recorded to make it single-use.
+func (j *JWTUtils) GeneratePurposeToken(userID, purpose string, ttl time.Duration) (string, string, error) {
+	tokenID := GenerateUUID()
+	claims := JWTClaims{
+		UserID:  userID,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			Issuer:    "word-hero",
+			Subject:   userID,
+		},
+	}
+
+	tokenString, err := j.sign(claims)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return tokenString, tokenID, nil
+}
+
+// ValidatePurposeToken validates a token generated by GeneratePurposeToken
+func (j *JWTUtils) ValidatePurposeToken(tokenString, purpose string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, j.keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid || claims.Purpose != purpose || claims.ID == "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
 // RefreshToken generates a new token with the same claims but new expiration
 func (j *JWTUtils) RefreshToken(tokenString string) (string, error) {
 	// Validate existing token
@@ -96,7 +205,7 @@ func (j *JWTUtils) RefreshToken(tokenString string) (string, error) {
 	}
 
 	// Generate new token
-	return j.GenerateToken(claims.UserID, claims.Username, claims.Email, claims.Role)
+	return j.GenerateToken(claims.UserID, claims.Username, claims.Email, claims.Role, claims.SessionVersion)
 }
 
 // GetUserIDFromToken extracts user ID from token
@@ -106,4 +215,4 @@ func (j *JWTUtils) GetUserIDFromToken(tokenString string) (string, error) {
 		return "", fmt.Errorf("failed to validate token: %w", err)
 	}
 	return claims.UserID, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/sanmu2018/word-hero/internal/conf"
+)
+
+// writeKeyFiles writes a private and public PEM file for key and returns their paths
+func writeKeyFiles(t *testing.T, name string, private interface{}, public interface{}) (string, string) {
+	t.Helper()
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, name+".pem")
+	publicPath := filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatalf("failed to write private key: %v", err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600); err != nil {
+		t.Fatalf("failed to write public key: %v", err)
+	}
+	return privatePath, publicPath
+}
+
+// testKeys returns configs for an RSA key with its private key and an
+// Ed25519 key with only its public key
+func testKeys(t *testing.T) (conf.JWTKeyConfig, conf.JWTKeyConfig) {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	rsaPrivate, _ := writeKeyFiles(t, "rsa", rsaKey, &rsaKey.PublicKey)
+
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 key: %v", err)
+	}
+	_, edPublicPath := writeKeyFiles(t, "ed", edPrivate, edPublic)
+
+	return conf.JWTKeyConfig{ID: "rsa-1", Algorithm: "RS256", PrivateKeyFile: rsaPrivate},
+		conf.JWTKeyConfig{ID: "ed-old", Algorithm: "EdDSA", PublicKeyFile: edPublicPath}
+}
+
+// tokenHeader returns the alg and kid a token was signed with
+func tokenHeader(t *testing.T, tokenString string) (string, string) {
+	t.Helper()
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &JWTClaims{})
+	if err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	kid, _ := token.Header["kid"].(string)
+	return token.Method.Alg(), kid
+}
+
+func TestNewJWTUtilsSelectsSigningKey(t *testing.T) {
+	rsaKey, edKey := testKeys(t)
+
+	tests := []struct {
+		name    string
+		config  conf.JWTConfig
+		wantAlg string
+		wantKid string
+	}{
+		{"secret only", conf.JWTConfig{Secret: "secret"}, "HS256", ""},
+		{"first private key", conf.JWTConfig{Secret: "secret", Keys: []conf.JWTKeyConfig{edKey, rsaKey}}, "RS256", "rsa-1"},
+		{"public keys only verify", conf.JWTConfig{Secret: "secret", Keys: []conf.JWTKeyConfig{edKey}}, "HS256", ""},
+		{"explicit signing key", conf.JWTConfig{SigningKey: "rsa-1", Keys: []conf.JWTKeyConfig{edKey, rsaKey}}, "RS256", "rsa-1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.ExpiresIn = "1h"
+			j, err := NewJWTUtils(&test.config)
+			if err != nil {
+				t.Fatalf("NewJWTUtils() error = %v", err)
+			}
+			token, err := j.GenerateToken("user-1", "alice", "alice@example.com", "user", 0)
+			if err != nil {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+			if alg, kid := tokenHeader(t, token); alg != test.wantAlg || kid != test.wantKid {
+				t.Errorf("token signed with alg %q kid %q, want %q %q", alg, kid, test.wantAlg, test.wantKid)
+			}
+			if _, err := j.ValidateToken(token); err != nil {
+				t.Errorf("ValidateToken() error = %v", err)
+			}
+		})
+	}
+
+	invalid := map[string]conf.JWTConfig{
+		"no signing key":          {Keys: []conf.JWTKeyConfig{edKey}},
+		"public-only signing key": {Secret: "secret", SigningKey: "ed-old", Keys: []conf.JWTKeyConfig{edKey}},
+		"unknown signing key":     {Secret: "secret", SigningKey: "missing"},
+		"duplicate kid":           {Keys: []conf.JWTKeyConfig{rsaKey, rsaKey}},
+	}
+	for name, config := range invalid {
+		if _, err := NewJWTUtils(&config); err == nil {
+			t.Errorf("%s: NewJWTUtils() succeeded, want an error", name)
+		}
+	}
+}
+
+func TestValidateTokenAcrossKeyRotation(t *testing.T) {
+	rsaKey, _ := testKeys(t)
+
+	// Tokens signed with the secret stay valid once an RSA key takes over signing
+	old, err := NewJWTUtils(&conf.JWTConfig{Secret: "secret", ExpiresIn: "1h"})
+	if err != nil {
+		t.Fatalf("NewJWTUtils() error = %v", err)
+	}
+	legacy, err := old.GenerateToken("user-1", "alice", "alice@example.com", "user", 0)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	j, err := NewJWTUtils(&conf.JWTConfig{Secret: "secret", ExpiresIn: "1h", Keys: []conf.JWTKeyConfig{rsaKey}})
+	if err != nil {
+		t.Fatalf("NewJWTUtils() error = %v", err)
+	}
+	if _, err := j.ValidateToken(legacy); err != nil {
+		t.Errorf("ValidateToken(legacy) error = %v", err)
+	}
+	if jwks := j.JWKS(); len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "rsa-1" {
+		t.Errorf("JWKS() = %+v, want only the RSA public key", jwks)
+	}
+}
+
+func TestValidateTokenRejectsUnknownKidAndAlgorithm(t *testing.T) {
+	rsaKey, edKey := testKeys(t)
+	j, err := NewJWTUtils(&conf.JWTConfig{Secret: "secret", ExpiresIn: "1h", Keys: []conf.JWTKeyConfig{rsaKey, edKey}})
+	if err != nil {
+		t.Fatalf("NewJWTUtils() error = %v", err)
+	}
+	claims := JWTClaims{
+		UserID: "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+
+	rsaPEM, err := os.ReadFile(rsaKey.PrivateKeyFile)
+	if err != nil {
+		t.Fatalf("failed to read key: %v", err)
+	}
+	tests := map[string]string{
+		// A kid that was never configured, or that was removed
+		"unknown kid": sign(jwt.SigningMethodHS256, "retired", []byte("secret")),
+		// HS256 keyed with the RSA key material, presenting the RSA kid
+		"alg mismatch for kid": sign(jwt.SigningMethodHS256, "rsa-1", rsaPEM),
+		// The secret has no kid, so an unlabelled token must be HS256
+		"alg mismatch without kid": sign(jwt.SigningMethodHS384, "", []byte("secret")),
+	}
+	for name, token := range tests {
+		if _, err := j.ValidateToken(token); err == nil {
+			t.Errorf("%s: ValidateToken() succeeded, want an error", name)
+		}
+	}
+}
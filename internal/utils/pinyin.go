@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+var (
+	// partOfSpeech matches markers such as "n." or "vt." inside a Chinese gloss
+	partOfSpeech = regexp.MustCompile(`\b[a-zA-Z]+\.\s*`)
+	// glossSeparators splits a Chinese definition into individual glosses
+	glossSeparators = regexp.MustCompile(`[；;，,、/]+`)
+	// pinyinQuery matches input that can be compared against pinyin
+	pinyinQuery = regexp.MustCompile(`^[a-zāáǎàēéěèīíǐìōóǒòūúǔùüǖǘǚǜ]+$`)
+)
+
+// GlossPinyin holds the pinyin of a Chinese definition, one space separated
+// entry per gloss so a search can match from the start of any gloss
+type GlossPinyin struct {
+	Plain    string // xuanze tiaoxuan
+	Tone     string // xuǎnzé tiāoxuǎn
+	Initials string // xz tx
+}
+
+// SplitGlosses splits a Chinese definition such as "n. 苹果；苹果树" into glosses
+func SplitGlosses(chinese string) []string {
+	var glosses []string
+	for _, gloss := range glossSeparators.Split(partOfSpeech.ReplaceAllString(chinese, " "), -1) {
+		if gloss = strings.TrimSpace(gloss); gloss != "" {
+			glosses = append(glosses, gloss)
+		}
+	}
+	return glosses
+}
+
+// ChinesePinyin computes the pinyin of each gloss in a Chinese definition.
+// Characters without pinyin, such as punctuation, are skipped.
+func ChinesePinyin(chinese string) GlossPinyin {
+	var plain, tone, initials []string
+	for _, gloss := range SplitGlosses(chinese) {
+		p, t, i := glossPinyin(gloss)
+		if p == "" {
+			continue
+		}
+		plain = append(plain, p)
+		tone = append(tone, t)
+		initials = append(initials, i)
+	}
+	return GlossPinyin{
+		Plain:    strings.Join(plain, " "),
+		Tone:     strings.Join(tone, " "),
+		Initials: strings.Join(initials, " "),
+	}
+}
+
+// MatchPinyinGloss returns the first gloss whose pinyin starts with query
+func MatchPinyinGloss(chinese, query string) (string, bool) {
+	query = NormalizePinyinQuery(query)
+	if query == "" {
+		return "", false
+	}
+	for _, gloss := range SplitGlosses(chinese) {
+		p, t, i := glossPinyin(gloss)
+		if p != "" && (strings.HasPrefix(p, query) || strings.HasPrefix(t, query) || strings.HasPrefix(i, query)) {
+			return gloss, true
+		}
+	}
+	return "", false
+}
+
+// NormalizePinyinQuery lowercases query and drops spaces and apostrophes
+// ("Xuan'ze" becomes "xuanze"). It returns "" when query cannot be pinyin.
+func NormalizePinyinQuery(query string) string {
+	query = strings.ToLower(strings.NewReplacer(" ", "", "'", "", "’", "").Replace(query))
+	if !pinyinQuery.MatchString(query) {
+		return ""
+	}
+	return query
+}
+
+// glossPinyin returns the plain, toned and initial-letter pinyin of one gloss
+func glossPinyin(gloss string) (string, string, string) {
+	args := pinyin.NewArgs()
+	args.Style = pinyin.Tone
+	syllables := pinyin.LazyPinyin(gloss, args)
+	if len(syllables) == 0 {
+		return "", "", ""
+	}
+
+	args.Style = pinyin.Normal
+	plain := pinyin.LazyPinyin(gloss, args)
+	args.Style = pinyin.FirstLetter
+	initials := pinyin.LazyPinyin(gloss, args)
+	return strings.Join(plain, ""), strings.Join(syllables, ""), strings.Join(initials, "")
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitGlosses(t *testing.T) {
+	tests := map[string][]string{
+		"n. 苹果；苹果树":       {"苹果", "苹果树"},
+		"vt. 选择, 挑选 / 选出": {"选择", "挑选", "选出"},
+		"adj. 快的、迅速的":     {"快的", "迅速的"},
+		"；；":              nil,
+	}
+	for chinese, want := range tests {
+		if got := SplitGlosses(chinese); !slices.Equal(got, want) {
+			t.Errorf("SplitGlosses(%q) = %q, want %q", chinese, got, want)
+		}
+	}
+}
+
+func TestChinesePinyin(t *testing.T) {
+	tests := []struct {
+		chinese string
+		want    GlossPinyin
+	}{
+		{"v. 选择；挑选", GlossPinyin{Plain: "xuanze tiaoxuan", Tone: "xuǎnzé tiāoxuǎn", Initials: "xz tx"}},
+		{"n. 苹果", GlossPinyin{Plain: "pingguo", Tone: "píngguǒ", Initials: "pg"}},
+		// Glosses without Chinese characters have no pinyin and are skipped
+		{"n. CPU；中央", GlossPinyin{Plain: "zhongyang", Tone: "zhōngyāng", Initials: "zy"}},
+		{"", GlossPinyin{}},
+	}
+	for _, test := range tests {
+		if got := ChinesePinyin(test.chinese); got != test.want {
+			t.Errorf("ChinesePinyin(%q) = %+v, want %+v", test.chinese, got, test.want)
+		}
+	}
+}
+
+func TestNormalizePinyinQuery(t *testing.T) {
+	tests := map[string]string{
+		"xuanze":     "xuanze",
+		"Xuan Ze":    "xuanze",
+		"xuan'ze":    "xuanze",
+		"xuan’ze":    "xuanze",
+		"XuǍnZé":     "xuǎnzé",
+		"nü":         "nü",
+		"xz":         "xz",
+		"xuan3ze2":   "",
+		"选择":         "",
+		"xuan-ze":    "",
+		"":           "",
+		"   ":        "",
+		"apple pie!": "",
+	}
+	for query, want := range tests {
+		if got := NormalizePinyinQuery(query); got != want {
+			t.Errorf("NormalizePinyinQuery(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestMatchPinyinGloss(t *testing.T) {
+	tests := []struct {
+		query string
+		gloss string
+		ok    bool
+	}{
+		{"tiao", "挑选", true},
+		{"Tiāo Xuǎn", "挑选", true},
+		{"tx", "挑选", true},
+		{"xuanz", "选择", true},
+		{"ze", "", false},
+		{"选择", "", false},
+	}
+	for _, test := range tests {
+		if gloss, ok := MatchPinyinGloss("v. 选择；挑选", test.query); gloss != test.gloss || ok != test.ok {
+			t.Errorf("MatchPinyinGloss(%q) = %q, %v, want %q, %v", test.query, gloss, ok, test.gloss, test.ok)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Package filterql parses compact filter expressions such as
+//
+//	cat:noun known:false len:>8 learned:2024-01-01..2024-02-01 -diff:hard
+//
+// into terms. It only handles syntax; callers map the field names they
+// support onto their own queries and must reject everything else.
+package filterql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Op is the comparison of a term
+type Op string
+
+// Supported comparisons
+const (
+	OpEq    Op = "="
+	OpNe    Op = "!="
+	OpGt    Op = ">"
+	OpGe    Op = ">="
+	OpLt    Op = "<"
+	OpLe    Op = "<="
+	OpRange Op = ".." // inclusive range Value..To
+)
+
+// maxTerms bounds the number of terms in one expression
+const maxTerms = 20
+
+// Term is a single field comparison
+type Term struct {
+	Field string
+	Op    Op
+	Value string
+	// To is the upper bound of an OpRange term
+	To string
+}
+
+// Query is a parsed filter expression
+type Query struct {
+	Terms []Term
+	// Text holds the words that are not field terms, for free text search
+	Text []string
+}
+
+// Parse parses a filter expression. Fields are lowercased; values keep their
+// case. Values containing spaces can be double quoted: cat:"phrasal verb".
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &Query{}
+	for _, token := range tokens {
+		field, value, isTerm := strings.Cut(token.text, ":")
+		if !isTerm || token.quotedField {
+			query.Text = append(query.Text, token.text)
+			continue
+		}
+
+		negate := strings.HasPrefix(field, "-")
+		field = strings.ToLower(strings.TrimPrefix(field, "-"))
+		if !isIdentifier(field) {
+			return nil, fmt.Errorf("invalid filter field %q", field)
+		}
+
+		term, err := parseTerm(field, value, token.quotedValue)
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			if term.Op != OpEq {
+				return nil, fmt.Errorf("filter %q: only equality can be negated", field)
+			}
+			term.Op = OpNe
+		}
+		query.Terms = append(query.Terms, term)
+	}
+
+	if len(query.Terms) > maxTerms {
+		return nil, fmt.Errorf("too many filter terms")
+	}
+	return query, nil
+}
+
+// parseTerm parses the comparison after "field:"
+func parseTerm(field, value string, quoted bool) (Term, error) {
+	term := Term{Field: field, Op: OpEq, Value: value}
+	if !quoted {
+		switch {
+		case strings.HasPrefix(value, ">="):
+			term.Op, term.Value = OpGe, value[2:]
+		case strings.HasPrefix(value, "<="):
+			term.Op, term.Value = OpLe, value[2:]
+		case strings.HasPrefix(value, ">"):
+			term.Op, term.Value = OpGt, value[1:]
+		case strings.HasPrefix(value, "<"):
+			term.Op, term.Value = OpLt, value[1:]
+		case strings.Contains(value, ".."):
+			from, to, _ := strings.Cut(value, "..")
+			if from == "" || to == "" {
+				return term, fmt.Errorf("filter %q: range needs both bounds", field)
+			}
+			term.Op, term.Value, term.To = OpRange, from, to
+		}
+	}
+	if term.Value == "" {
+		return term, fmt.Errorf("filter %q: missing value", field)
+	}
+	return term, nil
+}
+
+// token is a whitespace separated word of the input
+type token struct {
+	text        string
+	quotedField bool
+	quotedValue bool
+}
+
+// tokenize splits input on whitespace, keeping double quoted parts together
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	var current strings.Builder
+	var tok token
+	inQuotes, started := false, false
+
+	flush := func() {
+		if started {
+			tok.text = current.String()
+			tokens = append(tokens, tok)
+		}
+		current.Reset()
+		tok = token{}
+		started = false
+	}
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			if !inQuotes {
+				if strings.Contains(current.String(), ":") {
+					tok.quotedValue = true
+				} else {
+					tok.quotedField = true
+				}
+			}
+			inQuotes = !inQuotes
+			started = true
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	flush()
+	return tokens, nil
+}
+
+// isIdentifier reports whether s is a plain lowercase field name
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package filterql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	query, err := Parse(`cat:noun known:false len:>8 learned:2024-01-01..2024-02-01 -diff:hard len:<=12 run away`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []Term{
+		{Field: "cat", Op: OpEq, Value: "noun"},
+		{Field: "known", Op: OpEq, Value: "false"},
+		{Field: "len", Op: OpGt, Value: "8"},
+		{Field: "learned", Op: OpRange, Value: "2024-01-01", To: "2024-02-01"},
+		{Field: "diff", Op: OpNe, Value: "hard"},
+		{Field: "len", Op: OpLe, Value: "12"},
+	}
+	if !reflect.DeepEqual(query.Terms, expected) {
+		t.Errorf("Unexpected terms:\n got %+v\nwant %+v", query.Terms, expected)
+	}
+	if !reflect.DeepEqual(query.Text, []string{"run", "away"}) {
+		t.Errorf("Unexpected free text: %v", query.Text)
+	}
+}
+
+func TestParseQuotes(t *testing.T) {
+	query, err := Parse(`Cat:"phrasal verb" "a:b" cat:">5"`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// 引号内的值不解析比较运算符，字段名统一小写
+	expected := []Term{
+		{Field: "cat", Op: OpEq, Value: "phrasal verb"},
+		{Field: "cat", Op: OpEq, Value: ">5"},
+	}
+	if !reflect.DeepEqual(query.Terms, expected) {
+		t.Errorf("Unexpected terms:\n got %+v\nwant %+v", query.Terms, expected)
+	}
+	if !reflect.DeepEqual(query.Text, []string{"a:b"}) {
+		t.Errorf("Expected quoted text to stay free text, got %v", query.Text)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	inputs := []string{
+		`cat:`,
+		`len:>`,
+		`learned:2024-01-01..`,
+		`-len:>5`,
+		`c.at:noun`,
+		`cat:"noun`,
+		`a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1 a:1`,
+	}
+	for _, input := range inputs {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Expected Parse(%q) to fail", input)
+		}
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	query, err := Parse("   ")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(query.Terms) != 0 || len(query.Text) != 0 {
+		t.Errorf("Expected empty query, got %+v", query)
+	}
+}
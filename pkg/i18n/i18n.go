@@ -0,0 +1,117 @@
+// Package i18n holds the message catalog of texts shown to API clients, with
+// one bundle per supported language, and picks a language for a request.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+// Supported languages
+const (
+	ZhCN = "zh-CN"
+	EN   = "en"
+
+	// Default is used when a request states no supported language
+	Default = ZhCN
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// bundles maps a language to its messages by key
+var bundles = loadBundles()
+
+func loadBundles() map[string]map[string]string {
+	loaded := make(map[string]map[string]string)
+	for _, lang := range []string{ZhCN, EN} {
+		data, err := localeFiles.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("missing message bundle %s: %v", lang, err))
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("invalid message bundle %s: %v", lang, err))
+		}
+		loaded[lang] = messages
+	}
+	return loaded
+}
+
+// Normalize returns the supported language matching tag, such as "en" for
+// "en-GB" or "zh-CN" for "zh-Hans", or "" if none does
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	primary, _, _ := strings.Cut(tag, "-")
+	switch primary {
+	case "zh":
+		return ZhCN
+	case "en":
+		return EN
+	}
+	return ""
+}
+
+// Match picks the supported language a client prefers from an
+// Accept-Language header, falling back to Default
+func Match(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang := Normalize(tag)
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// T returns the message for key in lang, formatted with args. Keys missing
+// from lang fall back to the default language; unknown keys are returned as
+// they are, so literal texts may be passed where a key is expected.
+func T(lang, key string, args ...interface{}) string {
+	msg, ok := bundles[lang][key]
+	if !ok {
+		if msg, ok = bundles[Default][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// ErrorMessage returns the message of a pke error code in lang. The default
+// language uses the messages defined by pke.
+func ErrorMessage(lang string, code int) string {
+	if msg, ok := bundles[lang]["error."+strconv.Itoa(code)]; ok {
+		return msg
+	}
+	return pke.GetErrorMessage(code)
+}
@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sanmu2018/word-hero/pkg/pke"
+)
+
+func TestMatch(t *testing.T) {
+	cases := map[string]string{
+		"":                             Default,
+		"en-US,en;q=0.9":               EN,
+		"zh-Hans-CN,zh;q=0.9,en;q=0.8": ZhCN,
+		"fr-FR,en;q=0.5,zh-TW;q=0.7":   ZhCN,
+		"fr, de;q=0.8":                 Default,
+		"zh;q=0, en-GB;q=0.1":          EN,
+		"en;q=bad, zh-CN;q=0.3":        ZhCN,
+	}
+	for header, expected := range cases {
+		if lang := Match(header); lang != expected {
+			t.Errorf("Match(%q) = %q, want %q", header, lang, expected)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if msg := T(EN, "word.forgotten", 3); msg != "Forgot 3 known words" {
+		t.Errorf("Unexpected message %q", msg)
+	}
+	if msg := T(ZhCN, "word.forgotten", 3); msg != "已忘光 3 个已认识单词" {
+		t.Errorf("Unexpected message %q", msg)
+	}
+	// 未知的语言使用默认语言，未知的键原样返回
+	if msg := T("fr", "word.marked"); msg != T(Default, "word.marked") {
+		t.Errorf("Expected default language fallback, got %q", msg)
+	}
+	if msg := T(EN, "unknown sort field"); msg != "unknown sort field" {
+		t.Errorf("Expected literal text to pass through, got %q", msg)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	if msg := ErrorMessage(ZhCN, pke.CodeUserNotFound); msg != pke.GetErrorMessage(pke.CodeUserNotFound) {
+		t.Errorf("Expected pke message, got %q", msg)
+	}
+	for code := range pke.ErrorMessages {
+		if msg := ErrorMessage(EN, code); msg == pke.GetErrorMessage(code) {
+			t.Errorf("Missing English message for %s", pke.FormatErrorCode(code))
+		}
+	}
+}
+
+func TestBundlesHaveSameKeys(t *testing.T) {
+	for key := range bundles[EN] {
+		if _, ok := bundles[ZhCN][key]; !ok && !strings.HasPrefix(key, "error.") {
+			t.Errorf("Key %q is missing from %s", key, ZhCN)
+		}
+	}
+	for key := range bundles[ZhCN] {
+		if _, ok := bundles[EN][key]; !ok {
+			t.Errorf("Key %q is missing from %s", key, EN)
+		}
+	}
+}
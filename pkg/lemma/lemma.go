@@ -0,0 +1,145 @@
+// Package lemma maps inflected English words to the headwords they may come
+// from, using an irregular forms table plus suffix rules.
+package lemma
+
+import (
+	"bufio"
+	_ "embed"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//go:embed irregular.txt
+var irregularData string
+
+var (
+	irregularOnce sync.Once
+	irregular     map[string][]string
+
+	// wordPattern matches the single lowercase words Lemmas handles
+	wordPattern = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+)
+
+// rule strips a suffix and appends replacements to form candidate lemmas
+type rule struct {
+	suffix       string
+	replacements []string
+	// undouble also tries removing a doubled final consonant (stopped -> stop)
+	undouble bool
+}
+
+// rules are tried in order; the more specific suffixes come first
+var rules = []rule{
+	{suffix: "ies", replacements: []string{"y", "ie"}},
+	{suffix: "ves", replacements: []string{"f", "fe"}},
+	{suffix: "ses", replacements: []string{"sis", "s", "se"}},
+	{suffix: "xes", replacements: []string{"x"}},
+	{suffix: "zes", replacements: []string{"z", "ze"}},
+	{suffix: "ches", replacements: []string{"ch", "che"}},
+	{suffix: "shes", replacements: []string{"sh", "she"}},
+	{suffix: "oes", replacements: []string{"o", "oe"}},
+	{suffix: "men", replacements: []string{"man"}},
+	{suffix: "s", replacements: []string{""}},
+	{suffix: "ied", replacements: []string{"y", "ie"}},
+	{suffix: "ed", replacements: []string{"", "e"}, undouble: true},
+	{suffix: "ying", replacements: []string{"ie", "y"}},
+	{suffix: "ing", replacements: []string{"", "e"}, undouble: true},
+	{suffix: "ier", replacements: []string{"y"}},
+	{suffix: "iest", replacements: []string{"y"}},
+	{suffix: "er", replacements: []string{"", "e"}, undouble: true},
+	{suffix: "est", replacements: []string{"", "e"}, undouble: true},
+	{suffix: "ily", replacements: []string{"y"}},
+	{suffix: "ly", replacements: []string{"", "le"}},
+}
+
+// minDerivedLength is the shortest headword the suffix rules may produce
+const minDerivedLength = 3
+
+// Lemmas returns the possible headwords of an inflected word, most likely
+// first. Irregular lemmas come from the irregular forms table and are
+// reliable; derived lemmas come from the suffix rules and are only guesses
+// (news -> new), so callers should only accept them as existing headwords
+// and rank them below surer matches. The word itself is not included; words
+// that are not a single English word, or that have no known inflection,
+// return nil.
+func Lemmas(word string) (irregular, derived []string) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if !wordPattern.MatchString(word) {
+		return nil, nil
+	}
+
+	seen := map[string]bool{word: true}
+	for _, lemma := range irregularForms()[word] {
+		if len(lemma) >= 2 && !seen[lemma] {
+			seen[lemma] = true
+			irregular = append(irregular, lemma)
+		}
+	}
+
+	add := func(lemma string) {
+		if plausibleLemma(lemma) && !seen[lemma] {
+			seen[lemma] = true
+			derived = append(derived, lemma)
+		}
+	}
+	for _, r := range rules {
+		stem, ok := strings.CutSuffix(word, r.suffix)
+		if !ok || stem == "" {
+			continue
+		}
+		for _, replacement := range r.replacements {
+			add(stem + replacement)
+		}
+		if r.undouble && hasDoubledConsonant(stem) {
+			add(stem[:len(stem)-1])
+		}
+	}
+	return irregular, derived
+}
+
+// plausibleLemma reports whether a rule-derived lemma can be a word of its
+// own: long enough, and with a vowel besides a silent final "e", so that
+// "bed" does not yield "be" nor "thing" yield "the"
+func plausibleLemma(lemma string) bool {
+	if len(lemma) < minDerivedLength {
+		return false
+	}
+	stem := strings.TrimSuffix(lemma, "e")
+	return strings.ContainsAny(stem, "aeiou") || strings.ContainsRune(stem[1:], 'y')
+}
+
+// hasDoubledConsonant reports whether stem ends in a doubled consonant such
+// as the "pp" of "stopp"
+func hasDoubledConsonant(stem string) bool {
+	n := len(stem)
+	return n >= 3 && stem[n-1] == stem[n-2] && !strings.ContainsRune("aeiouwxy", rune(stem[n-1]))
+}
+
+// irregularForms parses the embedded irregular forms table once
+func irregularForms() map[string][]string {
+	irregularOnce.Do(func() {
+		irregular = make(map[string][]string)
+		scanner := bufio.NewScanner(strings.NewReader(irregularData))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				continue
+			}
+			form, lemma := fields[0], fields[1]
+			for _, existing := range irregular[form] {
+				if existing == lemma {
+					lemma = ""
+				}
+			}
+			if lemma != "" {
+				irregular[form] = append(irregular[form], lemma)
+			}
+		}
+	})
+	return irregular
+}
@@ -0,0 +1,105 @@
+package lemma
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLemmas(t *testing.T) {
+	cases := []struct {
+		word  string
+		lemma string
+	}{
+		// 规则变化
+		{"studied", "study"},
+		{"studies", "study"},
+		{"studying", "study"},
+		{"stopped", "stop"},
+		{"running", "run"},
+		{"hoped", "hope"},
+		{"making", "make"},
+		{"boxes", "box"},
+		{"watches", "watch"},
+		{"cats", "cat"},
+		{"happier", "happy"},
+		{"biggest", "big"},
+		{"quickly", "quick"},
+		{"wolves", "wolf"},
+		{"lying", "lie"},
+		// 不规则变化
+		{"went", "go"},
+		{"analyses", "analysis"},
+		{"children", "child"},
+		{"better", "good"},
+		{"Written", "write"},
+	}
+
+	for _, c := range cases {
+		irregular, derived := Lemmas(c.word)
+		if lemmas := append(irregular, derived...); !slices.Contains(lemmas, c.lemma) {
+			t.Errorf("Lemmas(%q) = %v, want it to contain %q", c.word, lemmas, c.lemma)
+		}
+	}
+}
+
+func TestLemmasIrregularFirst(t *testing.T) {
+	irregular, derived := Lemmas("left")
+	if len(irregular) == 0 || irregular[0] != "leave" {
+		t.Errorf("Expected irregular lemma first, got %v", irregular)
+	}
+	if slices.Contains(derived, "leave") {
+		t.Errorf("Expected irregular lemma only once, got %v", derived)
+	}
+}
+
+func TestLemmasRejectsImplausibleStems(t *testing.T) {
+	cases := []struct {
+		word string
+		not  []string
+	}{
+		{"bed", []string{"be", "b"}},
+		{"feed", []string{"fe"}},
+		{"seed", []string{"se"}},
+		{"thing", []string{"th", "the"}},
+		{"was", []string{"wa"}},
+	}
+
+	for _, c := range cases {
+		irregular, derived := Lemmas(c.word)
+		for _, lemma := range c.not {
+			if slices.Contains(irregular, lemma) || slices.Contains(derived, lemma) {
+				t.Errorf("Lemmas(%q) = %v %v, want no %q", c.word, irregular, derived, lemma)
+			}
+		}
+	}
+}
+
+func TestLemmasRuleGuessesAreDerived(t *testing.T) {
+	// Suffix rules still guess these, but they must rank below surer matches
+	cases := map[string]string{"news": "new", "feed": "fee", "seed": "see"}
+	for word, lemma := range cases {
+		if irregular, derived := Lemmas(word); slices.Contains(irregular, lemma) || !slices.Contains(derived, lemma) {
+			t.Errorf("Lemmas(%q) = %v %v, want %q only as derived", word, irregular, derived, lemma)
+		}
+	}
+	if irregular, _ := Lemmas("was"); !slices.Equal(irregular, []string{"be"}) {
+		t.Errorf("Expected was to map to be through the irregular table, got %v", irregular)
+	}
+}
+
+func TestLemmasIgnoresNonWords(t *testing.T) {
+	for _, word := range []string{"", "a", "new york", "选择", "abc123"} {
+		if irregular, derived := Lemmas(word); len(irregular)+len(derived) != 0 {
+			t.Errorf("Lemmas(%q) = %v %v, want none", word, irregular, derived)
+		}
+	}
+	if irregular, _ := Lemmas("is"); !slices.Contains(irregular, "be") {
+		t.Errorf("Expected short irregular forms to be handled, got %v", irregular)
+	}
+	irregular, derived := Lemmas("studied")
+	for _, lemma := range append(irregular, derived...) {
+		if lemma == "studied" {
+			t.Error("Expected the word itself to be excluded")
+		}
+	}
+}
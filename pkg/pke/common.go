@@ -1,5 +1,7 @@
 package pke
 
+import "time"
+
 type AnyResponse[R any] struct {
 	Code int    `json:"code"`
 	Msg  string `json:"msg"`
@@ -13,11 +15,17 @@ type CommResponse struct {
 type BaseListResp struct {
 	Items interface{} `json:"items"`
 	Total int64       `json:"total"`
+	// NextCursor fetches the next page of a cursor paged list; empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 type APIResponse struct {
 	Code int         `json:"code"`
 	Data interface{} `json:"data,omitempty"`
 	Msg  string      `json:"msg,omitempty"`
+	// Detail 附加在错误消息之后的说明，可以是消息目录中的键
+	Detail string `json:"-"`
+	// DetailArgs 是消息目录中Detail对应消息的参数
+	DetailArgs []interface{} `json:"-"`
 }
 
 func (ar *APIResponse) Error() string {
@@ -31,3 +39,25 @@ func (ar *APIResponse) ErrorNo() int {
 func NewApiError(code int) *APIResponse {
 	return &APIResponse{Code: code, Msg: GetErrorMessage(code)}
 }
+
+// WithDetail 设置错误的附加说明及其参数并返回该错误
+func (ar *APIResponse) WithDetail(detail string, args ...interface{}) *APIResponse {
+	ar.Detail = detail
+	ar.DetailArgs = args
+	ar.Msg = GetErrorMessage(ar.Code) + ": " + detail
+	return ar
+}
+
+// RetryAfterError is an API error telling the client when it may try again
+type RetryAfterError struct {
+	*APIResponse
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.APIResponse
+}
+
+func NewRetryAfterError(code int, retryAfter time.Duration) *RetryAfterError {
+	return &RetryAfterError{APIResponse: NewApiError(code), RetryAfter: retryAfter}
+}
@@ -40,6 +40,11 @@ const (
 	CodePermissionDenied  = 100000203 // 权限不足
 	CodeSessionExpired    = 100000204 // 会话已过期
 	CodeInvalidCredentials = 100000205 // 无效的凭据
+	CodeAccountLocked      = 100000206 // 账户已被临时锁定
+	CodeInvalidTwoFactorCode    = 100000207 // 两步验证码无效
+	CodeTwoFactorSetupRequired  = 100000208 // 需要先启用两步验证
+	CodeTwoFactorAlreadyEnabled = 100000209 // 两步验证已启用
+	CodeTwoFactorNotEnabled     = 100000210 // 两步验证未启用
 
 	// 单词相关错误 (100000300-100000399)
 	CodeWordNotFound      = 100000301 // 单词不存在
@@ -151,6 +156,11 @@ var ErrorMessages = map[int]string{
 	CodePermissionDenied:     "权限不足",
 	CodeSessionExpired:       "会话已过期",
 	CodeInvalidCredentials:   "无效的凭据",
+	CodeAccountLocked:        "登录失败次数过多，账户已被临时锁定",
+	CodeInvalidTwoFactorCode:    "两步验证码无效",
+	CodeTwoFactorSetupRequired:  "管理员账户必须先启用两步验证",
+	CodeTwoFactorAlreadyEnabled: "两步验证已启用",
+	CodeTwoFactorNotEnabled:     "两步验证未启用",
 
 	// 单词相关错误
 	CodeWordNotFound:         "单词不存在",
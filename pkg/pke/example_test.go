@@ -76,6 +76,25 @@ func TestErrorCodes(t *testing.T) {
 	}
 }
 
+func TestHTTPStatus(t *testing.T) {
+	cases := map[int]int{
+		CodeSuccess:           200,
+		CodeInvalidRequest:    400,
+		CodeLoginRequired:     401,
+		CodePermissionDenied:  403,
+		CodeWordNotFound:      404,
+		CodeUserAlreadyExists: 409,
+		CodeAccountLocked:     429,
+		CodeSystemError:       500,
+		CodeInvalidPagination: 400,
+	}
+	for code, expected := range cases {
+		if status := HTTPStatus(code); status != expected {
+			t.Errorf("Expected HTTP status %d for %s, got %d", expected, FormatErrorCode(code), status)
+		}
+	}
+}
+
 // BenchmarkErrorCodes 性能测试
 func BenchmarkErrorCodes(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,86 @@
+package pke
+
+import "net/http"
+
+// httpStatuses 错误码对应的HTTP状态码，未列出的错误码为400
+var httpStatuses = map[int]int{
+	CodeSuccess: http.StatusOK,
+
+	// 401: 未登录或凭据无效
+	CodeUnauthorized:       http.StatusUnauthorized,
+	CodeInvalidToken:       http.StatusUnauthorized,
+	CodeTokenExpired:       http.StatusUnauthorized,
+	CodeAuthFailed:         http.StatusUnauthorized,
+	CodeLoginRequired:      http.StatusUnauthorized,
+	CodeSessionExpired:     http.StatusUnauthorized,
+	CodeInvalidCredentials: http.StatusUnauthorized,
+
+	// 403: 已登录但无权访问
+	CodeForbidden:              http.StatusForbidden,
+	CodePermissionDenied:       http.StatusForbidden,
+	CodeUserDisabled:           http.StatusForbidden,
+	CodeUserNotVerified:        http.StatusForbidden,
+	CodeTwoFactorSetupRequired: http.StatusForbidden,
+
+	// 404: 资源不存在
+	CodeNotFound:        http.StatusNotFound,
+	CodeUserNotFound:    http.StatusNotFound,
+	CodeWordNotFound:    http.StatusNotFound,
+	CodeWordTagNotFound: http.StatusNotFound,
+	CodeFileNotFound:    http.StatusNotFound,
+
+	CodeMethodNotAllowed: http.StatusMethodNotAllowed,
+	CodeRequestTimeout:   http.StatusRequestTimeout,
+	CodeFileTooLarge:     http.StatusRequestEntityTooLarge,
+
+	// 409: 与现有数据冲突
+	CodeUserAlreadyExists:       http.StatusConflict,
+	CodeWordAlreadyExists:       http.StatusConflict,
+	CodeAlreadyMarked:           http.StatusConflict,
+	CodeTwoFactorAlreadyEnabled: http.StatusConflict,
+	CodeDataConflict:            http.StatusConflict,
+	CodeDuplicateEntry:          http.StatusConflict,
+
+	// 429: 请求过于频繁
+	CodeTooManyRequests:   http.StatusTooManyRequests,
+	CodeRateLimitExceeded: http.StatusTooManyRequests,
+	CodeAccountLocked:     http.StatusTooManyRequests,
+
+	// 500: 服务端错误
+	CodeSystemError:          http.StatusInternalServerError,
+	CodeSearchFailed:         http.StatusInternalServerError,
+	CodeMarkFailed:           http.StatusInternalServerError,
+	CodeUnmarkFailed:         http.StatusInternalServerError,
+	CodeForgetFailed:         http.StatusInternalServerError,
+	CodeDatabaseError:        http.StatusInternalServerError,
+	CodeDBConnectionError:    http.StatusInternalServerError,
+	CodeDBQueryError:         http.StatusInternalServerError,
+	CodeDBTransactionError:   http.StatusInternalServerError,
+	CodeFileReadError:        http.StatusInternalServerError,
+	CodeFileWriteError:       http.StatusInternalServerError,
+	CodeNetworkError:         http.StatusInternalServerError,
+	CodeConnectionError:      http.StatusInternalServerError,
+	CodeServiceError:         http.StatusInternalServerError,
+	CodeConfigError:          http.StatusInternalServerError,
+	CodeMissingConfig:        http.StatusInternalServerError,
+	CodeInvalidConfig:        http.StatusInternalServerError,
+	CodeBusinessLogicError:   http.StatusInternalServerError,
+	CodeOperationFailed:      http.StatusInternalServerError,
+	CodeExternalServiceError: http.StatusInternalServerError,
+	CodeCacheError:           http.StatusInternalServerError,
+
+	// 503/504: 服务暂不可用或超时
+	CodeServiceUnavailable:  http.StatusServiceUnavailable,
+	CodeResourceBusy:        http.StatusServiceUnavailable,
+	CodeExternalServiceDown: http.StatusServiceUnavailable,
+	CodeSearchTimeout:       http.StatusGatewayTimeout,
+	CodeTimeoutError:        http.StatusGatewayTimeout,
+}
+
+// HTTPStatus 根据错误码获取HTTP状态码
+func HTTPStatus(code int) int {
+	if status, exists := httpStatuses[code]; exists {
+		return status
+	}
+	return http.StatusBadRequest
+}
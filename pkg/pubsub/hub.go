@@ -0,0 +1,105 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Hub delivers the messages of a broker to the subscribers in this process
+type Hub struct {
+	broker     Broker
+	bufferSize int
+
+	mu   sync.Mutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+// NewHub creates a new hub. Each subscription buffers up to bufferSize
+// messages.
+func NewHub(broker Broker, bufferSize int) *Hub {
+	return &Hub{
+		broker:     broker,
+		bufferSize: bufferSize,
+		subs:       make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Run receives the messages of the broker until ctx is done
+func (h *Hub) Run(ctx context.Context) error {
+	return h.broker.Receive(ctx, h.deliver)
+}
+
+// Publish sends payload to the subscribers of topic on all instances
+func (h *Hub) Publish(ctx context.Context, topic string, payload []byte) error {
+	return h.broker.Publish(ctx, topic, payload)
+}
+
+// Subscribe subscribes to the messages of topic published from now on. The
+// subscription must be closed when it is no longer needed.
+func (h *Hub) Subscribe(topic string) *Subscription {
+	sub := &Subscription{
+		hub:   h,
+		topic: topic,
+		ch:    make(chan []byte, h.bufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[topic] == nil {
+		h.subs[topic] = make(map[*Subscription]struct{})
+	}
+	h.subs[topic][sub] = struct{}{}
+	return sub
+}
+
+// deliver passes a message to the subscribers of its topic without blocking.
+// A subscriber whose buffer is full has fallen behind and is closed, so that
+// it notices the gap instead of silently missing messages.
+func (h *Hub) deliver(topic string, payload []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[topic] {
+		select {
+		case sub.ch <- payload:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// remove removes and closes sub; h.mu must be held
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subs[sub.topic]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.topic)
+	}
+	close(sub.ch)
+}
+
+// Subscription receives the messages of a topic
+type Subscription struct {
+	hub   *Hub
+	topic string
+	ch    chan []byte
+}
+
+// Messages returns the channel of received messages. It is closed when the
+// subscription is closed, including when it fell behind.
+func (s *Subscription) Messages() <-chan []byte {
+	return s.ch
+}
+
+// Close unsubscribes; it may be called more than once
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
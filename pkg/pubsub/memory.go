@@ -0,0 +1,48 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker is an in-process Broker, suitable for single-instance
+// deployments
+type MemoryBroker struct {
+	mu        sync.RWMutex
+	receivers map[int]func(topic string, payload []byte)
+	nextID    int
+}
+
+// NewMemoryBroker creates a new in-memory broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		receivers: make(map[int]func(topic string, payload []byte)),
+	}
+}
+
+// Publish passes payload to the running receivers
+func (b *MemoryBroker) Publish(ctx context.Context, topic string, payload []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, deliver := range b.receivers {
+		deliver(topic, payload)
+	}
+	return nil
+}
+
+// Receive passes the published messages to deliver until ctx is done
+func (b *MemoryBroker) Receive(ctx context.Context, deliver func(topic string, payload []byte)) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.receivers[id] = deliver
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.receivers, id)
+	b.mu.Unlock()
+	return nil
+}
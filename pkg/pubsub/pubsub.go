@@ -0,0 +1,16 @@
+// Package pubsub fans out messages published on a topic to the subscribers of
+// the topic. A Hub delivers messages to the subscribers in this process and a
+// Broker carries them between the instances of a deployment.
+package pubsub
+
+import "context"
+
+// Broker carries published messages to the hubs of all instances.
+// Implementations must be safe for concurrent use.
+type Broker interface {
+	// Publish sends payload on topic to all instances
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Receive passes the messages published by any instance to deliver until
+	// ctx is done
+	Receive(ctx context.Context, deliver func(topic string, payload []byte)) error
+}
@@ -0,0 +1,108 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// runHub runs a hub on a memory broker until the test ends
+func runHub(t *testing.T, bufferSize int) *Hub {
+	t.Helper()
+	broker := NewMemoryBroker()
+	hub := NewHub(broker, bufferSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go hub.Run(ctx)
+
+	// Wait for the hub to receive, or early messages would be lost
+	for deadline := time.Now().Add(time.Second); ; {
+		broker.mu.RLock()
+		running := len(broker.receivers) > 0
+		broker.mu.RUnlock()
+		if running {
+			return hub
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Hub did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, sub *Subscription) (string, bool) {
+	t.Helper()
+	select {
+	case msg, ok := <-sub.Messages():
+		return string(msg), ok
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a message")
+		return "", false
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	ctx := context.Background()
+	hub := runHub(t, 8)
+
+	first := hub.Subscribe("user:1")
+	defer first.Close()
+	second := hub.Subscribe("user:1")
+	defer second.Close()
+	other := hub.Subscribe("user:2")
+	defer other.Close()
+
+	if err := hub.Publish(ctx, "user:1", []byte("marked")); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	for _, sub := range []*Subscription{first, second} {
+		if msg, _ := receive(t, sub); msg != "marked" {
+			t.Errorf("Expected message %q, got %q", "marked", msg)
+		}
+	}
+	select {
+	case msg := <-other.Messages():
+		t.Errorf("Subscriber of another topic received %q", msg)
+	default:
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	hub := runHub(t, 8)
+
+	sub := hub.Subscribe("user:1")
+	sub.Close()
+	sub.Close()
+	if _, ok := receive(t, sub); ok {
+		t.Error("Expected the messages of a closed subscription to be closed")
+	}
+
+	if err := hub.Publish(context.Background(), "user:1", []byte("marked")); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if len(hub.subs) != 0 {
+		t.Errorf("Expected no subscriptions left, got %v", hub.subs)
+	}
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	ctx := context.Background()
+	hub := runHub(t, 2)
+
+	slow := hub.Subscribe("user:1")
+	defer slow.Close()
+	for _, msg := range []string{"1", "2", "3"} {
+		hub.Publish(ctx, "user:1", []byte(msg))
+	}
+
+	// The buffered messages are still received, then the subscription ends
+	for _, want := range []string{"1", "2"} {
+		if msg, ok := receive(t, slow); !ok || msg != want {
+			t.Fatalf("Expected message %q, got %q", want, msg)
+		}
+	}
+	if _, ok := receive(t, slow); ok {
+		t.Error("Expected the subscription to be closed after falling behind")
+	}
+}
@@ -0,0 +1,56 @@
+package pubsub
+
+import (
+	"context"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBroker is a Broker backed by Redis Pub/Sub (or any RESP-compatible
+// server such as Valkey or KeyDB), so messages reach the subscribers of all
+// application instances. Messages published while an instance is
+// disconnected are lost for it.
+type RedisBroker struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisBroker creates a new Redis-backed broker; all channels are
+// namespaced with prefix
+func NewRedisBroker(client redis.UniversalClient, prefix string) *RedisBroker {
+	return &RedisBroker{
+		client:  client,
+		channel: prefix + "pubsub:",
+	}
+}
+
+// Publish sends payload on the channel of topic
+func (b *RedisBroker) Publish(ctx context.Context, topic string, payload []byte) error {
+	return b.client.Publish(ctx, b.channel+topic, payload).Err()
+}
+
+// Receive passes the messages of all topics to deliver until ctx is done. The
+// client reconnects by itself when the connection to Redis is lost.
+func (b *RedisBroker) Receive(ctx context.Context, deliver func(topic string, payload []byte)) error {
+	ps := b.client.PSubscribe(ctx, b.channel+"*")
+	defer ps.Close()
+
+	// Wait for the subscription to be confirmed
+	if _, err := ps.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := ps.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			deliver(strings.TrimPrefix(msg.Channel, b.channel), []byte(msg.Payload))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// LockoutPolicy configures progressive lockout after repeated failures
+type LockoutPolicy struct {
+	MaxFailures  int           // failures allowed before the first lockout
+	Window       time.Duration // failures older than this are forgotten
+	BaseDuration time.Duration // length of the first lockout
+	MaxDuration  time.Duration // upper bound for a single lockout
+}
+
+// Lockout tracks failures per key and locks the key out for progressively
+// longer periods: BaseDuration, then twice that on every further failure
+type Lockout struct {
+	store  Store
+	policy LockoutPolicy
+}
+
+// NewLockout creates a new lockout tracker
+func NewLockout(store Store, policy LockoutPolicy) *Lockout {
+	return &Lockout{
+		store:  store,
+		policy: policy,
+	}
+}
+
+// Check returns the remaining lockout for key, or 0 if it is not locked
+func (l *Lockout) Check(ctx context.Context, key string) (time.Duration, error) {
+	return l.store.TTL(ctx, lockKey(key))
+}
+
+// Fail records a failure for key and returns the lockout it triggered, if any
+func (l *Lockout) Fail(ctx context.Context, key string) (time.Duration, error) {
+	lockFor := l.policy.MaxDuration
+	failures, err := l.store.Incr(ctx, failKey(key), l.policy.Window+lockFor)
+	if err != nil {
+		return 0, err
+	}
+
+	excess := int(failures) - l.policy.MaxFailures
+	if l.policy.MaxFailures <= 0 || excess < 0 {
+		return 0, nil
+	}
+
+	if excess < 32 {
+		if d := l.policy.BaseDuration << excess; d > 0 && d < lockFor {
+			lockFor = d
+		}
+	}
+
+	if err := l.store.Set(ctx, lockKey(key), failures, lockFor); err != nil {
+		return 0, err
+	}
+	return lockFor, nil
+}
+
+// Reset clears failures and any active lockout for key
+func (l *Lockout) Reset(ctx context.Context, key string) error {
+	return l.store.Delete(ctx, failKey(key), lockKey(key))
+}
+
+func failKey(key string) string {
+	return "lockout:fail:" + key
+}
+
+func lockKey(key string) string {
+	return "lockout:lock:" + key
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	tokens  float64
+	counter int64
+	updated time.Time
+	expires time.Time
+}
+
+// MemoryStore is an in-process Store, suitable for single-instance deployments
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Take consumes one token from the bucket identified by key
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	entry := s.get(key, now)
+	if entry == nil {
+		entry = &memoryEntry{tokens: float64(limit.Burst), updated: now}
+		s.entries[key] = entry
+	}
+
+	tokens, result := refill(entry.tokens, now.Sub(entry.updated), limit)
+	entry.tokens = tokens
+	entry.updated = now
+	entry.expires = now.Add(bucketTTL(limit))
+	return result, nil
+}
+
+// Incr increments the counter identified by key and (re)sets its ttl
+func (s *MemoryStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	entry := s.get(key, now)
+	if entry == nil {
+		entry = &memoryEntry{}
+		s.entries[key] = entry
+	}
+	entry.counter++
+	entry.expires = now.Add(ttl)
+	return entry.counter, nil
+}
+
+// Set stores value under key for ttl
+func (s *MemoryStore) Set(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &memoryEntry{counter: value, expires: s.now().Add(ttl)}
+	return nil
+}
+
+// TTL returns the remaining lifetime of key, or 0 if it does not exist
+func (s *MemoryStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry := s.get(key, now)
+	if entry == nil {
+		return 0, nil
+	}
+	return entry.expires.Sub(now), nil
+}
+
+// Delete removes the given keys
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// get returns the live entry for key, dropping it if it has expired
+func (s *MemoryStore) get(key string, now time.Time) *memoryEntry {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if !now.Before(entry.expires) {
+		delete(s.entries, key)
+		return nil
+	}
+	return entry
+}
+
+// sweep periodically removes expired entries so idle keys do not accumulate
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit describes a token bucket: Rate tokens are refilled per second up to Burst
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns a Limit refilling n tokens per minute with the given burst
+func PerMinute(n, burst int) Limit {
+	if burst <= 0 {
+		burst = n
+	}
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store is the state backend shared by token buckets and lockout counters.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Take consumes one token from the bucket identified by key
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Incr increments the counter identified by key and (re)sets its ttl
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value int64, ttl time.Duration) error
+	// TTL returns the remaining lifetime of key, or 0 if it does not exist
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Delete removes the given keys
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// refill computes the bucket state after elapsed time and whether a token can be taken
+func refill(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+	if tokens >= 1 {
+		tokens--
+		return tokens, Result{Allowed: true, Remaining: int(tokens)}
+	}
+
+	var retryAfter time.Duration
+	if limit.Rate > 0 {
+		retryAfter = time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	}
+	return tokens, Result{Allowed: false, RetryAfter: retryAfter}
+}
+
+// bucketTTL returns how long an idle bucket must be kept before it is full again
+func bucketTTL(limit Limit) time.Duration {
+	if limit.Rate <= 0 {
+		return time.Hour
+	}
+	return time.Duration(float64(limit.Burst)/limit.Rate*float64(time.Second)) + time.Second
+}
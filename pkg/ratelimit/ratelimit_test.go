@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestStore(now *time.Time) *MemoryStore {
+	store := NewMemoryStore()
+	store.now = func() time.Time { return *now }
+	return store
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	store := newTestStore(&now)
+	limit := PerMinute(60, 3)
+
+	// 测试突发容量
+	for i := 0; i < 3; i++ {
+		result, _ := store.Take(ctx, "ip:1", limit)
+		if !result.Allowed {
+			t.Fatalf("Expected request %d to be allowed", i+1)
+		}
+	}
+
+	result, _ := store.Take(ctx, "ip:1", limit)
+	if result.Allowed {
+		t.Fatal("Expected request beyond burst to be rejected")
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > time.Second {
+		t.Errorf("Expected retry after within one second, got %v", result.RetryAfter)
+	}
+
+	// 测试不同的key互不影响
+	if result, _ := store.Take(ctx, "ip:2", limit); !result.Allowed {
+		t.Error("Expected other key to be allowed")
+	}
+
+	// 测试令牌补充
+	now = now.Add(time.Second)
+	if result, _ := store.Take(ctx, "ip:1", limit); !result.Allowed {
+		t.Error("Expected request to be allowed after refill")
+	}
+}
+
+func TestLockoutProgressive(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	lockout := NewLockout(newTestStore(&now), LockoutPolicy{
+		MaxFailures:  3,
+		Window:       15 * time.Minute,
+		BaseDuration: time.Minute,
+		MaxDuration:  5 * time.Minute,
+	})
+
+	for i := 0; i < 2; i++ {
+		if d, _ := lockout.Fail(ctx, "alice"); d != 0 {
+			t.Fatalf("Expected no lockout after %d failures, got %v", i+1, d)
+		}
+	}
+
+	expected := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute}
+	for _, want := range expected {
+		if got, _ := lockout.Fail(ctx, "alice"); got != want {
+			t.Errorf("Expected lockout %v, got %v", want, got)
+		}
+	}
+
+	if remaining, _ := lockout.Check(ctx, "alice"); remaining != 5*time.Minute {
+		t.Errorf("Expected remaining lockout 5m, got %v", remaining)
+	}
+
+	now = now.Add(5 * time.Minute)
+	if remaining, _ := lockout.Check(ctx, "alice"); remaining != 0 {
+		t.Errorf("Expected lockout to expire, got %v", remaining)
+	}
+
+	lockout.Fail(ctx, "alice")
+	lockout.Reset(ctx, "alice")
+	if remaining, _ := lockout.Check(ctx, "alice"); remaining != 0 {
+		t.Errorf("Expected reset to clear lockout, got %v", remaining)
+	}
+}
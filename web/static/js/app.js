@@ -207,7 +207,7 @@ function loadPage(pageNumber, pageSize) {
         pageSize = isNaN(pageSize) || pageSize <= 0 ? 12 : pageSize;
     }
 
-    fetch(`/api/words?page=${pageNumber}&pageSize=${pageSize}`)
+    fetch(`/api/v1/words?page=${pageNumber}&pageSize=${pageSize}`)
         .then(response => response.json())
         .then(response => {
             if (response.code === 0) {
@@ -451,7 +451,7 @@ function updatePageInfo() {
 
 // Modal functions
 function showStats() {
-    fetch('/api/stats')
+    fetch('/api/v1/stats')
         .then(response => response.json())
         .then(data => {
             if (data.code === 0) {
@@ -902,12 +902,56 @@ function handleModalSearch() {
         return;
     }
     
-    if (query.length < 2) {
-        updateSearchInfo('输入至少2个字符开始搜索');
+    // Suggest while typing; Enter or the search button runs the full search
+    performModalSuggest(query);
+}
+
+function performModalSuggest(query) {
+    fetch(`/api/v1/suggest?q=${encodeURIComponent(query)}`)
+        .then(response => response.json())
+        .then(data => {
+            // Ignore responses for input the user has already changed
+            if (document.getElementById('searchModalInput').value.trim() !== query) {
+                return;
+            }
+            if (data.code === 0) {
+                displayModalSuggestions(data.data || [], query);
+            } else {
+                showSearchError(data.msg);
+            }
+        })
+        .catch(error => {
+            showSearchError('网络错误: ' + error.message);
+        });
+}
+
+function displayModalSuggestions(suggestions, query) {
+    const resultsContainer = document.getElementById('searchResultsContainer');
+    const resultsList = document.getElementById('searchModalResults');
+    const searchInfo = document.getElementById('searchResultCount');
+
+    resultsContainer.style.display = 'block';
+    resultsList.innerHTML = '';
+
+    if (suggestions.length === 0) {
+        searchInfo.textContent = '按回车进行完整搜索';
         return;
     }
-    
-    performModalSearch(query);
+
+    suggestions.forEach(suggestion => {
+        const resultItem = document.createElement('div');
+        resultItem.className = 'search-result-item';
+        resultItem.innerHTML = `
+            <div class="search-result-word">${escapeHtml(suggestion.english)}</div>
+            <div class="search-result-meaning">${escapeHtml(suggestion.chinese)}</div>
+        `;
+        resultItem.addEventListener('click', () => {
+            document.getElementById('searchModalInput').value = suggestion.english;
+            performModalSearch(suggestion.english);
+        });
+        resultsList.appendChild(resultItem);
+    });
+    searchInfo.textContent = `${suggestions.length} 个建议，按回车进行完整搜索`;
 }
 
 function performModalSearch(query) {
@@ -925,7 +969,8 @@ function performModalSearch(query) {
     `;
     searchInfo.textContent = '正在搜索...';
     
-    fetch(`/api/search?q=${encodeURIComponent(query)}`)
+    // Filters such as known:false in the query need the signed-in user
+    fetch(`/api/v1/search?q=${encodeURIComponent(query)}`, { headers: authHeaders() })
         .then(response => response.json())
         .then(data => {
             if (data.code === 0) {
@@ -945,7 +990,7 @@ function displayModalSearchResults(data, query) {
     
     resultsList.innerHTML = '';
     
-    if (!data.results || !Array.isArray(data.results) || data.results.length === 0) {
+    if (!data.items || !Array.isArray(data.items) || data.items.length === 0) {
         resultsList.innerHTML = `
             <div class="search-results-empty">
                 <i class="fas fa-search"></i>
@@ -954,19 +999,23 @@ function displayModalSearchResults(data, query) {
         `;
         searchInfo.textContent = '没有找到相关词汇';
     } else {
-        data.results.forEach(word => {
+        data.items.forEach(word => {
+            // highlight is escaped by the server, only <mark> is markup
+            const english = word.highlightField === 'english' ? word.highlight : escapeHtml(word.english);
+            const chinese = word.highlightField === 'chinese' ? word.highlight : escapeHtml(word.chinese);
+            const form = word.matchedForm ? `<span class="search-result-form">${escapeHtml(word.matchedForm)} →</span> ` : '';
             const resultItem = document.createElement('div');
             resultItem.className = 'search-result-item';
             resultItem.innerHTML = `
-                <div class="search-result-word">${escapeHtml(word.english)}</div>
-                <div class="search-result-meaning">${escapeHtml(word.chinese)}</div>
+                <div class="search-result-word">${form}${english}</div>
+                <div class="search-result-meaning">${chinese}</div>
             `;
             resultItem.addEventListener('click', () => {
                 closeSearchModal();
             });
             resultsList.appendChild(resultItem);
         });
-        searchInfo.textContent = `找到 ${data.results.length} 个相关词汇`;
+        searchInfo.textContent = `找到 ${data.total} 个相关词汇`;
     }
 }
 
@@ -1492,7 +1541,7 @@ function markWordAsKnown(word) {
     }
 
     // Call API to mark word as known
-    fetch('/api/word-tags/mark', {
+    fetch('/api/v1/word-tags/mark', {
         method: 'POST',
         headers: {
             'Content-Type': 'application/json',
@@ -1534,7 +1583,7 @@ function markWordAsUnknown(word) {
     }
 
     // Call API to unmark word
-    fetch('/api/word-tags/unmark', {
+    fetch('/api/v1/word-tags/unmark', {
         method: 'DELETE',
         headers: {
             'Content-Type': 'application/json',
@@ -1573,27 +1622,18 @@ function markWordAsKnownWithAPI(word, wordId) {
         return;
     }
 
-    const token = getAuthToken();
-    if (!token) {
-        showToast('请先登录后再进行操作', 'error');
-        setTimeout(() => showLoginModal(), 1000);
-        return;
-    }
-
     // Mark this request as pending
     pendingMarkRequests.add(requestKey);
 
-    // Call new API to mark word as known
-    fetch('/api/word-tags/mark', {
+    // Call new API to mark word as known; visitors get a guest session
+    ensureGuestSession()
+    .then(() => fetch('/api/v1/word-tags/mark', {
         method: 'POST',
-        headers: {
-            'Content-Type': 'application/json',
-            'Authorization': `Bearer ${token}`
-        },
+        headers: authHeaders(),
         body: JSON.stringify({
             wordId: wordId
         })
-    })
+    }))
     .then(response => response.json())
     .then(data => {
         // Remove from pending requests
@@ -1619,9 +1659,9 @@ function markWordAsKnownWithAPI(word, wordId) {
 
             // Close the modal after successful operation
             closeWordActionModal();
-        } else if (data.code === 401) {
-            showToast('请先登录后再进行操作', 'error');
-            setTimeout(() => showLoginModal(), 1000);
+        } else if (isAuthError(data)) {
+            localStorage.removeItem('guestSession');
+            showToast('会话已过期，请重试或登录', 'error');
         } else {
             showToast(data.msg || '标记失败', 'error');
         }
@@ -1641,27 +1681,18 @@ function markWordAsUnknownWithAPI(word, wordId) {
         return;
     }
 
-    const token = getAuthToken();
-    if (!token) {
-        showToast('请先登录后再进行操作', 'error');
-        setTimeout(() => showLoginModal(), 1000);
-        return;
-    }
-
     // Mark this request as pending
     pendingMarkRequests.add(requestKey);
 
-    // Call new API to unmark word
-    fetch('/api/word-tags/unmark', {
+    // Call new API to unmark word; visitors get a guest session
+    ensureGuestSession()
+    .then(() => fetch('/api/v1/word-tags/unmark', {
         method: 'DELETE',
-        headers: {
-            'Content-Type': 'application/json',
-            'Authorization': `Bearer ${token}`
-        },
+        headers: authHeaders(),
         body: JSON.stringify({
             wordId: wordId
         })
-    })
+    }))
     .then(response => response.json())
     .then(data => {
         // Remove from pending requests
@@ -1686,9 +1717,9 @@ function markWordAsUnknownWithAPI(word, wordId) {
 
             // Close the modal after successful operation
             closeWordActionModal();
-        } else if (data.code === 401) {
-            showToast('请先登录后再进行操作', 'error');
-            setTimeout(() => showLoginModal(), 1000);
+        } else if (isAuthError(data)) {
+            localStorage.removeItem('guestSession');
+            showToast('会话已过期，请重试或登录', 'error');
         } else {
             showToast(data.msg || '取消标记失败', 'error');
         }
@@ -1727,24 +1758,61 @@ function getAuthToken() {
     return localStorage.getItem('authToken');
 }
 
+// Visitors who are not logged in mark words in a guest session. The guest
+// cookie is HttpOnly, so only a flag is kept in localStorage; the server merges
+// the guest's progress into the account on login or registration.
+function ensureGuestSession() {
+    if (getAuthToken() || localStorage.getItem('guestSession')) {
+        return Promise.resolve();
+    }
+    return fetch('/api/v1/auth/guest', { method: 'POST' })
+    .then(response => response.json())
+    .then(data => {
+        if (data.code !== 0) {
+            throw new Error(data.msg || 'guest session failed');
+        }
+        localStorage.setItem('guestSession', '1');
+        connectEventStream();
+    });
+}
+
+// API error codes meaning the session is missing or no longer valid
+const AUTH_ERROR_CODES = new Set([
+    100000003, // CodeUnauthorized
+    100000106, // CodeInvalidToken
+    100000107, // CodeTokenExpired
+    100000202, // CodeLoginRequired
+    100000204  // CodeSessionExpired
+]);
+
+function isAuthError(data) {
+    return AUTH_ERROR_CODES.has(data.code);
+}
+
+function authHeaders() {
+    const headers = { 'Content-Type': 'application/json' };
+    const token = getAuthToken();
+    if (token) {
+        headers['Authorization'] = `Bearer ${token}`;
+    }
+    return headers;
+}
+
 function loadKnownWordsFromAPI(wordIds = null) {
     // Load from localStorage first for immediate feedback
     loadKnownWords();
 
     const token = getAuthToken();
-    if (!token) {
-        // Not logged in, use localStorage data only
+    if (!token && !localStorage.getItem('guestSession')) {
+        // Not logged in and no guest session, use localStorage data only
         return;
     }
 
     // Try to load from API for accurate data using new endpoint
-    const url = '/api/word-tags/known';
+    const url = '/api/v1/word-tags/known';
     const requestOptions = {
         method: 'POST',
-        headers: {
-            'Content-Type': 'application/json',
-            'Authorization': `Bearer ${token}`
-        }
+        headers: authHeaders()
     };
 
     // Add word IDs to request body if provided
@@ -1779,9 +1847,9 @@ function loadKnownWordsFromAPI(wordIds = null) {
 
             // Update the UI based on API data
             updateKnownWordsStatus();
-        } else if (data.code === 401) {
-            // Token invalid, clear it and continue with localStorage
-            localStorage.removeItem('authToken');
+        } else if (isAuthError(data)) {
+            // Token or guest session invalid, clear it and continue with localStorage
+            localStorage.removeItem(token ? 'authToken' : 'guestSession');
         }
     })
     .catch(error => {
@@ -1853,6 +1921,135 @@ function updateKnownWordsStatus() {
     saveKnownWords();
 }
 
+// Live updates: words marked in other tabs or on other devices are pushed
+// over /api/v1/events. EventSource cannot send the Authorization header, so
+// the stream is read with fetch.
+let eventStream = null; // { credential, controller } of the open stream
+
+function connectEventStream() {
+    const token = getAuthToken();
+    const credential = token || (localStorage.getItem('guestSession') ? 'guest' : null);
+    if (eventStream && eventStream.credential === credential) {
+        return;
+    }
+    if (eventStream) {
+        eventStream.controller.abort();
+        eventStream = null;
+    }
+    if (!credential) {
+        return;
+    }
+
+    const stream = { credential, controller: new AbortController() };
+    eventStream = stream;
+    fetch('/api/v1/events', { headers: authHeaders(), signal: stream.controller.signal })
+    .then(response => {
+        if (response.status === 401 || response.status === 403) {
+            // Not signed in any more; the next login opens a new stream
+            stream.controller.abort();
+            return;
+        }
+        if (!response.ok || !response.body) {
+            throw new Error(`event stream failed: ${response.status}`);
+        }
+        // Catch up on changes made while the stream was closed
+        loadKnownWordsFromAPI();
+        return readEventStream(response.body.getReader());
+    })
+    .catch(() => {})
+    .finally(() => {
+        if (eventStream !== stream) {
+            return;
+        }
+        eventStream = null;
+        if (!stream.controller.signal.aborted) {
+            setTimeout(connectEventStream, 3000);
+        }
+    });
+}
+
+function readEventStream(reader) {
+    const decoder = new TextDecoder();
+    let buffer = '';
+
+    function read() {
+        return reader.read().then(({ done, value }) => {
+            if (done) {
+                return;
+            }
+            buffer += decoder.decode(value, { stream: true });
+            const messages = buffer.split('\n\n');
+            buffer = messages.pop();
+            messages.forEach(message => {
+                const data = message.split('\n')
+                    .filter(line => line.startsWith('data:'))
+                    .map(line => line.substring(5).trim())
+                    .join('\n');
+                if (data) {
+                    handleServerEvent(JSON.parse(data));
+                }
+            });
+            return read();
+        });
+    }
+    return read();
+}
+
+function handleServerEvent(event) {
+    switch (event.type) {
+    case 'word.marked':
+    case 'word.unmarked':
+        applyRemoteMark(event.data.wordId, event.type === 'word.marked');
+        break;
+    case 'words.marked':
+        event.data.wordIds.forEach(wordId => applyRemoteMark(wordId, true));
+        break;
+    case 'words.forgotten':
+        if (event.data.all) {
+            document.querySelectorAll('.vocabulary-card').forEach(card => {
+                applyRemoteMark(card.dataset.wordId, false);
+            });
+            knownWords.clear();
+            wordTimestamps.clear();
+            saveKnownWords();
+            saveWordTimestamps();
+        } else {
+            event.data.wordIds.forEach(wordId => applyRemoteMark(wordId, false));
+        }
+        break;
+    }
+}
+
+// Show a mark made by another client of the same user
+function applyRemoteMark(wordId, isKnown) {
+    if (window.apiKnownWordIds) {
+        if (isKnown) {
+            window.apiKnownWordIds.add(wordId);
+        } else {
+            window.apiKnownWordIds.delete(wordId);
+        }
+    }
+    if (window.apiWordMarkStatuses && window.apiWordMarkStatuses.has(wordId)) {
+        window.apiWordMarkStatuses.get(wordId).isMarked = isKnown;
+    }
+
+    const card = document.querySelector(`.vocabulary-card[data-word-id="${wordId}"]`);
+    if (!card) {
+        return;
+    }
+    const word = card.dataset.word;
+    if (isKnown) {
+        knownWords.add(word);
+    } else {
+        knownWords.delete(word);
+        wordTimestamps.delete(word);
+        saveWordTimestamps();
+    }
+    saveKnownWords();
+    updateWordCardAppearance(word, isKnown);
+    updateInlineMenuButtons(card, word);
+}
+
 function resetKnownWords() {
     // Show reset options modal instead of confirmation dialog
     showResetOptionsModal();
@@ -1909,7 +2106,7 @@ function resetCurrentPage() {
     }
 
     // Call new API to forget specific words
-    fetch('/api/word-tags/forget-words', {
+    fetch('/api/v1/word-tags/forget-words', {
         method: 'POST',
         headers: {
             'Content-Type': 'application/json',
@@ -1953,7 +2150,7 @@ function resetCurrentPage() {
 
             // Refresh current page
             loadPage(currentPage, pageSize);
-        } else if (data.code === 401) {
+        } else if (isAuthError(data)) {
             showToast('请先登录后再进行操作', 'error');
             setTimeout(() => showLoginModal(), 1000);
         } else {
@@ -1976,7 +2173,7 @@ function resetAllPages() {
     // Confirm before forgetting all words
     if (confirm('确定要忘光所有已认识的单词吗？这将清除所有单词的已认识标记。')) {
         // Call new API to forget all words
-        fetch('/api/word-tags/forget-all', {
+        fetch('/api/v1/word-tags/forget-all', {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json',
@@ -2423,6 +2620,90 @@ class AuthManager {
         this.updateAuthUI();
         this.bindAuthEvents();
         this.checkAuthStatus();
+        this.checkEmailVerificationResult();
+        this.checkPasswordResetToken();
+        this.checkOIDCResult();
+        this.loadOIDCProviders();
+    }
+
+    // Show external login buttons for the configured identity providers
+    async loadOIDCProviders() {
+        try {
+            const response = await fetch('/api/v1/auth/oidc/providers');
+            const result = await response.json();
+            if (result.code !== 0 || !result.data || result.data.length === 0) {
+                return;
+            }
+
+            const container = document.getElementById('oidcProviders');
+            container.innerHTML = '';
+            result.data.forEach(provider => {
+                const link = document.createElement('a');
+                link.className = 'btn btn-secondary';
+                link.href = `/api/v1/auth/oidc/${encodeURIComponent(provider.name)}/login`;
+                link.textContent = `使用${provider.display_name}登录`;
+                container.appendChild(link);
+            });
+            container.style.display = 'flex';
+        } catch (error) {
+            // External login is optional
+        }
+    }
+
+    // Finish an external login; the callback passes its result in the URL fragment
+    checkOIDCResult() {
+        const params = new URLSearchParams(window.location.hash.substring(1));
+        const token = params.get('oidcToken');
+        const challenge = params.get('oidcChallenge');
+        const error = params.get('oidcError');
+        if (!token && !challenge && !error) {
+            return;
+        }
+        window.history.replaceState({}, '', window.location.pathname + window.location.search);
+
+        if (error) {
+            this.showNotification('外部账号登录失败', 'error');
+        } else if (challenge) {
+            this.loginChallenge = challenge;
+            showModal('loginTwoFactorModal');
+        } else {
+            this.token = token;
+            localStorage.setItem('authToken', this.token);
+            this.checkAuthStatus().then(() => {
+                this.showNotification('登录成功！', 'success');
+                if (params.get('twoFactorSetup') === '1') {
+                    this.showNotification('管理员账户必须先启用两步验证', 'info');
+                    showTwoFactorModal();
+                }
+            });
+        }
+    }
+
+    // Show the result of clicking the verification link sent by email
+    checkEmailVerificationResult() {
+        const params = new URLSearchParams(window.location.search);
+        const verified = params.get('emailVerified');
+        if (verified === null) {
+            return;
+        }
+        if (verified === '1') {
+            this.showNotification('邮箱验证成功，请登录', 'success');
+        } else {
+            this.showNotification('验证链接无效或已过期', 'error');
+        }
+        window.history.replaceState({}, '', window.location.pathname);
+    }
+
+    // Open the reset form when arriving from a password reset email
+    checkPasswordResetToken() {
+        const params = new URLSearchParams(window.location.search);
+        const token = params.get('resetToken');
+        if (!token) {
+            return;
+        }
+        this.resetToken = token;
+        window.history.replaceState({}, '', window.location.pathname);
+        showModal('resetPasswordModal');
     }
 
     bindAuthEvents() {
@@ -2438,6 +2719,36 @@ class AuthManager {
             this.register();
         });
 
+        // Login 2FA form submission
+        document.getElementById('loginTwoFactorForm').addEventListener('submit', (e) => {
+            e.preventDefault();
+            this.loginTwoFactor();
+        });
+
+        // 2FA enrollment form submission
+        document.getElementById('twoFactorEnableForm').addEventListener('submit', (e) => {
+            e.preventDefault();
+            this.enableTwoFactor();
+        });
+
+        // 2FA disable form submission
+        document.getElementById('twoFactorDisableForm').addEventListener('submit', (e) => {
+            e.preventDefault();
+            this.disableTwoFactor();
+        });
+
+        // Forgot password form submission
+        document.getElementById('forgotPasswordForm').addEventListener('submit', (e) => {
+            e.preventDefault();
+            this.forgotPassword();
+        });
+
+        // Reset password form submission
+        document.getElementById('resetPasswordForm').addEventListener('submit', (e) => {
+            e.preventDefault();
+            this.resetPassword();
+        });
+
         // Profile form submission
         document.getElementById('profileForm').addEventListener('submit', (e) => {
             e.preventDefault();
@@ -2456,7 +2767,7 @@ class AuthManager {
         const password = document.getElementById('loginPassword').value;
 
         try {
-            const response = await fetch('/api/auth/login', {
+            const response = await fetch('/api/v1/auth/login', {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
@@ -2466,14 +2777,14 @@ class AuthManager {
 
             const result = await response.json();
 
-            if (result.code === 0) {
-                this.token = result.data.token;
-                this.user = result.data.user;
-                localStorage.setItem('authToken', this.token);
-                localStorage.setItem('currentUser', JSON.stringify(this.user));
-                this.updateAuthUI();
-                this.showNotification('登录成功！', 'success');
+            if (result.code === 0 && result.data.twoFactorRequired) {
+                // Password accepted, ask for the second factor
+                this.loginChallenge = result.data.challengeToken;
                 closeModal('loginModal');
+                showModal('loginTwoFactorModal');
+            } else if (result.code === 0) {
+                closeModal('loginModal');
+                this.finishLogin(result.data);
             } else {
                 this.showNotification(result.msg || '登录失败', 'error');
             }
@@ -2482,6 +2793,188 @@ class AuthManager {
         }
     }
 
+    async loginTwoFactor() {
+        const code = document.getElementById('loginTwoFactorCode').value;
+
+        try {
+            const response = await fetch('/api/v1/auth/login/2fa', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify({ challenge_token: this.loginChallenge, code })
+            });
+
+            const result = await response.json();
+
+            if (result.code === 0) {
+                this.loginChallenge = null;
+                closeModal('loginTwoFactorModal');
+                document.getElementById('loginTwoFactorForm').reset();
+                this.finishLogin(result.data);
+            } else {
+                this.showNotification(result.msg || '验证失败', 'error');
+            }
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
+    finishLogin(data) {
+        this.token = data.token;
+        this.user = data.user;
+        localStorage.setItem('authToken', this.token);
+        localStorage.setItem('currentUser', JSON.stringify(this.user));
+        // The server has merged any guest progress into the account
+        localStorage.removeItem('guestSession');
+        this.updateAuthUI();
+        this.showNotification('登录成功！', 'success');
+
+        if (data.twoFactorSetupRequired) {
+            this.showNotification('管理员账户必须先启用两步验证', 'info');
+            showTwoFactorModal();
+        }
+    }
+
+    async loadTwoFactor() {
+        document.getElementById('twoFactorSetup').style.display = 'none';
+        document.getElementById('twoFactorRecoveryCodes').style.display = 'none';
+        document.getElementById('twoFactorManage').style.display = 'none';
+
+        try {
+            const response = await fetch('/api/v1/auth/2fa', {
+                headers: {
+                    'Authorization': `Bearer ${this.token}`
+                }
+            });
+            const result = await response.json();
+            if (result.code !== 0) {
+                this.showNotification(result.msg || '获取两步验证状态失败', 'error');
+                return;
+            }
+
+            const status = document.getElementById('twoFactorStatus');
+            if (result.data.enabled) {
+                status.textContent = `两步验证已启用，剩余 ${result.data.recovery_codes_remaining} 个恢复码。`;
+                document.getElementById('twoFactorManage').style.display = 'block';
+            } else {
+                status.textContent = result.data.required
+                    ? '管理员账户必须启用两步验证后才能继续使用。'
+                    : '两步验证未启用。启用后登录时需要输入身份验证器中的验证码。';
+                await this.setupTwoFactor();
+            }
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
+    async setupTwoFactor() {
+        const response = await fetch('/api/v1/auth/2fa/setup', {
+            method: 'POST',
+            headers: {
+                'Authorization': `Bearer ${this.token}`
+            }
+        });
+        const result = await response.json();
+        if (result.code !== 0) {
+            this.showNotification(result.msg || '生成密钥失败', 'error');
+            return;
+        }
+
+        document.getElementById('twoFactorQRCode').src = result.data.qr_code;
+        document.getElementById('twoFactorSecret').textContent = result.data.secret;
+        document.getElementById('twoFactorSetup').style.display = 'block';
+    }
+
+    async enableTwoFactor() {
+        const code = document.getElementById('twoFactorEnableCode').value;
+
+        try {
+            const response = await fetch('/api/v1/auth/2fa/enable', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                    'Authorization': `Bearer ${this.token}`
+                },
+                body: JSON.stringify({ code })
+            });
+            const result = await response.json();
+
+            if (result.code === 0) {
+                document.getElementById('twoFactorEnableForm').reset();
+                document.getElementById('twoFactorSetup').style.display = 'none';
+                document.getElementById('twoFactorStatus').textContent = '两步验证已启用。';
+                this.showRecoveryCodes(result.data.recovery_codes);
+                this.showNotification('两步验证已启用', 'success');
+            } else {
+                this.showNotification(result.msg || '验证码错误', 'error');
+            }
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
+    async disableTwoFactor() {
+        const password = document.getElementById('twoFactorDisablePassword').value;
+        const code = document.getElementById('twoFactorDisableCode').value;
+
+        try {
+            const response = await fetch('/api/v1/auth/2fa/disable', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                    'Authorization': `Bearer ${this.token}`
+                },
+                body: JSON.stringify({ password, code })
+            });
+            const result = await response.json();
+
+            if (result.code === 0) {
+                document.getElementById('twoFactorDisableForm').reset();
+                this.showNotification('两步验证已关闭', 'success');
+                closeModal('twoFactorModal');
+            } else {
+                this.showNotification(result.msg || '关闭失败', 'error');
+            }
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
+    async regenerateRecoveryCodes() {
+        const code = document.getElementById('twoFactorDisableCode').value;
+        if (!code) {
+            this.showNotification('请先输入身份验证器中的验证码', 'error');
+            return;
+        }
+
+        try {
+            const response = await fetch('/api/v1/auth/2fa/recovery-codes', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                    'Authorization': `Bearer ${this.token}`
+                },
+                body: JSON.stringify({ code })
+            });
+            const result = await response.json();
+
+            if (result.code === 0) {
+                document.getElementById('twoFactorDisableForm').reset();
+                this.showRecoveryCodes(result.data.recovery_codes);
+            } else {
+                this.showNotification(result.msg || '验证码错误', 'error');
+            }
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
+    showRecoveryCodes(codes) {
+        document.getElementById('twoFactorRecoveryCodeList').textContent = codes.join('\n');
+        document.getElementById('twoFactorRecoveryCodes').style.display = 'block';
+    }
+
     async register() {
         const formData = {
             username: document.getElementById('registerUsername').value,
@@ -2497,7 +2990,7 @@ class AuthManager {
         }
 
         try {
-            const response = await fetch('/api/auth/register', {
+            const response = await fetch('/api/v1/auth/register', {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
@@ -2508,6 +3001,13 @@ class AuthManager {
             const result = await response.json();
 
             if (result.code === 0) {
+                // The server has merged any guest progress into the new account
+                localStorage.removeItem('guestSession');
+            }
+            if (result.code === 0 && result.data.emailVerificationRequired) {
+                this.showNotification('注册成功！请查收邮件完成邮箱验证后登录', 'success');
+                closeModal('registerModal');
+            } else if (result.code === 0) {
                 this.token = result.data.token;
                 this.user = result.data.user;
                 localStorage.setItem('authToken', this.token);
@@ -2523,14 +3023,81 @@ class AuthManager {
         }
     }
 
+    async forgotPassword() {
+        const email = document.getElementById('forgotPasswordEmail').value;
+
+        try {
+            const response = await fetch('/api/v1/auth/forgot-password', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify({ email })
+            });
+
+            const result = await response.json();
+
+            if (result.code === 0) {
+                this.showNotification('如果该邮箱已注册，重置链接已发送，请查收邮件', 'success');
+                closeModal('forgotPasswordModal');
+                document.getElementById('forgotPasswordForm').reset();
+            } else {
+                this.showNotification(result.msg || '发送失败', 'error');
+            }
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
+    async resetPassword() {
+        const newPassword = document.getElementById('resetNewPassword').value;
+        const confirmPassword = document.getElementById('resetConfirmPassword').value;
+
+        if (newPassword !== confirmPassword) {
+            this.showNotification('两次输入的密码不一致', 'error');
+            return;
+        }
+
+        try {
+            const response = await fetch('/api/v1/auth/reset-password', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify({ token: this.resetToken, new_password: newPassword })
+            });
+
+            const result = await response.json();
+
+            if (result.code === 0) {
+                this.resetToken = null;
+                // All previous sessions were revoked, including this browser's
+                this.token = null;
+                this.user = null;
+                localStorage.removeItem('authToken');
+                localStorage.removeItem('currentUser');
+                this.updateAuthUI();
+                this.showNotification('密码已重置，请使用新密码登录', 'success');
+                closeModal('resetPasswordModal');
+                document.getElementById('resetPasswordForm').reset();
+                showLoginModal();
+            } else {
+                this.showNotification(result.msg || '重置失败，链接可能已失效', 'error');
+            }
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
     async updateProfile() {
         const formData = {
             full_name: document.getElementById('profileFullName').value,
-            bio: document.getElementById('profileBio').value
+            bio: document.getElementById('profileBio').value,
+            language: document.getElementById('profileLanguage').value
         };
 
         try {
-            const response = await fetch('/api/auth/profile', {
+            const response = await fetch('/api/v1/auth/profile', {
                 method: 'PUT',
                 headers: {
                     'Content-Type': 'application/json',
@@ -2568,7 +3135,7 @@ class AuthManager {
         }
 
         try {
-            const response = await fetch('/api/auth/change-password', {
+            const response = await fetch('/api/v1/auth/change-password', {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
@@ -2591,6 +3158,60 @@ class AuthManager {
         }
     }
 
+    async exportData() {
+        try {
+            const response = await fetch('/api/v1/user/export', {
+                headers: { 'Authorization': `Bearer ${this.token}` }
+            });
+            if (!response.ok) {
+                const result = await response.json();
+                this.showNotification(result.msg || '导出失败', 'error');
+                return;
+            }
+
+            const blob = await response.blob();
+            const link = document.createElement('a');
+            link.href = URL.createObjectURL(blob);
+            link.download = `word-hero-export-${new Date().toISOString().slice(0, 10)}.zip`;
+            link.click();
+            URL.revokeObjectURL(link.href);
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
+    async deleteAccount() {
+        const confirmName = prompt(`删除后账户将立即停用，并在宽限期后永久清除全部数据。\n请输入用户名「${this.user.username}」确认：`);
+        if (confirmName === null) {
+            return;
+        }
+        const password = prompt('请输入密码（通过第三方登录的账户可留空）：');
+        if (password === null) {
+            return;
+        }
+
+        try {
+            const response = await fetch('/api/v1/user/delete', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                    'Authorization': `Bearer ${this.token}`
+                },
+                body: JSON.stringify({ confirm: confirmName, password })
+            });
+            const result = await response.json();
+            if (result.code === 0) {
+                closeModal('profileModal');
+                this.logout();
+                this.showNotification('账户已删除', 'info');
+            } else {
+                this.showNotification(result.msg || '删除失败', 'error');
+            }
+        } catch (error) {
+            this.showNotification('网络错误，请重试', 'error');
+        }
+    }
+
     logout() {
         this.token = null;
         this.user = null;
@@ -2613,13 +3234,16 @@ class AuthManager {
             loginSection.style.display = 'flex';
             userSection.style.display = 'none';
         }
+
+        // Follow the changes of the signed in user, or of the guest
+        connectEventStream();
     }
 
     async checkAuthStatus() {
         if (!this.token) return;
 
         try {
-            const response = await fetch('/api/auth/me', {
+            const response = await fetch('/api/v1/auth/me', {
                 headers: {
                     'Authorization': `Bearer ${this.token}`
                 }
@@ -2664,12 +3288,17 @@ function showRegisterModal() {
     showModal('registerModal');
 }
 
+function showForgotPasswordModal() {
+    showModal('forgotPasswordModal');
+}
+
 function showProfileModal() {
     if (authManager.user) {
         document.getElementById('profileUsername').value = authManager.user.username;
         document.getElementById('profileEmail').value = authManager.user.email;
         document.getElementById('profileFullName').value = authManager.user.full_name || '';
         document.getElementById('profileBio').value = authManager.user.bio || '';
+        document.getElementById('profileLanguage').value = authManager.user.language || 'auto';
         showModal('profileModal');
     }
 }
@@ -2678,6 +3307,11 @@ function showChangePasswordModal() {
     showModal('changePasswordModal');
 }
 
+function showTwoFactorModal() {
+    showModal('twoFactorModal');
+    authManager.loadTwoFactor();
+}
+
 function logout() {
     if (confirm('确定要退出登录吗？')) {
         authManager.logout();